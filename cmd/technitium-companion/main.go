@@ -3,6 +3,7 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
 	"os"
@@ -54,6 +55,8 @@ func run() error {
 		slog.String("build_date", BuildDate),
 		slog.String("log_level", cfg.LogLevel),
 		slog.Bool("dry_run", cfg.DryRun),
+		slog.Bool("orphan_cleanup", cfg.OrphanCleanup),
+		slog.String("target_ip_mode", cfg.TargetIPMode),
 	)
 
 	// Initialize Prometheus metrics
@@ -64,52 +67,177 @@ func run() error {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
-	// Initialize Docker client
-	dockerClient, err := docker.NewClient(ctx, cfg.DockerHost, docker.WithLogger(logger))
-	if err != nil {
-		return fmt.Errorf("creating docker client: %w", err)
+	// Initialize one Docker client per host. A host that is unreachable at startup
+	// is reported unhealthy and retried, rather than stopping the others.
+	var dockerClients []*docker.Client
+	for _, endpoint := range cfg.Endpoints() {
+		hostLogger := logger.With(slog.String("docker_host", endpoint.Name))
+
+		opts := []docker.ClientOption{
+			docker.WithName(endpoint.Name),
+			docker.WithLogger(hostLogger),
+			docker.WithDeferredDetection(),
+		}
+		if endpoint.Mode != "" && endpoint.Mode != "auto" {
+			opts = append(opts, docker.WithMode(docker.Mode(endpoint.Mode)))
+		}
+		if endpoint.CertPath != "" {
+			tlsConfig, err := docker.NewTLSConfig(endpoint.CertPath, endpoint.TLSVerify)
+			if err != nil {
+				return fmt.Errorf("configuring TLS for docker host %s: %w", endpoint.Name, err)
+			}
+			if !endpoint.TLSVerify {
+				hostLogger.Warn("TLS certificate verification is disabled for docker host")
+			}
+			opts = append(opts, docker.WithTLSConfig(tlsConfig))
+		}
+
+		dockerClient, err := docker.NewClient(ctx, endpoint.Host, opts...)
+		if err != nil {
+			return fmt.Errorf("creating docker client for host %s: %w", endpoint.Name, err)
+		}
+		defer dockerClient.Close()
+		dockerClients = append(dockerClients, dockerClient)
+
+		hostLogger.Info("docker client connected",
+			slog.String("mode", string(dockerClient.Mode())),
+			slog.String("host", endpoint.Host),
+			slog.Any("target_ips", cfg.TargetsFor(endpoint.Name)),
+		)
 	}
-	defer dockerClient.Close()
 
-	logger.Info("docker client connected",
-		slog.String("mode", string(dockerClient.Mode())),
-		slog.String("host", cfg.DockerHost),
-	)
+	// Initialize one Technitium client per server
+	var techClients []*technitium.Client
+	var secrets []string
+	for _, server := range cfg.Servers() {
+		serverLogger := logger.With(slog.String("server", server.Name))
 
-	// Initialize Technitium client
-	techClient := technitium.NewClient(
-		cfg.TechnitiumURL,
-		cfg.TechnitiumToken,
-		technitium.WithLogger(logger),
-	)
+		tlsConfig, err := technitium.NewTLSConfig(technitium.TLSOptions{
+			CACert:             []byte(server.TLS.CACert),
+			ClientCert:         []byte(server.TLS.ClientCert),
+			ClientKey:          []byte(server.TLS.ClientKey),
+			ServerName:         server.TLS.ServerName,
+			InsecureSkipVerify: server.TLS.InsecureSkipVerify,
+		})
+		if err != nil {
+			return fmt.Errorf("configuring TLS for technitium server %s: %w", server.Name, err)
+		}
+		if server.TLS.InsecureSkipVerify {
+			serverLogger.Warn("TLS certificate verification is disabled for technitium server")
+		}
 
-	logger.Info("technitium client configured",
-		slog.String("url", cfg.TechnitiumURL),
-		slog.String("zone", cfg.TechnitiumZone),
-		slog.String("target_ip", cfg.TargetIP),
-	)
+		techClients = append(techClients, technitium.NewClient(
+			server.URL,
+			server.Token,
+			technitium.WithName(server.Name),
+			technitium.WithLogger(serverLogger),
+			technitium.WithCredentials(server.Username, server.Password),
+			technitium.WithTLSConfig(tlsConfig),
+			technitium.WithRetryPolicy(technitium.RetryPolicy{
+				MaxAttempts: cfg.RetryMaxAttempts,
+				BaseDelay:   cfg.RetryBaseDelay,
+				MaxDelay:    cfg.RetryMaxDelay,
+			}),
+			technitium.WithCircuitBreaker(cfg.BreakerThreshold, cfg.BreakerCooldown),
+			technitium.WithRateLimit(cfg.RateLimit, cfg.RateBurst),
+			technitium.WithMaxInFlight(cfg.MaxInFlight),
+		))
+		secrets = append(secrets, server.Token, server.Password, server.TLS.ClientKey)
+
+		authMode := "token"
+		if server.Username != "" {
+			authMode = "credentials"
+		}
+
+		serverLogger.Info("technitium client configured",
+			slog.String("url", server.URL),
+			slog.String("auth", authMode),
+			slog.Bool("custom_ca", server.TLS.CACert != ""),
+			slog.Bool("client_cert", server.TLS.ClientCert != ""),
+			slog.Any("zones", cfg.TechnitiumZones),
+			slog.Any("target_ips", cfg.Targets()),
+		)
+	}
 
 	// Initialize Traefik parser
-	parser := traefik.NewParser(traefik.WithLogger(logger))
+	ruleSyntax, _ := traefik.ParseSyntax(cfg.RuleSyntax) // validated by config.Load
+	constraints, err := traefik.ParseConstraints(cfg.TraefikConstraints)
+	if err != nil {
+		return fmt.Errorf("parsing TRAEFIK_CONSTRAINTS: %w", err)
+	}
+	parser := traefik.NewParser(
+		traefik.WithLogger(logger),
+		traefik.WithSyntax(ruleSyntax),
+		traefik.WithExposedByDefault(cfg.TraefikExposedByDefault),
+		traefik.WithConstraints(constraints),
+	)
 
 	// Initialize reconciler
-	rec := reconciler.New(cfg, dockerClient, parser, techClient, reconciler.WithLogger(logger))
+	rec := reconciler.New(cfg, dockerClients, parser, techClients, reconciler.WithLogger(logger))
 
 	// Initialize health server
-	healthServer := health.New(cfg.HealthPort, health.WithLogger(logger), health.WithVersion(Version))
+	healthServer := health.New(cfg.HealthPort,
+		health.WithLogger(logger),
+		health.WithVersion(Version),
+		health.WithRedactedSecrets(secrets...),
+	)
 
 	// Register health checkers
-	healthServer.RegisterChecker("docker", func(ctx context.Context) error {
-		return dockerClient.Ping(ctx)
-	})
-	healthServer.RegisterChecker("technitium", func(ctx context.Context) error {
-		// Simple check - try to get records for a non-existent hostname
-		// This verifies API connectivity without modifying anything
-		_, err := techClient.GetRecords(ctx, cfg.TechnitiumZone, "_health-check.invalid")
-		// Ignore "record not found" errors - we just want to verify API is reachable
-		// The API returns success with empty records if the hostname doesn't exist
-		return err
-	})
+	for _, dockerClient := range dockerClients {
+		name := "docker"
+		if dockerClient.Name() != config.DefaultDockerHostName {
+			name += ":" + dockerClient.Name()
+		}
+		healthServer.RegisterChecker(name, dockerClient.Ping)
+	}
+	for _, techClient := range techClients {
+		// Each server is its own component, so one being down is visible on its own
+		name := "technitium"
+		if techClient.Name() != config.DefaultServerName {
+			name += ":" + techClient.Name()
+		}
+		healthServer.RegisterChecker(name, func(ctx context.Context) error {
+			// Report an open breaker without waiting for its cooldown; once half-open,
+			// the check below doubles as the probe request
+			if techClient.BreakerState() == technitium.BreakerOpen {
+				return fmt.Errorf("%w: API requests suspended after repeated failures", technitium.ErrCircuitOpen)
+			}
+
+			// Simple check - try to get records for a non-existent hostname in each zone
+			// This verifies API connectivity without modifying anything
+			for _, zone := range cfg.TechnitiumZones {
+				// The API returns success with empty records if the hostname doesn't exist
+				if _, err := techClient.GetRecords(ctx, zone, "_health-check."+zone); err != nil {
+					// Another request holds the probe slot of a half-open breaker
+					if errors.Is(err, technitium.ErrCircuitOpen) && techClient.BreakerState() == technitium.BreakerHalfOpen {
+						break
+					}
+					return err
+				}
+			}
+
+			// A successful probe closes the breaker; until then the server is on trial
+			if techClient.BreakerState() == technitium.BreakerHalfOpen {
+				return health.Degraded("circuit breaker half-open: probing the API after repeated failures")
+			}
+			return nil
+		})
+	}
+
+	// Validate the configured zones before touching any records. Unreachable
+	// servers are skipped unless none can be reached. In unready mode the zones
+	// checker keeps /ready failing until they are available.
+	if cfg.ZoneCheck != config.ZoneCheckOff {
+		if err := rec.EnsureZones(ctx); err != nil {
+			if cfg.ZoneCheck == config.ZoneCheckFail {
+				return fmt.Errorf("validating zones: %w", err)
+			}
+			logger.Error("zone validation failed, staying unready until the zones are available",
+				slog.String("error", err.Error()),
+			)
+		}
+		healthServer.RegisterChecker("zones", rec.CheckZones)
+	}
 
 	// Start health server
 	healthErrCh := healthServer.Start()
@@ -127,7 +255,10 @@ func run() error {
 			logger.Info("startup reconciliation complete",
 				slog.Int("workloads_scanned", result.WorkloadsScanned),
 				slog.Int("records_created", result.RecordsCreated),
+				slog.Int("records_updated", result.RecordsUpdated),
 				slog.Int("records_existed", result.RecordsExisted),
+				slog.Int("records_deleted", result.RecordsDeleted),
+				slog.Int("records_owned", len(result.OwnedRecords)),
 				slog.Int("errors", len(result.Errors)),
 			)
 		}
@@ -136,24 +267,29 @@ func run() error {
 	// Mark as ready after startup reconciliation
 	healthServer.SetReady(true)
 
-	// Initialize and start event watcher
-	eventWatcher := watcher.New(
-		cfg,
-		dockerClient.RawClient(),
-		dockerClient.Mode(),
-		parser,
-		rec,
-		watcher.WithLogger(logger),
-	)
+	// Initialize and start one event watcher per Docker host. Each watcher
+	// reconnects on its own, so a failing host never stops the others.
+	for _, dockerClient := range dockerClients {
+		eventWatcher := watcher.New(
+			cfg,
+			dockerClient.RawClient(),
+			dockerClient.Mode(),
+			parser,
+			rec,
+			watcher.WithLogger(logger.With(slog.String("docker_host", dockerClient.Name()))),
+			watcher.WithHost(dockerClient.Name()),
+			watcher.WithConnect(dockerClient.Connect),
+		)
 
-	// Channel to receive watcher errors
-	watcherErrCh := make(chan error, 1)
-	go func() {
-		if err := eventWatcher.Watch(ctx); err != nil && err != context.Canceled {
-			watcherErrCh <- err
-		}
-		close(watcherErrCh)
-	}()
+		go eventWatcher.Watch(ctx)
+	}
+
+	// Reconcile when Traefik moves to other nodes in node target IP mode, or when
+	// workloads published at their network address get new ones. Without either
+	// the check finds nothing to compare.
+	if cfg.TargetIPRefresh > 0 {
+		go rec.WatchTargets(ctx, cfg.TargetIPRefresh)
+	}
 
 	logger.Info("technitium-companion running",
 		slog.Int("health_port", cfg.HealthPort),
@@ -171,10 +307,6 @@ func run() error {
 		if err != nil {
 			logger.Error("health server error", slog.String("error", err.Error()))
 		}
-	case err := <-watcherErrCh:
-		if err != nil {
-			logger.Error("event watcher error", slog.String("error", err.Error()))
-		}
 	}
 
 	// Graceful shutdown
@@ -3,38 +3,125 @@ package config
 
 import (
 	"fmt"
+	"math"
 	"net"
 	"os"
 	"regexp"
 	"strconv"
 	"strings"
+	"time"
 )
 
 // Config holds the application configuration.
 type Config struct {
-	// Technitium DNS settings
+	// Technitium DNS settings. TechnitiumURL and the credentials and TLS settings
+	// below belong to the first of TechnitiumServers.
 	TechnitiumURL   string
 	TechnitiumToken string
-	TechnitiumZone  string
-
-	// Target IP for DNS records
-	TargetIP string
+	TechnitiumZone  string   // First configured zone, kept for single-zone callers
+	TechnitiumZones []string // All managed zones; hostnames are assigned by longest suffix
+
+	// Username and password to log in with instead of a static token
+	TechnitiumUsername string
+	TechnitiumPassword string
+
+	// TLS settings for HTTPS connections to Technitium
+	TechnitiumTLS TLSConfig
+
+	// Independent Technitium servers that each receive every record, for
+	// redundancy without zone transfers
+	TechnitiumServers []TechnitiumServer
+
+	// Zone validation at startup: "fail" exits on a missing or disabled zone,
+	// "unready" keeps the readiness probe failing until it is fixed, "off" skips it.
+	// With ZoneAutoCreate, missing zones are created as Primary zones instead.
+	ZoneCheck       string
+	ZoneAutoCreate  bool
+	ZoneSOA         ZoneSOA
+	ZoneNameServers []string
+
+	// Technitium API resilience: transient failures are retried with exponential
+	// backoff, and the circuit breaker suspends requests after BreakerThreshold
+	// consecutive failures (0 disables it).
+	RetryMaxAttempts int
+	RetryBaseDelay   time.Duration
+	RetryMaxDelay    time.Duration
+	BreakerThreshold int
+	BreakerCooldown  time.Duration
+
+	// Technitium API throttling per server: a token bucket of RateLimit requests per
+	// second with bursts of RateBurst (0 disables it), and at most MaxInFlight
+	// requests awaiting a response (0 removes the cap)
+	RateLimit   float64
+	RateBurst   int
+	MaxInFlight int
+
+	// Target IPs for DNS records. IPv4 addresses produce A records and IPv6
+	// addresses AAAA records. TargetIP is the first entry.
+	TargetIP  string
+	TargetIPs []string
+
+	// Target IP mode: "static" uses the configured target IPs, "node" derives them
+	// from the swarm nodes running TraefikService, read from their TargetIPNodeLabel
+	// label or their swarm address, or from a standalone host's TargetIPInterface or
+	// host name. "network" points each workload's records at its own address on
+	// TargetIPNetwork. Derived and network addresses are checked for changes every
+	// TargetIPRefresh.
+	TargetIPMode      string
+	TraefikService    string
+	TargetIPNodeLabel string
+	TargetIPInterface string
+	TargetIPRefresh   time.Duration
+	TargetIPNetwork   string
+
+	// Record mode: "address" writes A/AAAA records to the target IPs, "cname"
+	// points every hostname at CNAMETarget instead.
+	RecordMode  string
+	CNAMETarget string
 
 	// DNS record settings
-	TTL int
+	TTL            int
+	AllowWildcards bool // Publish wildcard hosts such as *.apps.example.com as wildcard records
+
+	// PTR records for target addresses, kept in the most specific of the
+	// in-addr.arpa and ip6.arpa ReverseZones containing each address
+	PTRRecords   bool
+	ReverseZones []string
+
+	// Traefik rule syntax for routers without a ruleSyntax label: "auto", "v2" or "v3"
+	RuleSyntax string
+
+	// Mirror of Traefik's Docker provider settings: workloads without a traefik.enable
+	// label are published only when TraefikExposedByDefault is set, and only workloads
+	// matching the TraefikConstraints expression are published
+	TraefikExposedByDefault bool
+	TraefikConstraints      string
 
 	// Filtering
 	IncludePattern *regexp.Regexp
 	ExcludePattern *regexp.Regexp
 
-	// Docker settings
+	// Docker settings. DockerHost belongs to the first of DockerEndpoints, and
+	// DockerMode is the mode of endpoints that do not set their own.
 	DockerHost string
 	DockerMode string // "auto", "swarm", or "standalone"
 
+	// Docker hosts whose workloads are published together, each optionally with
+	// target IPs of its own
+	DockerEndpoints []DockerEndpoint
+
 	// Behavior
 	ReconcileOnStartup bool
 	DryRun             bool
 
+	// Orphan cleanup
+	OrphanCleanup bool
+	OwnerID       string
+
+	// Record provenance is always written to record comments; ProvenanceTXT also
+	// publishes it in a TXT record next to the ownership record
+	ProvenanceTXT bool
+
 	// Health server
 	HealthPort int
 
@@ -42,14 +129,101 @@ type Config struct {
 	LogLevel string
 }
 
+// TechnitiumServer is a Technitium DNS server the records are written to.
+// Token is empty when the server is logged in to with Username and Password.
+type TechnitiumServer struct {
+	Name     string
+	URL      string
+	Token    string
+	Username string
+	Password string
+	TLS      TLSConfig
+}
+
+// DockerEndpoint is a Docker host whose workloads are published. Workloads on a host
+// with TargetIPs point at them instead of the global target IPs. A host with
+// CertPath is connected to over TLS with the ca.pem, cert.pem and key.pem in it.
+type DockerEndpoint struct {
+	Name      string
+	Host      string
+	Mode      string
+	TargetIPs []string
+	CertPath  string
+	TLSVerify bool
+}
+
+// TLSConfig holds the TLS settings of a Technitium server connection. Certificates
+// and keys are PEM-encoded; empty fields keep Go's defaults.
+type TLSConfig struct {
+	CACert             string // Replaces the system roots
+	ClientCert         string // Presented for mutual TLS together with ClientKey
+	ClientKey          string
+	ServerName         string // Overrides the name the server certificate is verified against
+	InsecureSkipVerify bool
+}
+
+// Record modes
+const (
+	RecordModeAddress = "address"
+	RecordModeCNAME   = "cname"
+)
+
+// Target IP modes
+const (
+	TargetIPModeStatic  = "static"
+	TargetIPModeNode    = "node"
+	TargetIPModeNetwork = "network"
+)
+
+// Zone check modes
+const (
+	ZoneCheckFail    = "fail"
+	ZoneCheckUnready = "unready"
+	ZoneCheckOff     = "off"
+)
+
+// ZoneSOA holds the SOA settings of zones created with ZONE_AUTO_CREATE. Zero values
+// keep Technitium's defaults.
+type ZoneSOA struct {
+	PrimaryNameServer string
+	ResponsiblePerson string
+	Refresh           int
+	Retry             int
+	Expire            int
+	Minimum           int
+}
+
 // Defaults
 const (
+	DefaultServerName         = "default"
+	DefaultDockerHostName     = "default"
 	DefaultTTL                = 300
+	DefaultRetryMaxAttempts   = 3
+	DefaultRetryBaseDelay     = 250 * time.Millisecond
+	DefaultRetryMaxDelay      = 5 * time.Second
+	DefaultBreakerThreshold   = 5
+	DefaultBreakerCooldown    = 30 * time.Second
+	DefaultRateLimit          = 0
+	DefaultRateBurst          = 10
+	DefaultMaxInFlight        = 4
+	DefaultZoneCheck          = ZoneCheckFail
+	DefaultZoneAutoCreate     = false
+	DefaultRecordMode         = RecordModeAddress
+	DefaultTargetIPMode       = TargetIPModeStatic
+	DefaultTraefikService     = "traefik"
+	DefaultTargetIPRefresh    = 30 * time.Second
+	DefaultRuleSyntax         = "auto"
+	DefaultExposedByDefault   = true
+	DefaultAllowWildcards     = false
+	DefaultPTRRecords         = false
 	DefaultIncludePattern     = ".*"
 	DefaultDockerHost         = "unix:///var/run/docker.sock"
 	DefaultDockerMode         = "auto"
 	DefaultReconcileOnStartup = true
 	DefaultDryRun             = false
+	DefaultOrphanCleanup      = false
+	DefaultOwnerID            = "default"
+	DefaultProvenanceTXT      = false
 	DefaultHealthPort         = 8080
 	DefaultLogLevel           = "info"
 )
@@ -60,30 +234,107 @@ func Load() (*Config, error) {
 	cfg := &Config{}
 	var errs []string
 
-	// Required: Technitium URL
-	cfg.TechnitiumURL = getEnvOrFile("TECHNITIUM_URL")
-	if cfg.TechnitiumURL == "" {
-		errs = append(errs, "TECHNITIUM_URL is required")
+	// Required: Technitium server(s). Without TECHNITIUM_SERVERS a single server is
+	// configured through TECHNITIUM_URL and its credentials.
+	serverNames := parseNames(os.Getenv("TECHNITIUM_SERVERS"))
+	if len(serverNames) == 0 {
+		cfg.TechnitiumServers = []TechnitiumServer{loadServer(DefaultServerName, "TECHNITIUM_", &errs)}
+	}
+	for _, name := range serverNames {
+		if !serverNamePattern.MatchString(name) {
+			errs = append(errs, fmt.Sprintf("TECHNITIUM_SERVERS entry %s must contain only letters, digits, hyphens and underscores", name))
+			continue
+		}
+		prefix := "TECHNITIUM_" + strings.ToUpper(strings.ReplaceAll(name, "-", "_")) + "_"
+		cfg.TechnitiumServers = append(cfg.TechnitiumServers, loadServer(name, prefix, &errs))
+	}
+	if len(cfg.TechnitiumServers) > 0 {
+		primary := cfg.TechnitiumServers[0]
+		cfg.TechnitiumURL = primary.URL
+		cfg.TechnitiumToken = primary.Token
+		cfg.TechnitiumUsername = primary.Username
+		cfg.TechnitiumPassword = primary.Password
+		cfg.TechnitiumTLS = primary.TLS
+	}
+
+	// Required: Zone(s). TECHNITIUM_ZONE and TECHNITIUM_ZONES may be combined.
+	cfg.TechnitiumZones = parseNames(getEnvOrFile("TECHNITIUM_ZONE") + "," + getEnvOrFile("TECHNITIUM_ZONES"))
+	if len(cfg.TechnitiumZones) == 0 {
+		errs = append(errs, "TECHNITIUM_ZONE or TECHNITIUM_ZONES is required")
+	} else {
+		cfg.TechnitiumZone = cfg.TechnitiumZones[0]
+	}
+
+	// Optional: Zone validation and auto-creation
+	cfg.ZoneCheck = strings.ToLower(os.Getenv("ZONE_CHECK"))
+	if cfg.ZoneCheck == "" {
+		cfg.ZoneCheck = DefaultZoneCheck
+	}
+	if cfg.ZoneCheck != ZoneCheckFail && cfg.ZoneCheck != ZoneCheckUnready && cfg.ZoneCheck != ZoneCheckOff {
+		errs = append(errs, "ZONE_CHECK must be 'fail', 'unready', or 'off'")
+	}
+	cfg.ZoneAutoCreate = parseBoolEnv("ZONE_AUTO_CREATE", DefaultZoneAutoCreate, &errs)
+	if cfg.ZoneAutoCreate && cfg.ZoneCheck == ZoneCheckOff {
+		errs = append(errs, "ZONE_AUTO_CREATE requires ZONE_CHECK to be 'fail' or 'unready'")
+	}
+	cfg.ZoneSOA = ZoneSOA{
+		PrimaryNameServer: normalizeName(os.Getenv("ZONE_SOA_PRIMARY_NAME_SERVER")),
+		ResponsiblePerson: strings.TrimSpace(os.Getenv("ZONE_SOA_RESPONSIBLE_PERSON")),
+		Refresh:           parseIntEnv("ZONE_SOA_REFRESH", 0, 0, &errs),
+		Retry:             parseIntEnv("ZONE_SOA_RETRY", 0, 0, &errs),
+		Expire:            parseIntEnv("ZONE_SOA_EXPIRE", 0, 0, &errs),
+		Minimum:           parseIntEnv("ZONE_SOA_MINIMUM", 0, 0, &errs),
+	}
+	cfg.ZoneNameServers = parseNames(os.Getenv("ZONE_NAME_SERVERS"))
+
+	// Optional: Record mode
+	cfg.RecordMode = strings.ToLower(os.Getenv("RECORD_MODE"))
+	if cfg.RecordMode == "" {
+		cfg.RecordMode = DefaultRecordMode
+	}
+	if cfg.RecordMode != RecordModeAddress && cfg.RecordMode != RecordModeCNAME {
+		errs = append(errs, "RECORD_MODE must be 'address' or 'cname'")
 	}
 
-	// Required: Technitium Token (supports _FILE for secrets)
-	cfg.TechnitiumToken = getEnvOrFile("TECHNITIUM_TOKEN")
-	if cfg.TechnitiumToken == "" {
-		errs = append(errs, "TECHNITIUM_TOKEN or TECHNITIUM_TOKEN_FILE is required")
+	// Required in cname mode: canonical name every hostname points at
+	cfg.CNAMETarget = normalizeName(os.Getenv("CNAME_TARGET"))
+	if cfg.RecordMode == RecordModeCNAME && cfg.CNAMETarget == "" {
+		errs = append(errs, "CNAME_TARGET is required when RECORD_MODE is 'cname'")
 	}
 
-	// Required: Zone
-	cfg.TechnitiumZone = getEnvOrFile("TECHNITIUM_ZONE")
-	if cfg.TechnitiumZone == "" {
-		errs = append(errs, "TECHNITIUM_ZONE is required")
+	// Target IP(s), comma-separated for dual-stack. Required in address mode unless
+	// every Docker host has target IPs of its own or they are derived from the
+	// nodes, which is checked below.
+	targetIPs, err := ParseIPList(getEnvOrFile("TARGET_IP"))
+	targetIPValid := err == nil
+	if err != nil {
+		errs = append(errs, fmt.Sprintf("TARGET_IP is not a valid IP address: %v", err))
+	} else if len(targetIPs) > 0 {
+		cfg.TargetIPs = targetIPs
+		cfg.TargetIP = targetIPs[0]
 	}
 
-	// Required: Target IP
-	cfg.TargetIP = getEnvOrFile("TARGET_IP")
-	if cfg.TargetIP == "" {
-		errs = append(errs, "TARGET_IP is required")
-	} else if net.ParseIP(cfg.TargetIP) == nil {
-		errs = append(errs, fmt.Sprintf("TARGET_IP is not a valid IP address: %s", cfg.TargetIP))
+	// Optional: Target IP mode
+	cfg.TargetIPMode = strings.ToLower(os.Getenv("TARGET_IP_MODE"))
+	if cfg.TargetIPMode == "" {
+		cfg.TargetIPMode = DefaultTargetIPMode
+	}
+	if cfg.TargetIPMode != TargetIPModeStatic && cfg.TargetIPMode != TargetIPModeNode && cfg.TargetIPMode != TargetIPModeNetwork {
+		errs = append(errs, "TARGET_IP_MODE must be 'static', 'node', or 'network'")
+	}
+	if cfg.TargetIPMode != TargetIPModeStatic && cfg.RecordMode == RecordModeCNAME {
+		errs = append(errs, fmt.Sprintf("TARGET_IP_MODE '%s' requires RECORD_MODE to be 'address'", cfg.TargetIPMode))
+	}
+	cfg.TraefikService = strings.TrimSpace(os.Getenv("TRAEFIK_SERVICE"))
+	if cfg.TraefikService == "" {
+		cfg.TraefikService = DefaultTraefikService
+	}
+	cfg.TargetIPNodeLabel = strings.TrimSpace(os.Getenv("TARGET_IP_NODE_LABEL"))
+	cfg.TargetIPInterface = strings.TrimSpace(os.Getenv("TARGET_IP_INTERFACE"))
+	cfg.TargetIPRefresh = parseDurationEnv("TARGET_IP_REFRESH", DefaultTargetIPRefresh, &errs)
+	cfg.TargetIPNetwork = strings.TrimSpace(os.Getenv("TARGET_IP_NETWORK"))
+	if cfg.TargetIPMode == TargetIPModeNetwork && cfg.TargetIPNetwork == "" {
+		errs = append(errs, "TARGET_IP_NETWORK is required when TARGET_IP_MODE is 'network'")
 	}
 
 	// Optional: TTL
@@ -101,6 +352,35 @@ func Load() (*Config, error) {
 		cfg.TTL = DefaultTTL
 	}
 
+	// Optional: Traefik rule syntax
+	cfg.RuleSyntax = strings.ToLower(os.Getenv("TRAEFIK_RULE_SYNTAX"))
+	if cfg.RuleSyntax == "" {
+		cfg.RuleSyntax = DefaultRuleSyntax
+	}
+	if cfg.RuleSyntax != "auto" && cfg.RuleSyntax != "v2" && cfg.RuleSyntax != "v3" {
+		errs = append(errs, "TRAEFIK_RULE_SYNTAX must be 'auto', 'v2', or 'v3'")
+	}
+
+	// Optional: Traefik Docker provider filtering. The constraints expression is
+	// validated when the Traefik parser is built.
+	cfg.TraefikExposedByDefault = parseBoolEnv("TRAEFIK_EXPOSED_BY_DEFAULT", DefaultExposedByDefault, &errs)
+	cfg.TraefikConstraints = strings.TrimSpace(os.Getenv("TRAEFIK_CONSTRAINTS"))
+
+	// Optional: Wildcard records
+	cfg.AllowWildcards = parseBoolEnv("ALLOW_WILDCARDS", DefaultAllowWildcards, &errs)
+
+	// Optional: PTR records. REVERSE_ZONES is required when they are enabled.
+	cfg.PTRRecords = parseBoolEnv("PTR_RECORDS", DefaultPTRRecords, &errs)
+	cfg.ReverseZones = parseNames(os.Getenv("REVERSE_ZONES"))
+	for _, zone := range cfg.ReverseZones {
+		if !IsReverseZone(zone) {
+			errs = append(errs, fmt.Sprintf("REVERSE_ZONES entry %s is not an in-addr.arpa or ip6.arpa zone", zone))
+		}
+	}
+	if cfg.PTRRecords && len(cfg.ReverseZones) == 0 {
+		errs = append(errs, "REVERSE_ZONES is required when PTR_RECORDS is enabled")
+	}
+
 	// Optional: Include pattern
 	includeStr := os.Getenv("INCLUDE_PATTERN")
 	if includeStr == "" {
@@ -135,25 +415,73 @@ func Load() (*Config, error) {
 	if cfg.DockerMode == "" {
 		cfg.DockerMode = DefaultDockerMode
 	}
-	if cfg.DockerMode != "auto" && cfg.DockerMode != "swarm" && cfg.DockerMode != "standalone" {
+	if !isDockerMode(cfg.DockerMode) {
 		errs = append(errs, "DOCKER_MODE must be 'auto', 'swarm', or 'standalone'")
 	}
 
-	// Optional: Reconcile on startup
-	reconcileStr := os.Getenv("RECONCILE_ON_STARTUP")
-	if reconcileStr == "" {
-		cfg.ReconcileOnStartup = DefaultReconcileOnStartup
-	} else {
-		cfg.ReconcileOnStartup = parseBool(reconcileStr, DefaultReconcileOnStartup)
+	// Optional: Multiple Docker hosts. Without DOCKER_HOSTS the single host above is used.
+	hostNames := parseNames(os.Getenv("DOCKER_HOSTS"))
+	if len(hostNames) == 0 {
+		e := DockerEndpoint{Name: DefaultDockerHostName, Host: cfg.DockerHost, Mode: cfg.DockerMode}
+		loadDockerTLS(&e, "DOCKER_", &errs)
+		cfg.DockerEndpoints = []DockerEndpoint{e}
+	}
+	for _, name := range hostNames {
+		if !serverNamePattern.MatchString(name) {
+			errs = append(errs, fmt.Sprintf("DOCKER_HOSTS entry %s must contain only letters, digits, hyphens and underscores", name))
+			continue
+		}
+		prefix := "DOCKER_" + strings.ToUpper(strings.ReplaceAll(name, "-", "_")) + "_"
+		cfg.DockerEndpoints = append(cfg.DockerEndpoints, loadDockerEndpoint(name, prefix, cfg.DockerMode, &errs))
 	}
+	if len(hostNames) > 0 && len(cfg.DockerEndpoints) > 0 {
+		cfg.DockerHost = cfg.DockerEndpoints[0].Host
+	}
+
+	if targetIPValid && len(cfg.TargetIPs) == 0 && cfg.RecordMode != RecordModeCNAME && cfg.TargetIPMode == TargetIPModeStatic {
+		for _, e := range cfg.DockerEndpoints {
+			if len(e.TargetIPs) == 0 {
+				errs = append(errs, "TARGET_IP is required")
+				break
+			}
+		}
+	}
+
+	// Optional: Reconcile on startup
+	cfg.ReconcileOnStartup = parseBoolEnv("RECONCILE_ON_STARTUP", DefaultReconcileOnStartup, &errs)
 
 	// Optional: Dry run
-	dryRunStr := os.Getenv("DRY_RUN")
-	if dryRunStr == "" {
-		cfg.DryRun = DefaultDryRun
-	} else {
-		cfg.DryRun = parseBool(dryRunStr, DefaultDryRun)
+	cfg.DryRun = parseBoolEnv("DRY_RUN", DefaultDryRun, &errs)
+
+	// Optional: Orphan cleanup
+	cfg.OrphanCleanup = parseBoolEnv("ORPHAN_CLEANUP", DefaultOrphanCleanup, &errs)
+
+	// Optional: Owner ID (distinguishes multiple companion instances sharing a zone)
+	cfg.OwnerID = os.Getenv("OWNER_ID")
+	if cfg.OwnerID == "" {
+		cfg.OwnerID = DefaultOwnerID
+	}
+	if strings.ContainsAny(cfg.OwnerID, ", =\"") {
+		errs = append(errs, "OWNER_ID must not contain commas, spaces, equals signs or quotes")
+	}
+
+	// Optional: Provenance TXT records
+	cfg.ProvenanceTXT = parseBoolEnv("PROVENANCE_TXT", DefaultProvenanceTXT, &errs)
+
+	// Optional: Technitium API retries and circuit breaker
+	cfg.RetryMaxAttempts = parseIntEnv("TECHNITIUM_RETRY_ATTEMPTS", DefaultRetryMaxAttempts, 1, &errs)
+	cfg.RetryBaseDelay = parseDurationEnv("TECHNITIUM_RETRY_BASE_DELAY", DefaultRetryBaseDelay, &errs)
+	cfg.RetryMaxDelay = parseDurationEnv("TECHNITIUM_RETRY_MAX_DELAY", DefaultRetryMaxDelay, &errs)
+	if cfg.RetryMaxDelay < cfg.RetryBaseDelay {
+		errs = append(errs, "TECHNITIUM_RETRY_MAX_DELAY must not be less than TECHNITIUM_RETRY_BASE_DELAY")
 	}
+	cfg.BreakerThreshold = parseIntEnv("TECHNITIUM_BREAKER_THRESHOLD", DefaultBreakerThreshold, 0, &errs)
+	cfg.BreakerCooldown = parseDurationEnv("TECHNITIUM_BREAKER_COOLDOWN", DefaultBreakerCooldown, &errs)
+
+	// Optional: Technitium API rate limit and concurrency cap
+	cfg.RateLimit = parseFloatEnv("TECHNITIUM_RATE_LIMIT", DefaultRateLimit, &errs)
+	cfg.RateBurst = parseIntEnv("TECHNITIUM_RATE_BURST", DefaultRateBurst, 1, &errs)
+	cfg.MaxInFlight = parseIntEnv("TECHNITIUM_MAX_IN_FLIGHT", DefaultMaxInFlight, 0, &errs)
 
 	// Optional: Health port
 	healthPortStr := os.Getenv("HEALTH_PORT")
@@ -186,13 +514,135 @@ func Load() (*Config, error) {
 	return cfg, nil
 }
 
+// serverNamePattern matches names accepted in TECHNITIUM_SERVERS and DOCKER_HOSTS.
+// They become part of environment variable names and metrics labels.
+var serverNamePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+
+// loadServer reads a Technitium server's URL, credentials and TLS settings from the
+// environment variables starting with prefix, e.g. TECHNITIUM_DNS1_URL. A named server
+// without credentials of its own uses TECHNITIUM_TOKEN or TECHNITIUM_USERNAME and
+// TECHNITIUM_PASSWORD, and each unset TLS setting falls back to its TECHNITIUM_TLS_*
+// counterpart. All values support _FILE for secrets.
+func loadServer(name, prefix string, errs *[]string) TechnitiumServer {
+	s := TechnitiumServer{
+		Name:     name,
+		URL:      getEnvOrFile(prefix + "URL"),
+		Token:    getEnvOrFile(prefix + "TOKEN"),
+		Username: getEnvOrFile(prefix + "USERNAME"),
+		Password: getEnvOrFile(prefix + "PASSWORD"),
+	}
+	if s.URL == "" {
+		*errs = append(*errs, prefix+"URL is required")
+	}
+
+	credPrefix := prefix
+	if s.Token == "" && s.Username == "" && s.Password == "" && prefix != "TECHNITIUM_" {
+		credPrefix = "TECHNITIUM_"
+		s.Token = getEnvOrFile("TECHNITIUM_TOKEN")
+		s.Username = getEnvOrFile("TECHNITIUM_USERNAME")
+		s.Password = getEnvOrFile("TECHNITIUM_PASSWORD")
+	}
+
+	hasCredentials := s.Username != "" || s.Password != ""
+	switch {
+	case s.Token != "" && hasCredentials:
+		*errs = append(*errs, fmt.Sprintf("set either %[1]sTOKEN or %[1]sUSERNAME and %[1]sPASSWORD, not both", credPrefix))
+	case hasCredentials && (s.Username == "" || s.Password == ""):
+		*errs = append(*errs, fmt.Sprintf("%[1]sUSERNAME and %[1]sPASSWORD must be set together", credPrefix))
+	case s.Token == "" && !hasCredentials:
+		*errs = append(*errs, fmt.Sprintf("%[1]sTOKEN or %[1]sTOKEN_FILE is required, or %[1]sUSERNAME and %[1]sPASSWORD", prefix))
+	}
+
+	// A TLS setting the server does not set falls back to the TECHNITIUM_ one. A
+	// _FILE that cannot be read is an error rather than an unset setting, which would
+	// silently drop the CA or client certificate.
+	tlsKey := func(key string) string {
+		if prefix == "TECHNITIUM_" || os.Getenv(prefix+key) != "" || os.Getenv(prefix+key+"_FILE") != "" {
+			return prefix + key
+		}
+		return "TECHNITIUM_" + key
+	}
+	tlsSetting := func(key string) string {
+		v, err := getEnvOrFileErr(tlsKey(key))
+		if err != nil {
+			*errs = append(*errs, err.Error())
+		}
+		return v
+	}
+	skipVerifyKey := tlsKey("TLS_INSECURE_SKIP_VERIFY")
+	s.TLS = TLSConfig{
+		CACert:             tlsSetting("TLS_CA_CERT"),
+		ClientCert:         tlsSetting("TLS_CLIENT_CERT"),
+		ClientKey:          tlsSetting("TLS_CLIENT_KEY"),
+		ServerName:         strings.TrimSpace(tlsSetting("TLS_SERVER_NAME")),
+		InsecureSkipVerify: parseBoolSetting(skipVerifyKey, tlsSetting("TLS_INSECURE_SKIP_VERIFY"), false, errs),
+	}
+	if (s.TLS.ClientCert == "") != (s.TLS.ClientKey == "") {
+		*errs = append(*errs, fmt.Sprintf("%[1]sTLS_CLIENT_CERT and %[1]sTLS_CLIENT_KEY must be set together", prefix))
+	}
+
+	return s
+}
+
+// loadDockerEndpoint reads a Docker host's address, mode, target IPs and TLS settings
+// from the environment variables starting with prefix, e.g. DOCKER_EDGE1_HOST. The
+// mode falls back to defaultMode, and the target IPs to TARGET_IP when unset; the
+// TLS settings of other hosts never apply.
+func loadDockerEndpoint(name, prefix, defaultMode string, errs *[]string) DockerEndpoint {
+	e := DockerEndpoint{
+		Name: name,
+		Host: os.Getenv(prefix + "HOST"),
+		Mode: strings.ToLower(os.Getenv(prefix + "MODE")),
+	}
+	if e.Host == "" {
+		*errs = append(*errs, prefix+"HOST is required")
+	}
+
+	if e.Mode == "" {
+		e.Mode = defaultMode
+	} else if !isDockerMode(e.Mode) {
+		*errs = append(*errs, prefix+"MODE must be 'auto', 'swarm', or 'standalone'")
+	}
+
+	targetIPs, err := ParseIPList(getEnvOrFile(prefix + "TARGET_IP"))
+	if err != nil {
+		*errs = append(*errs, fmt.Sprintf("%sTARGET_IP is not a valid IP address: %v", prefix, err))
+	}
+	e.TargetIPs = targetIPs
+
+	loadDockerTLS(&e, prefix, errs)
+
+	return e
+}
+
+// loadDockerTLS reads the CERT_PATH and TLS_VERIFY settings of a Docker host from
+// the environment variables starting with prefix.
+func loadDockerTLS(e *DockerEndpoint, prefix string, errs *[]string) {
+	e.CertPath = os.Getenv(prefix + "CERT_PATH")
+	e.TLSVerify = parseBoolEnv(prefix+"TLS_VERIFY", false, errs)
+	if e.TLSVerify && e.CertPath == "" {
+		*errs = append(*errs, fmt.Sprintf("%[1]sTLS_VERIFY requires %[1]sCERT_PATH", prefix))
+	}
+}
+
+// isDockerMode reports whether mode is a valid DOCKER_MODE.
+func isDockerMode(mode string) bool {
+	return mode == "auto" || mode == "swarm" || mode == "standalone"
+}
+
 // getEnvOrFile returns the value of an environment variable,
 // or if VAR_FILE is set, reads the contents from that file.
 // Supports Docker secrets pattern.
 func getEnvOrFile(key string) string {
+	val, _ := getEnvOrFileErr(key)
+	return val
+}
+
+// getEnvOrFileErr is getEnvOrFile, but reports a VAR_FILE that is set and cannot be read.
+func getEnvOrFileErr(key string) (string, error) {
 	// First check if the direct value is set
 	if val := os.Getenv(key); val != "" {
-		return val
+		return val, nil
 	}
 
 	// Check for _FILE suffix (Docker secrets)
@@ -200,24 +650,217 @@ func getEnvOrFile(key string) string {
 	if filePath := os.Getenv(fileKey); filePath != "" {
 		data, err := os.ReadFile(filePath)
 		if err != nil {
-			return ""
+			return "", fmt.Errorf("%s could not be read: %w", fileKey, err)
 		}
-		return strings.TrimSpace(string(data))
+		return strings.TrimSpace(string(data)), nil
 	}
 
-	return ""
+	return "", nil
+}
+
+// ParseIPList parses a comma-separated list of IP addresses into their canonical
+// text form, dropping empty entries and duplicates.
+func ParseIPList(s string) ([]string, error) {
+	var ips []string
+	seen := make(map[string]struct{})
+	for _, entry := range strings.Split(s, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		ip := net.ParseIP(entry)
+		if ip == nil {
+			return nil, fmt.Errorf("%s", entry)
+		}
+		canonical := ip.String()
+		if _, ok := seen[canonical]; ok {
+			continue
+		}
+		seen[canonical] = struct{}{}
+		ips = append(ips, canonical)
+	}
+	return ips, nil
+}
+
+// Servers returns the Technitium servers, falling back to a single server built
+// from TechnitiumURL and its credentials when TechnitiumServers has not been populated.
+func (c *Config) Servers() []TechnitiumServer {
+	if len(c.TechnitiumServers) > 0 {
+		return c.TechnitiumServers
+	}
+	if c.TechnitiumURL != "" {
+		return []TechnitiumServer{{
+			Name:     DefaultServerName,
+			URL:      c.TechnitiumURL,
+			Token:    c.TechnitiumToken,
+			Username: c.TechnitiumUsername,
+			Password: c.TechnitiumPassword,
+			TLS:      c.TechnitiumTLS,
+		}}
+	}
+	return nil
+}
+
+// Endpoints returns the Docker hosts, falling back to a single host built from
+// DockerHost and DockerMode when DockerEndpoints has not been populated.
+func (c *Config) Endpoints() []DockerEndpoint {
+	if len(c.DockerEndpoints) > 0 {
+		return c.DockerEndpoints
+	}
+	return []DockerEndpoint{{Name: DefaultDockerHostName, Host: c.DockerHost, Mode: c.DockerMode}}
+}
+
+// Endpoint returns the Docker host with the given name.
+func (c *Config) Endpoint(name string) (DockerEndpoint, bool) {
+	for _, e := range c.Endpoints() {
+		if e.Name == name {
+			return e, true
+		}
+	}
+	return DockerEndpoint{}, false
 }
 
-// parseBool parses a boolean string, returning defaultValue on parse failure.
-func parseBool(s string, defaultValue bool) bool {
-	s = strings.ToLower(strings.TrimSpace(s))
-	switch s {
+// TargetsFor returns the target IPs of the workloads on the named Docker host: the
+// host's own TargetIPs, or the global target IPs when it has none.
+func (c *Config) TargetsFor(host string) []string {
+	for _, e := range c.DockerEndpoints {
+		if e.Name == host && len(e.TargetIPs) > 0 {
+			return e.TargetIPs
+		}
+	}
+	return c.Targets()
+}
+
+// Targets returns the target IPs, falling back to TargetIP when TargetIPs has
+// not been populated.
+func (c *Config) Targets() []string {
+	if len(c.TargetIPs) > 0 {
+		return c.TargetIPs
+	}
+	if c.TargetIP != "" {
+		return []string{c.TargetIP}
+	}
+	return nil
+}
+
+// parseNames splits a comma-separated list of DNS names, normalizing case and trailing dots
+// and dropping empty entries and duplicates while preserving order.
+func parseNames(s string) []string {
+	var zones []string
+	seen := make(map[string]struct{})
+	for _, zone := range strings.Split(s, ",") {
+		zone = normalizeName(zone)
+		if zone == "" {
+			continue
+		}
+		if _, ok := seen[zone]; ok {
+			continue
+		}
+		seen[zone] = struct{}{}
+		zones = append(zones, zone)
+	}
+	return zones
+}
+
+// IsReverseZone reports whether zone lies in the in-addr.arpa or ip6.arpa tree.
+func IsReverseZone(zone string) bool {
+	zone = normalizeName(zone)
+	return zone == "in-addr.arpa" || zone == "ip6.arpa" ||
+		strings.HasSuffix(zone, ".in-addr.arpa") || strings.HasSuffix(zone, ".ip6.arpa")
+}
+
+// normalizeName lowercases a DNS name and strips surrounding whitespace and the trailing dot.
+func normalizeName(name string) string {
+	return strings.TrimSuffix(strings.ToLower(strings.TrimSpace(name)), ".")
+}
+
+// parseIntEnv reads an integer environment variable of at least minValue, returning
+// defaultValue when it is unset and recording a message in errs when it is invalid.
+func parseIntEnv(key string, defaultValue, minValue int, errs *[]string) int {
+	s := os.Getenv(key)
+	if s == "" {
+		return defaultValue
+	}
+	n, err := strconv.Atoi(s)
+	if err != nil {
+		*errs = append(*errs, fmt.Sprintf("%s must be a valid integer: %v", key, err))
+		return defaultValue
+	}
+	if n < minValue {
+		*errs = append(*errs, fmt.Sprintf("%s must be at least %d", key, minValue))
+		return defaultValue
+	}
+	return n
+}
+
+// parseFloatEnv reads a non-negative number from an environment variable, returning
+// defaultValue when it is unset and recording a message in errs when it is invalid.
+func parseFloatEnv(key string, defaultValue float64, errs *[]string) float64 {
+	s := os.Getenv(key)
+	if s == "" {
+		return defaultValue
+	}
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		*errs = append(*errs, fmt.Sprintf("%s must be a valid number: %v", key, err))
+		return defaultValue
+	}
+	if f < 0 || math.IsNaN(f) {
+		*errs = append(*errs, fmt.Sprintf("%s must not be negative", key))
+		return defaultValue
+	}
+	return f
+}
+
+// parseDurationEnv reads a non-negative duration such as 500ms or 30s from an
+// environment variable, returning defaultValue when it is unset and recording a
+// message in errs when it is invalid.
+func parseDurationEnv(key string, defaultValue time.Duration, errs *[]string) time.Duration {
+	s := os.Getenv(key)
+	if s == "" {
+		return defaultValue
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		*errs = append(*errs, fmt.Sprintf("%s must be a valid duration: %v", key, err))
+		return defaultValue
+	}
+	if d < 0 {
+		*errs = append(*errs, fmt.Sprintf("%s must not be negative", key))
+		return defaultValue
+	}
+	return d
+}
+
+// parseBoolEnv reads a boolean from an environment variable, returning defaultValue
+// when it is unset and recording a message in errs when it is invalid.
+func parseBoolEnv(key string, defaultValue bool, errs *[]string) bool {
+	return parseBoolSetting(key, os.Getenv(key), defaultValue, errs)
+}
+
+// parseBoolSetting parses the value s of the boolean setting key, returning
+// defaultValue when it is empty and recording a message in errs when it is invalid.
+func parseBoolSetting(key, s string, defaultValue bool, errs *[]string) bool {
+	if strings.TrimSpace(s) == "" {
+		return defaultValue
+	}
+	b, ok := parseBool(s)
+	if !ok {
+		*errs = append(*errs, fmt.Sprintf("%s must be a boolean (true/false, 1/0, yes/no, on/off), got %q", key, s))
+		return defaultValue
+	}
+	return b
+}
+
+// parseBool parses a boolean string, reporting whether it was recognized.
+func parseBool(s string) (bool, bool) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
 	case "true", "1", "yes", "on":
-		return true
+		return true, true
 	case "false", "0", "no", "off":
-		return false
+		return false, true
 	default:
-		return defaultValue
+		return false, false
 	}
 }
 
@@ -237,10 +880,56 @@ func (c *Config) MatchesFilters(hostname string) bool {
 	return true
 }
 
+// ZoneFor returns the managed zone a hostname belongs to, choosing the longest
+// matching suffix when zones are nested (e.g. lab.example.com inside example.com).
+// Returns false if the hostname is not inside any configured zone.
+func (c *Config) ZoneFor(hostname string) (string, bool) {
+	return longestSuffixZone(c.Zones(), hostname)
+}
+
+// ReverseZoneFor returns the reverse zone a PTR name such as 1.0.0.10.in-addr.arpa
+// belongs to, choosing the longest matching suffix. Returns false if the name is not
+// inside any configured reverse zone.
+func (c *Config) ReverseZoneFor(name string) (string, bool) {
+	return longestSuffixZone(c.ReverseZones, name)
+}
+
+// Zones returns the managed zones, falling back to TechnitiumZone when
+// TechnitiumZones has not been populated.
+func (c *Config) Zones() []string {
+	if len(c.TechnitiumZones) > 0 {
+		return c.TechnitiumZones
+	}
+	if c.TechnitiumZone != "" {
+		return []string{normalizeName(c.TechnitiumZone)}
+	}
+	return nil
+}
+
+// longestSuffixZone returns the zone in zones that most specifically contains hostname.
+func longestSuffixZone(zones []string, hostname string) (string, bool) {
+	hostname = normalizeName(hostname)
+
+	best := ""
+	for _, zone := range zones {
+		if hostname != zone && !strings.HasSuffix(hostname, "."+zone) {
+			continue
+		}
+		if len(zone) > len(best) {
+			best = zone
+		}
+	}
+
+	return best, best != ""
+}
+
 // Validate performs additional validation that requires all fields to be loaded.
 func (c *Config) Validate() error {
-	// Ensure the Technitium URL doesn't have trailing slashes
+	// Ensure the Technitium URLs don't have trailing slashes
 	c.TechnitiumURL = strings.TrimRight(c.TechnitiumURL, "/")
+	for i := range c.TechnitiumServers {
+		c.TechnitiumServers[i].URL = strings.TrimRight(c.TechnitiumServers[i].URL, "/")
+	}
 
 	return nil
 }
@@ -5,6 +5,7 @@ import (
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 )
 
 func TestLoad_RequiredFields(t *testing.T) {
@@ -144,6 +145,58 @@ func TestLoad_TokenFromFile(t *testing.T) {
 	}
 }
 
+func TestLoad_Credentials(t *testing.T) {
+	clearEnv()
+	defer clearEnv()
+
+	passwordFile := filepath.Join(t.TempDir(), "password")
+	if err := os.WriteFile(passwordFile, []byte("hunter2\n"), 0600); err != nil {
+		t.Fatalf("failed to write password file: %v", err)
+	}
+
+	os.Setenv("TECHNITIUM_URL", "http://dns.example.com:5380")
+	os.Setenv("TECHNITIUM_USERNAME", "admin")
+	os.Setenv("TECHNITIUM_PASSWORD_FILE", passwordFile)
+	os.Setenv("TECHNITIUM_ZONE", "example.com")
+	os.Setenv("TARGET_IP", "10.0.0.1")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.TechnitiumToken != "" || cfg.TechnitiumUsername != "admin" || cfg.TechnitiumPassword != "hunter2" {
+		t.Errorf("unexpected credentials: token=%q user=%q password=%q", cfg.TechnitiumToken, cfg.TechnitiumUsername, cfg.TechnitiumPassword)
+	}
+}
+
+func TestLoad_InvalidCredentials(t *testing.T) {
+	tests := []struct {
+		name string
+		env  map[string]string
+	}{
+		{"token and credentials", map[string]string{"TECHNITIUM_TOKEN": "t", "TECHNITIUM_USERNAME": "admin", "TECHNITIUM_PASSWORD": "p"}},
+		{"username without password", map[string]string{"TECHNITIUM_USERNAME": "admin"}},
+		{"password without username", map[string]string{"TECHNITIUM_PASSWORD": "p"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clearEnv()
+			defer clearEnv()
+			os.Setenv("TECHNITIUM_URL", "http://dns.example.com:5380")
+			os.Setenv("TECHNITIUM_ZONE", "example.com")
+			os.Setenv("TARGET_IP", "10.0.0.1")
+			for k, v := range tt.env {
+				os.Setenv(k, v)
+			}
+
+			if _, err := Load(); err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+}
+
 func TestLoad_IncludePattern(t *testing.T) {
 	clearEnv()
 	setRequiredEnv()
@@ -261,6 +314,17 @@ func TestLoad_BooleanParsing(t *testing.T) {
 	}
 }
 
+func TestLoad_InvalidBoolean(t *testing.T) {
+	clearEnv()
+	setRequiredEnv()
+	os.Setenv("DRY_RUN", "ture")
+	defer clearEnv()
+
+	if _, err := Load(); err == nil || !strings.Contains(err.Error(), "DRY_RUN must be a boolean") {
+		t.Errorf("expected error for invalid DRY_RUN, got %v", err)
+	}
+}
+
 func TestLoad_InvalidLogLevel(t *testing.T) {
 	clearEnv()
 	setRequiredEnv()
@@ -335,9 +399,9 @@ func TestMatchesFilters(t *testing.T) {
 	}{
 		{"app.local.example.com", true},
 		{"api.local.example.com", true},
-		{"test.local.example.com", false},  // excluded by EXCLUDE_PATTERN
-		{"app.example.com", false},         // doesn't match INCLUDE_PATTERN
-		{"test.example.com", false},        // doesn't match INCLUDE_PATTERN
+		{"test.local.example.com", false}, // excluded by EXCLUDE_PATTERN
+		{"app.example.com", false},        // doesn't match INCLUDE_PATTERN
+		{"test.example.com", false},       // doesn't match INCLUDE_PATTERN
 	}
 
 	for _, tt := range tests {
@@ -414,25 +478,873 @@ func TestLoad_ValidIPv6(t *testing.T) {
 	}
 }
 
-func TestValidate_TrimsTrailingSlash(t *testing.T) {
+func TestLoad_DualStackTargetIPs(t *testing.T) {
 	clearEnv()
-	os.Setenv("TECHNITIUM_URL", "http://dns.example.com:5380/")
+	os.Setenv("TECHNITIUM_URL", "http://dns.example.com:5380")
 	os.Setenv("TECHNITIUM_TOKEN", "token")
 	os.Setenv("TECHNITIUM_ZONE", "example.com")
-	os.Setenv("TARGET_IP", "10.0.0.1")
+	os.Setenv("TARGET_IP", "10.0.0.1, 2001:DB8::1, 10.0.0.1")
 	defer clearEnv()
 
 	cfg, err := Load()
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
+	if len(cfg.TargetIPs) != 2 || cfg.TargetIPs[0] != "10.0.0.1" || cfg.TargetIPs[1] != "2001:db8::1" {
+		t.Errorf("expected target IPs [10.0.0.1 2001:db8::1], got %v", cfg.TargetIPs)
+	}
+	if cfg.TargetIP != "10.0.0.1" {
+		t.Errorf("expected TargetIP to be the first address, got %s", cfg.TargetIP)
+	}
+}
+
+func TestLoad_InvalidTargetIPInList(t *testing.T) {
+	clearEnv()
+	os.Setenv("TECHNITIUM_URL", "http://dns.example.com:5380")
+	os.Setenv("TECHNITIUM_TOKEN", "token")
+	os.Setenv("TECHNITIUM_ZONE", "example.com")
+	os.Setenv("TARGET_IP", "10.0.0.1,bogus")
+	defer clearEnv()
+
+	_, err := Load()
+	if err == nil || !strings.Contains(err.Error(), "bogus") {
+		t.Errorf("expected error naming the invalid address, got: %v", err)
+	}
+}
+
+func TestLoad_CNAMEMode(t *testing.T) {
+	clearEnv()
+	os.Setenv("TECHNITIUM_URL", "http://dns.example.com:5380")
+	os.Setenv("TECHNITIUM_TOKEN", "token")
+	os.Setenv("TECHNITIUM_ZONE", "example.com")
+	os.Setenv("RECORD_MODE", "CNAME")
+	os.Setenv("CNAME_TARGET", "Traefik.Example.com.")
+	defer clearEnv()
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("unexpected error (TARGET_IP should be optional in cname mode): %v", err)
+	}
+	if cfg.RecordMode != RecordModeCNAME {
+		t.Errorf("expected record mode cname, got %s", cfg.RecordMode)
+	}
+	if cfg.CNAMETarget != "traefik.example.com" {
+		t.Errorf("expected normalized CNAME target traefik.example.com, got %s", cfg.CNAMETarget)
+	}
+}
+
+func TestLoad_CNAMEModeRequiresTarget(t *testing.T) {
+	clearEnv()
+	setRequiredEnv()
+	os.Setenv("RECORD_MODE", "cname")
+	defer clearEnv()
+
+	_, err := Load()
+	if err == nil || !strings.Contains(err.Error(), "CNAME_TARGET is required") {
+		t.Errorf("expected CNAME_TARGET error, got: %v", err)
+	}
+}
+
+func TestLoad_InvalidRecordMode(t *testing.T) {
+	clearEnv()
+	setRequiredEnv()
+	os.Setenv("RECORD_MODE", "mx")
+	defer clearEnv()
+
+	_, err := Load()
+	if err == nil || !strings.Contains(err.Error(), "RECORD_MODE") {
+		t.Errorf("expected RECORD_MODE error, got: %v", err)
+	}
+}
+
+func TestLoad_InvalidRuleSyntax(t *testing.T) {
+	clearEnv()
+	setRequiredEnv()
+	os.Setenv("TRAEFIK_RULE_SYNTAX", "v4")
+	defer clearEnv()
+
+	_, err := Load()
+	if err == nil || !strings.Contains(err.Error(), "TRAEFIK_RULE_SYNTAX") {
+		t.Errorf("expected TRAEFIK_RULE_SYNTAX error, got: %v", err)
+	}
+}
+
+func TestLoad_TechnitiumServers(t *testing.T) {
+	clearEnv()
+	defer clearEnv()
+
+	os.Setenv("TECHNITIUM_SERVERS", "dns1, DNS-2")
+	os.Setenv("TECHNITIUM_DNS1_URL", "http://dns1.example.com:5380")
+	os.Setenv("TECHNITIUM_DNS_2_URL", "http://dns2.example.com:5380/")
+	os.Setenv("TECHNITIUM_TOKEN", "shared-token")
+	os.Setenv("TECHNITIUM_ZONE", "example.com")
+	os.Setenv("TARGET_IP", "10.0.0.1")
 
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
 	if err := cfg.Validate(); err != nil {
 		t.Fatalf("unexpected validation error: %v", err)
 	}
 
-	if cfg.TechnitiumURL != "http://dns.example.com:5380" {
-		t.Errorf("expected trailing slash to be trimmed, got %s", cfg.TechnitiumURL)
+	// A server without credentials of its own uses the shared token
+	expected := []TechnitiumServer{
+		{Name: "dns1", URL: "http://dns1.example.com:5380", Token: "shared-token"},
+		{Name: "dns-2", URL: "http://dns2.example.com:5380", Token: "shared-token"},
+	}
+	servers := cfg.Servers()
+	if len(servers) != len(expected) {
+		t.Fatalf("expected %d servers, got %+v", len(expected), servers)
+	}
+	for i := range expected {
+		if servers[i] != expected[i] {
+			t.Errorf("server %d = %+v, want %+v", i, servers[i], expected[i])
+		}
+	}
+	if cfg.TechnitiumURL != "http://dns1.example.com:5380" {
+		t.Errorf("expected TechnitiumURL of the first server, got %s", cfg.TechnitiumURL)
+	}
+
+	os.Setenv("TECHNITIUM_DNS_2_USERNAME", "admin")
+	os.Setenv("TECHNITIUM_DNS_2_PASSWORD", "hunter2")
+	cfg, err = Load()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if s := cfg.Servers()[1]; s.Token != "" || s.Username != "admin" || s.Password != "hunter2" {
+		t.Errorf("expected per-server credentials, got %+v", s)
+	}
+}
+
+func TestLoad_TechnitiumServersInvalid(t *testing.T) {
+	tests := []struct {
+		name string
+		env  map[string]string
+		key  string
+	}{
+		{"missing url", map[string]string{"TECHNITIUM_SERVERS": "dns1", "TECHNITIUM_DNS1_TOKEN": "t"}, "TECHNITIUM_DNS1_URL"},
+		{"missing token", map[string]string{"TECHNITIUM_SERVERS": "dns1", "TECHNITIUM_DNS1_URL": "http://dns1"}, "TECHNITIUM_DNS1_TOKEN"},
+		{"invalid name", map[string]string{"TECHNITIUM_SERVERS": "dns.1", "TECHNITIUM_TOKEN": "t"}, "TECHNITIUM_SERVERS"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clearEnv()
+			defer clearEnv()
+			os.Setenv("TECHNITIUM_ZONE", "example.com")
+			os.Setenv("TARGET_IP", "10.0.0.1")
+			for k, v := range tt.env {
+				os.Setenv(k, v)
+			}
+
+			if _, err := Load(); err == nil || !strings.Contains(err.Error(), tt.key) {
+				t.Errorf("expected error mentioning %s, got %v", tt.key, err)
+			}
+		})
+	}
+}
+
+func TestLoad_DockerHosts(t *testing.T) {
+	clearEnv()
+	setRequiredEnv()
+	defer clearEnv()
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if endpoints := cfg.Endpoints(); len(endpoints) != 1 || endpoints[0].Name != DefaultDockerHostName || endpoints[0].Host != DefaultDockerHost {
+		t.Errorf("expected the default docker host, got %+v", endpoints)
+	}
+
+	os.Setenv("DOCKER_MODE", "standalone")
+	os.Setenv("DOCKER_HOSTS", "edge1, Edge-2")
+	os.Setenv("DOCKER_EDGE1_HOST", "tcp://edge1:2376")
+	os.Setenv("DOCKER_EDGE1_TARGET_IP", "10.0.1.1, fd00::1")
+	os.Setenv("DOCKER_EDGE_2_HOST", "ssh://edge2")
+	os.Setenv("DOCKER_EDGE_2_MODE", "swarm")
+
+	cfg, err = Load()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	endpoints := cfg.Endpoints()
+	if len(endpoints) != 2 {
+		t.Fatalf("expected 2 docker hosts, got %+v", endpoints)
+	}
+	if e := endpoints[0]; e.Name != "edge1" || e.Host != "tcp://edge1:2376" || e.Mode != "standalone" {
+		t.Errorf("unexpected first docker host: %+v", e)
+	}
+	if e := endpoints[1]; e.Name != "edge-2" || e.Host != "ssh://edge2" || e.Mode != "swarm" {
+		t.Errorf("unexpected second docker host: %+v", e)
+	}
+	if cfg.DockerHost != "tcp://edge1:2376" {
+		t.Errorf("expected DockerHost of the first host, got %s", cfg.DockerHost)
+	}
+
+	// A host without target IPs of its own uses TARGET_IP
+	if targets := cfg.TargetsFor("edge1"); len(targets) != 2 || targets[0] != "10.0.1.1" || targets[1] != "fd00::1" {
+		t.Errorf("expected edge1's own target IPs, got %v", targets)
+	}
+	if targets := cfg.TargetsFor("edge-2"); len(targets) != 1 || targets[0] != "10.0.0.1" {
+		t.Errorf("expected the global target IP for edge-2, got %v", targets)
+	}
+
+	// TARGET_IP is optional once every host has its own
+	os.Unsetenv("TARGET_IP")
+	if _, err := Load(); err == nil || !strings.Contains(err.Error(), "TARGET_IP is required") {
+		t.Errorf("expected TARGET_IP error while edge-2 has no target IPs, got %v", err)
+	}
+	os.Setenv("DOCKER_EDGE_2_TARGET_IP", "10.0.2.1")
+	if _, err := Load(); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestLoad_DockerHostsTLS(t *testing.T) {
+	clearEnv()
+	setRequiredEnv()
+	defer clearEnv()
+
+	os.Setenv("DOCKER_CERT_PATH", "/certs/default")
+	os.Setenv("DOCKER_TLS_VERIFY", "1")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if e := cfg.Endpoints()[0]; e.CertPath != "/certs/default" || !e.TLSVerify {
+		t.Errorf("expected the default host to use DOCKER_CERT_PATH, got %+v", e)
+	}
+
+	// Named hosts have TLS settings of their own and ignore the global ones
+	os.Setenv("DOCKER_HOSTS", "edge1,edge-2")
+	os.Setenv("DOCKER_EDGE1_HOST", "tcp://edge1:2376")
+	os.Setenv("DOCKER_EDGE1_CERT_PATH", "/certs/edge1")
+	os.Setenv("DOCKER_EDGE1_TLS_VERIFY", "true")
+	os.Setenv("DOCKER_EDGE_2_HOST", "ssh://edge2")
+
+	cfg, err = Load()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	endpoints := cfg.Endpoints()
+	if e := endpoints[0]; e.CertPath != "/certs/edge1" || !e.TLSVerify {
+		t.Errorf("expected edge1's own TLS settings, got %+v", e)
+	}
+	if e := endpoints[1]; e.CertPath != "" || e.TLSVerify {
+		t.Errorf("expected no TLS for edge-2, got %+v", e)
+	}
+}
+
+func TestLoad_TargetIPMode(t *testing.T) {
+	clearEnv()
+	setRequiredEnv()
+	defer clearEnv()
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.TargetIPMode != TargetIPModeStatic || cfg.TraefikService != DefaultTraefikService || cfg.TargetIPRefresh != DefaultTargetIPRefresh {
+		t.Errorf("unexpected defaults: mode %s, service %s, refresh %v", cfg.TargetIPMode, cfg.TraefikService, cfg.TargetIPRefresh)
+	}
+
+	// TARGET_IP is not required when the addresses are derived from the nodes
+	os.Unsetenv("TARGET_IP")
+	os.Setenv("TARGET_IP_MODE", "Node")
+	os.Setenv("TRAEFIK_SERVICE", "proxy_traefik")
+	os.Setenv("TARGET_IP_NODE_LABEL", "ingress-ip")
+	os.Setenv("TARGET_IP_INTERFACE", "eth0")
+	os.Setenv("TARGET_IP_REFRESH", "1m")
+
+	cfg, err = Load()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.TargetIPMode != TargetIPModeNode || cfg.TraefikService != "proxy_traefik" || cfg.TargetIPNodeLabel != "ingress-ip" ||
+		cfg.TargetIPInterface != "eth0" || cfg.TargetIPRefresh != time.Minute {
+		t.Errorf("unexpected node mode settings: %+v", cfg)
+	}
+
+	os.Setenv("RECORD_MODE", "cname")
+	os.Setenv("CNAME_TARGET", "traefik.example.com")
+	if _, err := Load(); err == nil || !strings.Contains(err.Error(), "TARGET_IP_MODE") {
+		t.Errorf("expected TARGET_IP_MODE error in cname mode, got %v", err)
+	}
+
+	os.Unsetenv("RECORD_MODE")
+	os.Setenv("TARGET_IP_MODE", "dhcp")
+	if _, err := Load(); err == nil || !strings.Contains(err.Error(), "TARGET_IP_MODE") {
+		t.Errorf("expected TARGET_IP_MODE error, got %v", err)
+	}
+}
+
+func TestLoad_TargetIPModeNetwork(t *testing.T) {
+	clearEnv()
+	setRequiredEnv()
+	defer clearEnv()
+
+	// TARGET_IP is not required when every workload uses its own address
+	os.Unsetenv("TARGET_IP")
+	os.Setenv("TARGET_IP_MODE", "network")
+
+	if _, err := Load(); err == nil || !strings.Contains(err.Error(), "TARGET_IP_NETWORK") {
+		t.Errorf("expected TARGET_IP_NETWORK error, got %v", err)
+	}
+
+	os.Setenv("TARGET_IP_NETWORK", " lan ")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.TargetIPMode != TargetIPModeNetwork || cfg.TargetIPNetwork != "lan" {
+		t.Errorf("unexpected network mode settings: mode %s, network %q", cfg.TargetIPMode, cfg.TargetIPNetwork)
+	}
+
+	os.Setenv("RECORD_MODE", "cname")
+	os.Setenv("CNAME_TARGET", "traefik.example.com")
+	if _, err := Load(); err == nil || !strings.Contains(err.Error(), "TARGET_IP_MODE 'network'") {
+		t.Errorf("expected TARGET_IP_MODE error in cname mode, got %v", err)
+	}
+}
+
+func TestLoad_DockerHostsInvalid(t *testing.T) {
+	tests := []struct {
+		name string
+		env  map[string]string
+		key  string
+	}{
+		{"missing host", map[string]string{"DOCKER_HOSTS": "edge1"}, "DOCKER_EDGE1_HOST"},
+		{"invalid mode", map[string]string{"DOCKER_HOSTS": "edge1", "DOCKER_EDGE1_HOST": "tcp://edge1:2376", "DOCKER_EDGE1_MODE": "kubernetes"}, "DOCKER_EDGE1_MODE"},
+		{"invalid target", map[string]string{"DOCKER_HOSTS": "edge1", "DOCKER_EDGE1_HOST": "tcp://edge1:2376", "DOCKER_EDGE1_TARGET_IP": "edge1"}, "DOCKER_EDGE1_TARGET_IP"},
+		{"invalid name", map[string]string{"DOCKER_HOSTS": "edge.1"}, "DOCKER_HOSTS"},
+		{"invalid tls verify", map[string]string{"DOCKER_HOSTS": "edge1", "DOCKER_EDGE1_HOST": "tcp://edge1:2376", "DOCKER_EDGE1_CERT_PATH": "/certs/edge1", "DOCKER_EDGE1_TLS_VERIFY": "ture"}, "DOCKER_EDGE1_TLS_VERIFY"},
+		{"tls verify without certs", map[string]string{"DOCKER_HOSTS": "edge1", "DOCKER_EDGE1_HOST": "tcp://edge1:2376", "DOCKER_EDGE1_TLS_VERIFY": "true"}, "DOCKER_EDGE1_CERT_PATH"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clearEnv()
+			setRequiredEnv()
+			defer clearEnv()
+			for k, v := range tt.env {
+				os.Setenv(k, v)
+			}
+
+			if _, err := Load(); err == nil || !strings.Contains(err.Error(), tt.key) {
+				t.Errorf("expected error mentioning %s, got %v", tt.key, err)
+			}
+		})
+	}
+}
+
+func TestLoad_TechnitiumTLS(t *testing.T) {
+	clearEnv()
+	setRequiredEnv()
+	defer clearEnv()
+
+	dir := t.TempDir()
+	caFile := filepath.Join(dir, "ca.pem")
+	keyFile := filepath.Join(dir, "client.key")
+	if err := os.WriteFile(caFile, []byte("CA PEM\n"), 0600); err != nil {
+		t.Fatalf("failed to write CA file: %v", err)
+	}
+	if err := os.WriteFile(keyFile, []byte("KEY PEM\n"), 0600); err != nil {
+		t.Fatalf("failed to write key file: %v", err)
+	}
+
+	os.Setenv("TECHNITIUM_TLS_CA_CERT_FILE", caFile)
+	os.Setenv("TECHNITIUM_TLS_CLIENT_CERT", "CERT PEM")
+	os.Setenv("TECHNITIUM_TLS_CLIENT_KEY_FILE", keyFile)
+	os.Setenv("TECHNITIUM_TLS_SERVER_NAME", "dns.internal")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := TLSConfig{CACert: "CA PEM", ClientCert: "CERT PEM", ClientKey: "KEY PEM", ServerName: "dns.internal"}
+	if cfg.TechnitiumTLS != expected {
+		t.Errorf("TechnitiumTLS = %+v, want %+v", cfg.TechnitiumTLS, expected)
+	}
+	if cfg.Servers()[0].TLS != expected {
+		t.Errorf("expected the TLS settings on the default server, got %+v", cfg.Servers()[0].TLS)
+	}
+
+	// Named servers inherit each unset setting
+	os.Setenv("TECHNITIUM_SERVERS", "dns1")
+	os.Setenv("TECHNITIUM_DNS1_URL", "https://dns1.example.com")
+	os.Setenv("TECHNITIUM_DNS1_TLS_SERVER_NAME", "dns1.internal")
+	os.Setenv("TECHNITIUM_DNS1_TLS_INSECURE_SKIP_VERIFY", "true")
+
+	cfg, err = Load()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected.ServerName = "dns1.internal"
+	expected.InsecureSkipVerify = true
+	if tls := cfg.Servers()[0].TLS; tls != expected {
+		t.Errorf("dns1 TLS = %+v, want %+v", tls, expected)
+	}
+}
+
+func TestLoad_TechnitiumTLSInvalidSettings(t *testing.T) {
+	clearEnv()
+	setRequiredEnv()
+	defer clearEnv()
+
+	// A _FILE that cannot be read is reported rather than treated as unset
+	os.Setenv("TECHNITIUM_TLS_CA_CERT_FILE", filepath.Join(t.TempDir(), "missing.pem"))
+	if _, err := Load(); err == nil || !strings.Contains(err.Error(), "TECHNITIUM_TLS_CA_CERT_FILE could not be read") {
+		t.Errorf("expected error for unreadable TECHNITIUM_TLS_CA_CERT_FILE, got %v", err)
+	}
+	os.Unsetenv("TECHNITIUM_TLS_CA_CERT_FILE")
+
+	// A misspelled boolean is rejected instead of read as false
+	os.Setenv("TECHNITIUM_TLS_INSECURE_SKIP_VERIFY", "treu")
+	if _, err := Load(); err == nil || !strings.Contains(err.Error(), "TECHNITIUM_TLS_INSECURE_SKIP_VERIFY must be a boolean") {
+		t.Errorf("expected error for invalid TECHNITIUM_TLS_INSECURE_SKIP_VERIFY, got %v", err)
+	}
+}
+
+func TestLoad_TechnitiumTLSClientCertWithoutKey(t *testing.T) {
+	clearEnv()
+	setRequiredEnv()
+	defer clearEnv()
+
+	os.Setenv("TECHNITIUM_TLS_CLIENT_CERT", "CERT PEM")
+
+	if _, err := Load(); err == nil || !strings.Contains(err.Error(), "TECHNITIUM_TLS_CLIENT_KEY") {
+		t.Errorf("expected error mentioning TECHNITIUM_TLS_CLIENT_KEY, got %v", err)
+	}
+}
+
+func TestValidate_TrimsTrailingSlash(t *testing.T) {
+	clearEnv()
+	os.Setenv("TECHNITIUM_URL", "http://dns.example.com:5380/")
+	os.Setenv("TECHNITIUM_TOKEN", "token")
+	os.Setenv("TECHNITIUM_ZONE", "example.com")
+	os.Setenv("TARGET_IP", "10.0.0.1")
+	defer clearEnv()
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("unexpected validation error: %v", err)
+	}
+
+	if cfg.TechnitiumURL != "http://dns.example.com:5380" {
+		t.Errorf("expected trailing slash to be trimmed, got %s", cfg.TechnitiumURL)
+	}
+}
+
+func TestLoad_AllowWildcards(t *testing.T) {
+	clearEnv()
+	setRequiredEnv()
+	defer clearEnv()
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.AllowWildcards != DefaultAllowWildcards {
+		t.Errorf("expected default AllowWildcards %v, got %v", DefaultAllowWildcards, cfg.AllowWildcards)
+	}
+
+	os.Setenv("ALLOW_WILDCARDS", "true")
+
+	cfg, err = Load()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !cfg.AllowWildcards {
+		t.Error("expected AllowWildcards to be enabled")
+	}
+}
+
+func TestLoad_TraefikProvider(t *testing.T) {
+	clearEnv()
+	setRequiredEnv()
+	defer clearEnv()
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !cfg.TraefikExposedByDefault {
+		t.Error("expected workloads to be exposed by default")
+	}
+	if cfg.TraefikConstraints != "" {
+		t.Errorf("expected no constraints, got %q", cfg.TraefikConstraints)
+	}
+
+	os.Setenv("TRAEFIK_EXPOSED_BY_DEFAULT", "false")
+	os.Setenv("TRAEFIK_CONSTRAINTS", " Label(`traefik.scope`, `public`) ")
+
+	cfg, err = Load()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.TraefikExposedByDefault {
+		t.Error("expected exposedByDefault to be disabled")
+	}
+	if cfg.TraefikConstraints != "Label(`traefik.scope`, `public`)" {
+		t.Errorf("unexpected constraints: %q", cfg.TraefikConstraints)
+	}
+}
+
+func TestLoad_Resilience(t *testing.T) {
+	clearEnv()
+	setRequiredEnv()
+	defer clearEnv()
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.RetryMaxAttempts != DefaultRetryMaxAttempts || cfg.RetryBaseDelay != DefaultRetryBaseDelay ||
+		cfg.RetryMaxDelay != DefaultRetryMaxDelay {
+		t.Errorf("unexpected retry defaults: %d %v %v", cfg.RetryMaxAttempts, cfg.RetryBaseDelay, cfg.RetryMaxDelay)
+	}
+	if cfg.BreakerThreshold != DefaultBreakerThreshold || cfg.BreakerCooldown != DefaultBreakerCooldown {
+		t.Errorf("unexpected breaker defaults: %d %v", cfg.BreakerThreshold, cfg.BreakerCooldown)
+	}
+	if cfg.RateLimit != DefaultRateLimit || cfg.RateBurst != DefaultRateBurst || cfg.MaxInFlight != DefaultMaxInFlight {
+		t.Errorf("unexpected throttling defaults: %v %d %d", cfg.RateLimit, cfg.RateBurst, cfg.MaxInFlight)
+	}
+
+	os.Setenv("TECHNITIUM_RETRY_ATTEMPTS", "5")
+	os.Setenv("TECHNITIUM_RETRY_BASE_DELAY", "100ms")
+	os.Setenv("TECHNITIUM_RETRY_MAX_DELAY", "2s")
+	os.Setenv("TECHNITIUM_BREAKER_THRESHOLD", "0")
+	os.Setenv("TECHNITIUM_BREAKER_COOLDOWN", "1m")
+	os.Setenv("TECHNITIUM_RATE_LIMIT", "2.5")
+	os.Setenv("TECHNITIUM_RATE_BURST", "5")
+	os.Setenv("TECHNITIUM_MAX_IN_FLIGHT", "0")
+
+	cfg, err = Load()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.RetryMaxAttempts != 5 || cfg.RetryBaseDelay != 100*time.Millisecond || cfg.RetryMaxDelay != 2*time.Second {
+		t.Errorf("unexpected retry settings: %d %v %v", cfg.RetryMaxAttempts, cfg.RetryBaseDelay, cfg.RetryMaxDelay)
+	}
+	if cfg.BreakerThreshold != 0 || cfg.BreakerCooldown != time.Minute {
+		t.Errorf("unexpected breaker settings: %d %v", cfg.BreakerThreshold, cfg.BreakerCooldown)
+	}
+	if cfg.RateLimit != 2.5 || cfg.RateBurst != 5 || cfg.MaxInFlight != 0 {
+		t.Errorf("unexpected throttling settings: %v %d %d", cfg.RateLimit, cfg.RateBurst, cfg.MaxInFlight)
+	}
+}
+
+func TestLoad_InvalidResilience(t *testing.T) {
+	tests := []struct {
+		key, value string
+	}{
+		{"TECHNITIUM_RETRY_ATTEMPTS", "0"},
+		{"TECHNITIUM_RETRY_ATTEMPTS", "many"},
+		{"TECHNITIUM_RETRY_BASE_DELAY", "fast"},
+		{"TECHNITIUM_RETRY_MAX_DELAY", "1ms"},
+		{"TECHNITIUM_BREAKER_THRESHOLD", "-1"},
+		{"TECHNITIUM_BREAKER_COOLDOWN", "-5s"},
+		{"TECHNITIUM_RATE_LIMIT", "-1"},
+		{"TECHNITIUM_RATE_LIMIT", "NaN"},
+		{"TECHNITIUM_RATE_BURST", "0"},
+		{"TECHNITIUM_MAX_IN_FLIGHT", "-1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.key+"="+tt.value, func(t *testing.T) {
+			clearEnv()
+			setRequiredEnv()
+			os.Setenv(tt.key, tt.value)
+			defer clearEnv()
+
+			if _, err := Load(); err == nil || !strings.Contains(err.Error(), tt.key) {
+				t.Errorf("expected error mentioning %s, got %v", tt.key, err)
+			}
+		})
+	}
+}
+
+func TestLoad_ZoneCheck(t *testing.T) {
+	clearEnv()
+	setRequiredEnv()
+	defer clearEnv()
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.ZoneCheck != DefaultZoneCheck || cfg.ZoneAutoCreate != DefaultZoneAutoCreate {
+		t.Errorf("unexpected zone check defaults: %s %v", cfg.ZoneCheck, cfg.ZoneAutoCreate)
+	}
+	if cfg.ZoneSOA != (ZoneSOA{}) || cfg.ZoneNameServers != nil {
+		t.Errorf("expected no SOA or NS settings by default, got %+v %v", cfg.ZoneSOA, cfg.ZoneNameServers)
+	}
+
+	os.Setenv("ZONE_CHECK", "Unready")
+	os.Setenv("ZONE_AUTO_CREATE", "true")
+	os.Setenv("ZONE_SOA_PRIMARY_NAME_SERVER", "NS1.example.com.")
+	os.Setenv("ZONE_SOA_RESPONSIBLE_PERSON", "hostmaster.example.com")
+	os.Setenv("ZONE_SOA_REFRESH", "3600")
+	os.Setenv("ZONE_SOA_MINIMUM", "300")
+	os.Setenv("ZONE_NAME_SERVERS", "ns1.example.com, ns2.example.com")
+
+	cfg, err = Load()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.ZoneCheck != ZoneCheckUnready || !cfg.ZoneAutoCreate {
+		t.Errorf("unexpected zone check settings: %s %v", cfg.ZoneCheck, cfg.ZoneAutoCreate)
+	}
+	expectedSOA := ZoneSOA{
+		PrimaryNameServer: "ns1.example.com",
+		ResponsiblePerson: "hostmaster.example.com",
+		Refresh:           3600,
+		Minimum:           300,
+	}
+	if cfg.ZoneSOA != expectedSOA {
+		t.Errorf("expected SOA %+v, got %+v", expectedSOA, cfg.ZoneSOA)
+	}
+	if strings.Join(cfg.ZoneNameServers, ",") != "ns1.example.com,ns2.example.com" {
+		t.Errorf("unexpected name servers: %v", cfg.ZoneNameServers)
+	}
+}
+
+func TestLoad_InvalidZoneCheck(t *testing.T) {
+	tests := []struct {
+		name string
+		env  map[string]string
+		key  string
+	}{
+		{"unknown mode", map[string]string{"ZONE_CHECK": "warn"}, "ZONE_CHECK"},
+		{"auto-create without check", map[string]string{"ZONE_CHECK": "off", "ZONE_AUTO_CREATE": "true"}, "ZONE_AUTO_CREATE"},
+		{"negative SOA refresh", map[string]string{"ZONE_SOA_REFRESH": "-1"}, "ZONE_SOA_REFRESH"},
+		{"non-numeric SOA expire", map[string]string{"ZONE_SOA_EXPIRE": "week"}, "ZONE_SOA_EXPIRE"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clearEnv()
+			setRequiredEnv()
+			for k, v := range tt.env {
+				os.Setenv(k, v)
+			}
+			defer clearEnv()
+
+			if _, err := Load(); err == nil || !strings.Contains(err.Error(), tt.key) {
+				t.Errorf("expected error mentioning %s, got %v", tt.key, err)
+			}
+		})
+	}
+}
+
+func TestLoad_PTRRecords(t *testing.T) {
+	clearEnv()
+	setRequiredEnv()
+	defer clearEnv()
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.PTRRecords != DefaultPTRRecords {
+		t.Errorf("expected default PTRRecords %v, got %v", DefaultPTRRecords, cfg.PTRRecords)
+	}
+
+	os.Setenv("PTR_RECORDS", "true")
+	os.Setenv("REVERSE_ZONES", "10.in-addr.arpa, 0.10.IN-ADDR.ARPA., 8.b.d.0.1.0.0.2.ip6.arpa")
+
+	cfg, err = Load()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := []string{"10.in-addr.arpa", "0.10.in-addr.arpa", "8.b.d.0.1.0.0.2.ip6.arpa"}
+	if !cfg.PTRRecords || strings.Join(cfg.ReverseZones, ",") != strings.Join(expected, ",") {
+		t.Errorf("expected PTR records in %v, got %v %v", expected, cfg.PTRRecords, cfg.ReverseZones)
+	}
+
+	zone, ok := cfg.ReverseZoneFor("1.0.0.10.in-addr.arpa")
+	if !ok || zone != "0.10.in-addr.arpa" {
+		t.Errorf("ReverseZoneFor = %q, %v; want 0.10.in-addr.arpa, true", zone, ok)
+	}
+	if _, ok := cfg.ReverseZoneFor("1.0.168.192.in-addr.arpa"); ok {
+		t.Error("expected no reverse zone for 192.168.0.1")
+	}
+}
+
+func TestLoad_InvalidPTRRecords(t *testing.T) {
+	tests := []struct {
+		name string
+		env  map[string]string
+		key  string
+	}{
+		{"missing reverse zones", map[string]string{"PTR_RECORDS": "true"}, "REVERSE_ZONES"},
+		{"forward zone", map[string]string{"PTR_RECORDS": "true", "REVERSE_ZONES": "example.com"}, "REVERSE_ZONES"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clearEnv()
+			setRequiredEnv()
+			for k, v := range tt.env {
+				os.Setenv(k, v)
+			}
+			defer clearEnv()
+
+			if _, err := Load(); err == nil || !strings.Contains(err.Error(), tt.key) {
+				t.Errorf("expected error mentioning %s, got %v", tt.key, err)
+			}
+		})
+	}
+}
+
+func TestLoad_ProvenanceTXT(t *testing.T) {
+	clearEnv()
+	setRequiredEnv()
+	defer clearEnv()
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.ProvenanceTXT != DefaultProvenanceTXT {
+		t.Errorf("expected default ProvenanceTXT %v, got %v", DefaultProvenanceTXT, cfg.ProvenanceTXT)
+	}
+
+	os.Setenv("PROVENANCE_TXT", "true")
+	cfg, err = Load()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !cfg.ProvenanceTXT {
+		t.Error("expected ProvenanceTXT to be enabled")
+	}
+}
+
+func TestLoad_OrphanCleanup(t *testing.T) {
+	clearEnv()
+	setRequiredEnv()
+	defer clearEnv()
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.OrphanCleanup != DefaultOrphanCleanup {
+		t.Errorf("expected default OrphanCleanup %v, got %v", DefaultOrphanCleanup, cfg.OrphanCleanup)
+	}
+	if cfg.OwnerID != DefaultOwnerID {
+		t.Errorf("expected default OwnerID %s, got %s", DefaultOwnerID, cfg.OwnerID)
+	}
+
+	os.Setenv("ORPHAN_CLEANUP", "true")
+	os.Setenv("OWNER_ID", "swarm-prod")
+
+	cfg, err = Load()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !cfg.OrphanCleanup {
+		t.Error("expected OrphanCleanup to be enabled")
+	}
+	if cfg.OwnerID != "swarm-prod" {
+		t.Errorf("expected OwnerID swarm-prod, got %s", cfg.OwnerID)
+	}
+}
+
+func TestLoad_InvalidOwnerID(t *testing.T) {
+	clearEnv()
+	setRequiredEnv()
+	os.Setenv("OWNER_ID", "bad,owner")
+	defer clearEnv()
+
+	_, err := Load()
+	if err == nil {
+		t.Error("expected error for invalid OWNER_ID")
+	}
+}
+
+func TestLoad_MultipleZones(t *testing.T) {
+	clearEnv()
+	setRequiredEnv()
+	os.Setenv("TECHNITIUM_ZONE", "Example.com.")
+	os.Setenv("TECHNITIUM_ZONES", "lab.example.com, example.net,example.com")
+	defer clearEnv()
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := []string{"example.com", "lab.example.com", "example.net"}
+	if strings.Join(cfg.TechnitiumZones, ",") != strings.Join(expected, ",") {
+		t.Errorf("expected zones %v, got %v", expected, cfg.TechnitiumZones)
+	}
+	if cfg.TechnitiumZone != "example.com" {
+		t.Errorf("expected primary zone example.com, got %s", cfg.TechnitiumZone)
+	}
+}
+
+func TestLoad_ZonesOnly(t *testing.T) {
+	clearEnv()
+	setRequiredEnv()
+	os.Unsetenv("TECHNITIUM_ZONE")
+	os.Setenv("TECHNITIUM_ZONES", "example.net")
+	defer clearEnv()
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.TechnitiumZone != "example.net" {
+		t.Errorf("expected primary zone example.net, got %s", cfg.TechnitiumZone)
+	}
+}
+
+func TestZoneFor(t *testing.T) {
+	cfg := &Config{
+		TechnitiumZones: []string{"example.com", "lab.example.com", "example.net"},
+	}
+
+	tests := []struct {
+		hostname string
+		zone     string
+		ok       bool
+	}{
+		{"app.example.com", "example.com", true},
+		{"app.lab.example.com", "lab.example.com", true},
+		{"APP.Lab.Example.com.", "lab.example.com", true},
+		{"lab.example.com", "lab.example.com", true},
+		{"app.example.net", "example.net", true},
+		{"app.notexample.com", "", false},
+		{"app.example.org", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.hostname, func(t *testing.T) {
+			zone, ok := cfg.ZoneFor(tt.hostname)
+			if zone != tt.zone || ok != tt.ok {
+				t.Errorf("ZoneFor(%q) = %q, %v; want %q, %v", tt.hostname, zone, ok, tt.zone, tt.ok)
+			}
+		})
+	}
+}
+
+func TestZoneFor_FallsBackToSingleZone(t *testing.T) {
+	cfg := &Config{TechnitiumZone: "example.com"}
+
+	zone, ok := cfg.ZoneFor("app.example.com")
+	if !ok || zone != "example.com" {
+		t.Errorf("ZoneFor = %q, %v; want example.com, true", zone, ok)
 	}
 }
 
@@ -442,11 +1354,35 @@ func clearEnv() {
 	envVars := []string{
 		"TECHNITIUM_URL", "TECHNITIUM_URL_FILE",
 		"TECHNITIUM_TOKEN", "TECHNITIUM_TOKEN_FILE",
+		"TECHNITIUM_USERNAME", "TECHNITIUM_USERNAME_FILE",
+		"TECHNITIUM_PASSWORD", "TECHNITIUM_PASSWORD_FILE",
+		"TECHNITIUM_SERVERS", "TECHNITIUM_DNS1_URL", "TECHNITIUM_DNS1_TOKEN",
+		"TECHNITIUM_DNS_2_URL", "TECHNITIUM_DNS_2_USERNAME", "TECHNITIUM_DNS_2_PASSWORD",
+		"TECHNITIUM_TLS_CA_CERT", "TECHNITIUM_TLS_CA_CERT_FILE",
+		"TECHNITIUM_TLS_CLIENT_CERT", "TECHNITIUM_TLS_CLIENT_CERT_FILE",
+		"TECHNITIUM_TLS_CLIENT_KEY", "TECHNITIUM_TLS_CLIENT_KEY_FILE",
+		"TECHNITIUM_TLS_SERVER_NAME", "TECHNITIUM_TLS_INSECURE_SKIP_VERIFY",
+		"TECHNITIUM_DNS1_TLS_SERVER_NAME", "TECHNITIUM_DNS1_TLS_INSECURE_SKIP_VERIFY",
 		"TECHNITIUM_ZONE", "TECHNITIUM_ZONE_FILE",
+		"TECHNITIUM_ZONES", "TECHNITIUM_ZONES_FILE",
 		"TARGET_IP", "TARGET_IP_FILE",
+		"TARGET_IP_MODE", "TRAEFIK_SERVICE", "TARGET_IP_NODE_LABEL", "TARGET_IP_INTERFACE", "TARGET_IP_REFRESH", "TARGET_IP_NETWORK",
+		"RECORD_MODE", "CNAME_TARGET", "TRAEFIK_RULE_SYNTAX", "ALLOW_WILDCARDS",
+		"TRAEFIK_EXPOSED_BY_DEFAULT", "TRAEFIK_CONSTRAINTS",
+		"PTR_RECORDS", "REVERSE_ZONES",
+		"TECHNITIUM_RETRY_ATTEMPTS", "TECHNITIUM_RETRY_BASE_DELAY", "TECHNITIUM_RETRY_MAX_DELAY",
+		"TECHNITIUM_BREAKER_THRESHOLD", "TECHNITIUM_BREAKER_COOLDOWN",
+		"TECHNITIUM_RATE_LIMIT", "TECHNITIUM_RATE_BURST", "TECHNITIUM_MAX_IN_FLIGHT",
+		"ZONE_CHECK", "ZONE_AUTO_CREATE", "ZONE_NAME_SERVERS",
+		"ZONE_SOA_PRIMARY_NAME_SERVER", "ZONE_SOA_RESPONSIBLE_PERSON",
+		"ZONE_SOA_REFRESH", "ZONE_SOA_RETRY", "ZONE_SOA_EXPIRE", "ZONE_SOA_MINIMUM",
 		"TTL", "INCLUDE_PATTERN", "EXCLUDE_PATTERN",
-		"DOCKER_HOST", "DOCKER_MODE",
+		"DOCKER_HOST", "DOCKER_MODE", "DOCKER_HOSTS", "DOCKER_CERT_PATH", "DOCKER_TLS_VERIFY",
+		"DOCKER_EDGE1_HOST", "DOCKER_EDGE1_MODE", "DOCKER_EDGE1_TARGET_IP",
+		"DOCKER_EDGE1_CERT_PATH", "DOCKER_EDGE1_TLS_VERIFY",
+		"DOCKER_EDGE_2_HOST", "DOCKER_EDGE_2_MODE", "DOCKER_EDGE_2_TARGET_IP",
 		"RECONCILE_ON_STARTUP", "DRY_RUN",
+		"ORPHAN_CLEANUP", "OWNER_ID", "PROVENANCE_TXT",
 		"HEALTH_PORT", "LOG_LEVEL",
 	}
 	for _, v := range envVars {
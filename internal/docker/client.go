@@ -3,8 +3,11 @@ package docker
 
 import (
 	"context"
+	"crypto/tls"
 	"fmt"
 	"log/slog"
+	"net/http"
+	"sync"
 
 	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/api/types/container"
@@ -40,8 +43,16 @@ type Container struct {
 // Client wraps the Docker client with convenience methods.
 type Client struct {
 	docker *client.Client
-	mode   Mode
+	name   string
 	logger *slog.Logger
+
+	// deferDetection lets NewClient succeed while the mode cannot be detected
+	deferDetection bool
+
+	tlsConfig *tls.Config
+
+	mu   sync.RWMutex
+	mode Mode
 }
 
 // ClientOption is a functional option for configuring the Client.
@@ -54,6 +65,14 @@ func WithLogger(logger *slog.Logger) ClientOption {
 	}
 }
 
+// WithName sets the name the host is known by in logs, metrics and the Host of
+// its workloads.
+func WithName(name string) ClientOption {
+	return func(c *Client) {
+		c.name = name
+	}
+}
+
 // WithMode forces a specific Docker mode instead of auto-detecting.
 func WithMode(mode Mode) ClientOption {
 	return func(c *Client) {
@@ -61,12 +80,36 @@ func WithMode(mode Mode) ClientOption {
 	}
 }
 
+// WithDeferredDetection lets NewClient succeed when the daemon cannot be reached to
+// detect its mode. The mode stays unknown until Connect, which ListWorkloads calls,
+// detects it.
+func WithDeferredDetection() ClientOption {
+	return func(c *Client) {
+		c.deferDetection = true
+	}
+}
+
 // NewClient creates a new Docker client.
-// If host is empty, uses the DOCKER_HOST environment variable or default socket.
+// If host is empty, uses the default socket. The DOCKER_TLS_VERIFY and
+// DOCKER_CERT_PATH environment variables are not read; TLS is configured per
+// host with WithTLSConfig.
 func NewClient(ctx context.Context, host string, opts ...ClientOption) (*Client, error) {
+	c := &Client{
+		logger: slog.Default(),
+	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
 	var dockerOpts []client.Opt
 
-	dockerOpts = append(dockerOpts, client.FromEnv)
+	if c.tlsConfig != nil {
+		dockerOpts = append(dockerOpts, client.WithHTTPClient(&http.Client{
+			Transport:     &http.Transport{TLSClientConfig: c.tlsConfig},
+			CheckRedirect: client.CheckRedirect,
+		}))
+	}
 	dockerOpts = append(dockerOpts, client.WithAPIVersionNegotiation())
 
 	if host != "" {
@@ -77,33 +120,47 @@ func NewClient(ctx context.Context, host string, opts ...ClientOption) (*Client,
 	if err != nil {
 		return nil, fmt.Errorf("creating docker client: %w", err)
 	}
-
-	c := &Client{
-		docker: dockerClient,
-		logger: slog.Default(),
-	}
-
-	for _, opt := range opts {
-		opt(c)
-	}
+	c.docker = dockerClient
 
 	// Auto-detect mode if not explicitly set
-	if c.mode == "" {
-		detectedMode, err := c.detectMode(ctx)
-		if err != nil {
+	if _, err := c.Connect(ctx); err != nil {
+		if !c.deferDetection {
 			dockerClient.Close()
-			return nil, fmt.Errorf("detecting docker mode: %w", err)
+			return nil, err
 		}
-		c.mode = detectedMode
+		c.logger.Warn("docker host unreachable, deferring mode detection",
+			slog.String("error", err.Error()),
+		)
+		return c, nil
 	}
 
 	c.logger.Info("docker client initialized",
-		slog.String("mode", string(c.mode)),
+		slog.String("mode", string(c.Mode())),
 	)
 
 	return c, nil
 }
 
+// Connect returns the Docker mode, detecting it first if it is not known yet.
+func (c *Client) Connect(ctx context.Context) (Mode, error) {
+	if mode := c.Mode(); mode != "" {
+		return mode, nil
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.mode != "" {
+		return c.mode, nil
+	}
+
+	mode, err := c.detectMode(ctx)
+	if err != nil {
+		return "", fmt.Errorf("detecting docker mode: %w", err)
+	}
+	c.mode = mode
+	return mode, nil
+}
+
 // detectMode determines whether Docker is running in Swarm or standalone mode.
 func (c *Client) detectMode(ctx context.Context) (Mode, error) {
 	info, err := c.docker.Info(ctx)
@@ -127,8 +184,15 @@ func (c *Client) detectMode(ctx context.Context) (Mode, error) {
 	return ModeStandalone, nil
 }
 
-// Mode returns the detected Docker mode.
+// Name returns the name the host is known by.
+func (c *Client) Name() string {
+	return c.name
+}
+
+// Mode returns the detected Docker mode, or an empty Mode while detection is deferred.
 func (c *Client) Mode() Mode {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
 	return c.mode
 }
 
@@ -146,7 +210,7 @@ func (c *Client) Close() error {
 // ListServices returns all Swarm services with their labels.
 // Only valid in Swarm mode.
 func (c *Client) ListServices(ctx context.Context) ([]Service, error) {
-	if c.mode != ModeSwarm {
+	if c.Mode() != ModeSwarm {
 		return nil, fmt.Errorf("ListServices only available in swarm mode")
 	}
 
@@ -174,7 +238,7 @@ func (c *Client) ListServices(ctx context.Context) ([]Service, error) {
 // ListContainers returns all running containers with their labels.
 // Only valid in standalone mode.
 func (c *Client) ListContainers(ctx context.Context) ([]Container, error) {
-	if c.mode != ModeStandalone {
+	if c.Mode() != ModeStandalone {
 		return nil, fmt.Errorf("ListContainers only available in standalone mode")
 	}
 
@@ -214,7 +278,7 @@ func (c *Client) ListContainers(ctx context.Context) ([]Container, error) {
 
 // GetServiceLabels returns the labels for a specific Swarm service by ID.
 func (c *Client) GetServiceLabels(ctx context.Context, serviceID string) (map[string]string, error) {
-	if c.mode != ModeSwarm {
+	if c.Mode() != ModeSwarm {
 		return nil, fmt.Errorf("GetServiceLabels only available in swarm mode")
 	}
 
@@ -228,7 +292,7 @@ func (c *Client) GetServiceLabels(ctx context.Context, serviceID string) (map[st
 
 // GetContainerLabels returns the labels for a specific container by ID.
 func (c *Client) GetContainerLabels(ctx context.Context, containerID string) (map[string]string, error) {
-	if c.mode != ModeStandalone {
+	if c.Mode() != ModeStandalone {
 		return nil, fmt.Errorf("GetContainerLabels only available in standalone mode")
 	}
 
@@ -247,12 +311,33 @@ type Workload struct {
 	Name   string
 	Labels map[string]string
 	Type   string // "service" or "container"
+	Host   string // Name of the Docker host the workload was listed on
+}
+
+// Labels identifying the stack or Compose project a workload was deployed with.
+const (
+	stackNamespaceLabel = "com.docker.stack.namespace"
+	composeProjectLabel = "com.docker.compose.project"
+)
+
+// Stack returns the Swarm stack namespace or Compose project the workload belongs to,
+// or an empty string if it was deployed on its own.
+func (w Workload) Stack() string {
+	if stack := w.Labels[stackNamespaceLabel]; stack != "" {
+		return stack
+	}
+	return w.Labels[composeProjectLabel]
 }
 
 // ListWorkloads returns all workloads (services in Swarm mode, containers in standalone).
 // Provides a unified interface regardless of Docker mode.
 func (c *Client) ListWorkloads(ctx context.Context) ([]Workload, error) {
-	if c.mode == ModeSwarm {
+	mode, err := c.Connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if mode == ModeSwarm {
 		services, err := c.ListServices(ctx)
 		if err != nil {
 			return nil, err
@@ -265,6 +350,7 @@ func (c *Client) ListWorkloads(ctx context.Context) ([]Workload, error) {
 				Name:   svc.Name,
 				Labels: svc.Labels,
 				Type:   "service",
+				Host:   c.name,
 			})
 		}
 		return workloads, nil
@@ -282,6 +368,7 @@ func (c *Client) ListWorkloads(ctx context.Context) ([]Workload, error) {
 			Name:   ctr.Name,
 			Labels: ctr.Labels,
 			Type:   "container",
+			Host:   c.name,
 		})
 	}
 	return workloads, nil
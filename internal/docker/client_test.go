@@ -3,8 +3,13 @@ package docker
 
 import (
 	"context"
+	"encoding/json"
 	"log/slog"
+	"net/http"
+	"net/http/httptest"
 	"os"
+	"strings"
+	"sync/atomic"
 	"testing"
 )
 
@@ -104,6 +109,29 @@ func TestWorkloadStruct(t *testing.T) {
 	}
 }
 
+// TestWorkloadStack verifies the stack is read from Swarm and Compose labels.
+func TestWorkloadStack(t *testing.T) {
+	tests := []struct {
+		name   string
+		labels map[string]string
+		want   string
+	}{
+		{"swarm stack", map[string]string{"com.docker.stack.namespace": "media"}, "media"},
+		{"compose project", map[string]string{"com.docker.compose.project": "home"}, "home"},
+		{"stack wins over project", map[string]string{"com.docker.stack.namespace": "media", "com.docker.compose.project": "home"}, "media"},
+		{"standalone", map[string]string{"env": "dev"}, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := Workload{Labels: tt.labels}
+			if got := w.Stack(); got != tt.want {
+				t.Errorf("Stack() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
 // TestWithLogger verifies the logger option works correctly.
 func TestWithLogger(t *testing.T) {
 	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
@@ -140,6 +168,47 @@ func TestWithMode(t *testing.T) {
 	}
 }
 
+// TestWithName verifies the name option works correctly.
+func TestWithName(t *testing.T) {
+	c := &Client{}
+	WithName("edge1")(c)
+
+	if c.Name() != "edge1" {
+		t.Errorf("WithName did not set the name correctly: got %s", c.Name())
+	}
+}
+
+// TestListWorkloads_TagsHost verifies workloads carry the name of the host they were listed on.
+func TestListWorkloads_TagsHost(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("API-Version", "1.43")
+		if !strings.HasSuffix(r.URL.Path, "/containers/json") {
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode([]map[string]interface{}{
+			{"Id": "abc123", "Names": []string{"/whoami"}, "Labels": map[string]string{"traefik.enable": "true"}},
+		})
+	}))
+	defer server.Close()
+
+	c, err := NewClient(context.Background(), "tcp://"+strings.TrimPrefix(server.URL, "http://"),
+		WithName("edge1"), WithMode(ModeStandalone))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer c.Close()
+
+	workloads, err := c.ListWorkloads(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(workloads) != 1 || workloads[0].Name != "whoami" || workloads[0].Host != "edge1" {
+		t.Errorf("expected whoami listed on edge1, got %+v", workloads)
+	}
+}
+
 // TestNewClient_InvalidHost tests error handling for invalid Docker hosts.
 func TestNewClient_InvalidHost(t *testing.T) {
 	ctx := context.Background()
@@ -156,6 +225,51 @@ func TestNewClient_InvalidHost(t *testing.T) {
 	}
 }
 
+// TestNewClient_DeferredDetection verifies a host that is down at startup is detected
+// once it becomes reachable.
+func TestNewClient_DeferredDetection(t *testing.T) {
+	var up atomic.Bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("API-Version", "1.43")
+		if !up.Load() {
+			http.Error(w, `{"message": "daemon unavailable"}`, http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if strings.HasSuffix(r.URL.Path, "/containers/json") {
+			w.Write([]byte("[]"))
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"Swarm": map[string]interface{}{"LocalNodeState": "inactive"}})
+	}))
+	defer server.Close()
+	host := "tcp://" + strings.TrimPrefix(server.URL, "http://")
+
+	if _, err := NewClient(context.Background(), host); err == nil {
+		t.Fatal("expected error without deferred detection")
+	}
+
+	c, err := NewClient(context.Background(), host, WithDeferredDetection())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer c.Close()
+	if c.Mode() != "" {
+		t.Errorf("expected an unknown mode, got %s", c.Mode())
+	}
+	if _, err := c.ListWorkloads(context.Background()); err == nil {
+		t.Error("expected listing to fail while the host is down")
+	}
+
+	up.Store(true)
+	if _, err := c.ListWorkloads(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if c.Mode() != ModeStandalone {
+		t.Errorf("expected standalone mode once reachable, got %s", c.Mode())
+	}
+}
+
 // TestListServices_WrongMode tests that ListServices fails in standalone mode.
 func TestListServices_WrongMode(t *testing.T) {
 	c := &Client{
@@ -0,0 +1,102 @@
+package docker
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net"
+	"slices"
+
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/api/types/swarm"
+)
+
+// NetworkAddresses returns the addresses a workload has on the named Docker network,
+// sorted, such as those of a container on a macvlan or ipvlan network. The network may
+// be given by name or ID. A container's addresses are taken from container inspect; a
+// service's are those of its running tasks, one per replica.
+func (c *Client) NetworkAddresses(ctx context.Context, workload Workload, network string) ([]string, error) {
+	var addresses []string
+	var err error
+	if c.Mode() == ModeSwarm {
+		addresses, err = c.serviceNetworkAddresses(ctx, workload.ID, network)
+	} else {
+		addresses, err = c.containerNetworkAddresses(ctx, workload.ID, network)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(addresses) == 0 {
+		return nil, fmt.Errorf("%s %s has no address on network %s", workload.Type, workload.Name, network)
+	}
+	slices.Sort(addresses)
+
+	c.logger.Debug("resolved workload network addresses",
+		slog.String("workload", workload.Name),
+		slog.String("network", network),
+		slog.Any("addresses", addresses),
+	)
+
+	return addresses, nil
+}
+
+// containerNetworkAddresses returns the IPv4 and global IPv6 addresses of a container
+// on a network.
+func (c *Client) containerNetworkAddresses(ctx context.Context, containerID, network string) ([]string, error) {
+	ctr, err := c.docker.ContainerInspect(ctx, containerID)
+	if err != nil {
+		return nil, fmt.Errorf("inspecting container %s: %w", containerID, err)
+	}
+	if ctr.NetworkSettings == nil {
+		return nil, nil
+	}
+
+	var addresses []string
+	for name, endpoint := range ctr.NetworkSettings.Networks {
+		if endpoint == nil || (name != network && endpoint.NetworkID != network) {
+			continue
+		}
+		for _, addr := range []string{endpoint.IPAddress, endpoint.GlobalIPv6Address} {
+			if ip := net.ParseIP(addr); ip != nil && !slices.Contains(addresses, ip.String()) {
+				addresses = append(addresses, ip.String())
+			}
+		}
+	}
+	return addresses, nil
+}
+
+// serviceNetworkAddresses returns the addresses the running tasks of a service have on
+// a network.
+func (c *Client) serviceNetworkAddresses(ctx context.Context, serviceID, network string) ([]string, error) {
+	tasks, err := c.docker.TaskList(ctx, swarm.TaskListOptions{
+		Filters: filters.NewArgs(
+			filters.Arg("service", serviceID),
+			filters.Arg("desired-state", "running"),
+		),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("listing tasks of service %s: %w", serviceID, err)
+	}
+
+	var addresses []string
+	for _, task := range tasks {
+		if task.Status.State != swarm.TaskStateRunning {
+			continue
+		}
+		for _, attachment := range task.NetworksAttachments {
+			if attachment.Network.Spec.Name != network && attachment.Network.ID != network {
+				continue
+			}
+			for _, cidr := range attachment.Addresses {
+				ip, _, err := net.ParseCIDR(cidr)
+				if err != nil {
+					ip = net.ParseIP(cidr)
+				}
+				if ip != nil && !slices.Contains(addresses, ip.String()) {
+					addresses = append(addresses, ip.String())
+				}
+			}
+		}
+	}
+	return addresses, nil
+}
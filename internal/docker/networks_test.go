@@ -0,0 +1,73 @@
+package docker
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestNetworkAddresses_Container(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("API-Version", "1.43")
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"Id": "abc123",
+			"NetworkSettings": map[string]interface{}{"Networks": map[string]interface{}{
+				"bridge": map[string]interface{}{"NetworkID": "net-bridge", "IPAddress": "172.17.0.5"},
+				"lan":    map[string]interface{}{"NetworkID": "net-lan", "IPAddress": "192.168.1.50", "GlobalIPv6Address": "2001:DB8::50"},
+			}},
+		})
+	}))
+	defer server.Close()
+
+	c, err := NewClient(context.Background(), "tcp://"+strings.TrimPrefix(server.URL, "http://"), WithMode(ModeStandalone))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer c.Close()
+
+	nas := Workload{ID: "abc123", Name: "nas", Type: "container"}
+	for _, network := range []string{"lan", "net-lan"} {
+		addresses, err := c.NetworkAddresses(context.Background(), nas, network)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if strings.Join(addresses, ",") != "192.168.1.50,2001:db8::50" {
+			t.Errorf("expected the lan addresses for %s, got %v", network, addresses)
+		}
+	}
+
+	if _, err := c.NetworkAddresses(context.Background(), nas, "iot"); err == nil || !strings.Contains(err.Error(), "no address on network iot") {
+		t.Errorf("expected error for a network the container is not attached to, got %v", err)
+	}
+}
+
+func TestNetworkAddresses_Service(t *testing.T) {
+	task := func(state, network, address string) map[string]interface{} {
+		return map[string]interface{}{
+			"NodeID": "node1",
+			"Status": map[string]interface{}{"State": state},
+			"NetworksAttachments": []map[string]interface{}{{
+				"Network":   map[string]interface{}{"ID": "net-" + network, "Spec": map[string]interface{}{"Name": network}},
+				"Addresses": []string{address},
+			}},
+		}
+	}
+	c := newFakeSwarm(t, []map[string]interface{}{
+		task("running", "lan", "192.168.1.61/24"),
+		task("running", "lan", "192.168.1.60/24"),
+		task("shutdown", "lan", "192.168.1.59/24"),
+		task("running", "ingress", "10.0.0.5/24"),
+	}, nil)
+
+	addresses, err := c.NetworkAddresses(context.Background(), Workload{ID: "svc1", Name: "web", Type: "service"}, "lan")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if strings.Join(addresses, ",") != "192.168.1.60,192.168.1.61" {
+		t.Errorf("expected the lan addresses of the running tasks, got %v", addresses)
+	}
+}
@@ -0,0 +1,187 @@
+package docker
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/api/types/swarm"
+	"github.com/docker/docker/client"
+)
+
+// ServiceNodeAddresses returns the addresses of the swarm nodes running a task of the
+// named service, sorted. A node's address is read from its nodeLabel label when set,
+// which may hold a comma-separated list, and is otherwise the address the node joined
+// the swarm with. Only valid in Swarm mode.
+func (c *Client) ServiceNodeAddresses(ctx context.Context, service, nodeLabel string) ([]string, error) {
+	if c.Mode() != ModeSwarm {
+		return nil, fmt.Errorf("ServiceNodeAddresses only available in swarm mode")
+	}
+
+	tasks, err := c.docker.TaskList(ctx, swarm.TaskListOptions{
+		Filters: filters.NewArgs(
+			filters.Arg("service", service),
+			filters.Arg("desired-state", "running"),
+		),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("listing tasks of service %s: %w", service, err)
+	}
+
+	running := make(map[string]struct{})
+	for _, task := range tasks {
+		if task.Status.State == swarm.TaskStateRunning && task.NodeID != "" {
+			running[task.NodeID] = struct{}{}
+		}
+	}
+	if len(running) == 0 {
+		return nil, fmt.Errorf("service %s has no running tasks", service)
+	}
+
+	nodes, err := c.docker.NodeList(ctx, swarm.NodeListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("listing nodes: %w", err)
+	}
+
+	var addresses []string
+	for _, node := range nodes {
+		if _, ok := running[node.ID]; !ok {
+			continue
+		}
+
+		nodeAddresses, err := nodeAddresses(node, nodeLabel)
+		if err != nil {
+			return nil, fmt.Errorf("node %s: %w", node.Description.Hostname, err)
+		}
+		for _, addr := range nodeAddresses {
+			if !slices.Contains(addresses, addr) {
+				addresses = append(addresses, addr)
+			}
+		}
+	}
+	slices.Sort(addresses)
+
+	c.logger.Debug("resolved service node addresses",
+		slog.String("service", service),
+		slog.Int("nodes", len(running)),
+		slog.Any("addresses", addresses),
+	)
+
+	return addresses, nil
+}
+
+// nodeAddresses returns the addresses of a swarm node: those in its label nodeLabel,
+// or its status address. Managers that report 0.0.0.0 as their status address fall
+// back to their manager address.
+func nodeAddresses(node swarm.Node, nodeLabel string) ([]string, error) {
+	if value := node.Spec.Labels[nodeLabel]; nodeLabel != "" && value != "" {
+		var addresses []string
+		for _, entry := range strings.Split(value, ",") {
+			ip := net.ParseIP(strings.TrimSpace(entry))
+			if ip == nil {
+				return nil, fmt.Errorf("label %s is not a valid IP address: %q", nodeLabel, entry)
+			}
+			addresses = append(addresses, ip.String())
+		}
+		return addresses, nil
+	}
+
+	addr := node.Status.Addr
+	if (addr == "" || addr == "0.0.0.0") && node.ManagerStatus != nil {
+		if host, _, err := net.SplitHostPort(node.ManagerStatus.Addr); err == nil {
+			addr = host
+		}
+	}
+	ip := net.ParseIP(addr)
+	if ip == nil || ip.IsUnspecified() {
+		return nil, fmt.Errorf("no usable address reported: %q", node.Status.Addr)
+	}
+	return []string{ip.String()}, nil
+}
+
+// HostAddresses returns the IPv4 addresses of a standalone Docker host, sorted: those
+// of the network interface iface when the daemon is reached through a local socket,
+// which requires the companion to share the host's network, or those the daemon's
+// host name resolves to when it is remote.
+func (c *Client) HostAddresses(ctx context.Context, iface string) ([]string, error) {
+	daemon, err := client.ParseHostURL(c.docker.DaemonHost())
+	if err != nil {
+		return nil, fmt.Errorf("parsing docker host: %w", err)
+	}
+
+	var ips []net.IP
+	switch daemon.Scheme {
+	case "unix", "npipe":
+		if iface == "" {
+			return nil, fmt.Errorf("a network interface is required to derive the address of a local docker host")
+		}
+		ips, err = interfaceIPs(iface)
+	default:
+		ips, err = lookupIPs(ctx, daemon)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var addresses []string
+	for _, ip := range ips {
+		v4 := ip.To4()
+		if v4 == nil || v4.IsLoopback() || v4.IsLinkLocalUnicast() {
+			continue
+		}
+		if addr := v4.String(); !slices.Contains(addresses, addr) {
+			addresses = append(addresses, addr)
+		}
+	}
+	slices.Sort(addresses)
+
+	return addresses, nil
+}
+
+// interfaceIPs returns the addresses assigned to the named network interface.
+func interfaceIPs(name string) ([]net.IP, error) {
+	iface, err := net.InterfaceByName(name)
+	if err != nil {
+		return nil, fmt.Errorf("finding interface %s: %w", name, err)
+	}
+	addrs, err := iface.Addrs()
+	if err != nil {
+		return nil, fmt.Errorf("listing addresses of interface %s: %w", name, err)
+	}
+
+	var ips []net.IP
+	for _, addr := range addrs {
+		if ipNet, ok := addr.(*net.IPNet); ok {
+			ips = append(ips, ipNet.IP)
+		}
+	}
+	return ips, nil
+}
+
+// lookupIPs resolves the host name of a remote daemon URL such as tcp://edge1:2376
+// or ssh://user@edge1.
+func lookupIPs(ctx context.Context, daemon *url.URL) ([]net.IP, error) {
+	host := daemon.Host
+	if i := strings.LastIndex(host, "@"); i >= 0 {
+		host = host[i+1:]
+	}
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	host = strings.Trim(host, "[]")
+
+	if ip := net.ParseIP(host); ip != nil {
+		return []net.IP{ip}, nil
+	}
+
+	ips, err := net.DefaultResolver.LookupIP(ctx, "ip", host)
+	if err != nil {
+		return nil, fmt.Errorf("resolving docker host %s: %w", host, err)
+	}
+	return ips, nil
+}
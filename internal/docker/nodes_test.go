@@ -0,0 +1,123 @@
+package docker
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// newFakeSwarm starts a fake swarm manager answering task and node listings.
+func newFakeSwarm(t *testing.T, tasks, nodes []map[string]interface{}) *Client {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("API-Version", "1.43")
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/tasks"):
+			json.NewEncoder(w).Encode(tasks)
+		case strings.HasSuffix(r.URL.Path, "/nodes"):
+			json.NewEncoder(w).Encode(nodes)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	c, err := NewClient(context.Background(), "tcp://"+strings.TrimPrefix(server.URL, "http://"), WithMode(ModeSwarm))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	t.Cleanup(func() { c.Close() })
+	return c
+}
+
+func fakeTask(nodeID, state string) map[string]interface{} {
+	return map[string]interface{}{"NodeID": nodeID, "Status": map[string]interface{}{"State": state}}
+}
+
+func TestServiceNodeAddresses(t *testing.T) {
+	tasks := []map[string]interface{}{
+		fakeTask("node2", "running"),
+		fakeTask("node1", "running"),
+		fakeTask("node3", "shutdown"),
+	}
+	nodes := []map[string]interface{}{
+		{"ID": "node1", "Status": map[string]interface{}{"Addr": "10.0.0.11"}},
+		{"ID": "node2", "Status": map[string]interface{}{"Addr": "0.0.0.0"}, "ManagerStatus": map[string]interface{}{"Addr": "10.0.0.12:2377"}},
+		{"ID": "node3", "Status": map[string]interface{}{"Addr": "10.0.0.13"}},
+	}
+	c := newFakeSwarm(t, tasks, nodes)
+
+	addresses, err := c.ServiceNodeAddresses(context.Background(), "traefik", "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if strings.Join(addresses, ",") != "10.0.0.11,10.0.0.12" {
+		t.Errorf("expected the addresses of the nodes running traefik, got %v", addresses)
+	}
+}
+
+func TestServiceNodeAddresses_NodeLabel(t *testing.T) {
+	tasks := []map[string]interface{}{fakeTask("node1", "running")}
+	nodes := []map[string]interface{}{
+		{
+			"ID":     "node1",
+			"Spec":   map[string]interface{}{"Labels": map[string]string{"ingress-ip": "192.168.1.11, fd00::11"}},
+			"Status": map[string]interface{}{"Addr": "10.0.0.11"},
+		},
+	}
+	c := newFakeSwarm(t, tasks, nodes)
+
+	addresses, err := c.ServiceNodeAddresses(context.Background(), "traefik", "ingress-ip")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if strings.Join(addresses, ",") != "192.168.1.11,fd00::11" {
+		t.Errorf("expected the addresses of the node label, got %v", addresses)
+	}
+
+	// Nodes without the label use their status address
+	addresses, err = c.ServiceNodeAddresses(context.Background(), "traefik", "other-label")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if strings.Join(addresses, ",") != "10.0.0.11" {
+		t.Errorf("expected the status address, got %v", addresses)
+	}
+}
+
+func TestServiceNodeAddresses_NoRunningTasks(t *testing.T) {
+	c := newFakeSwarm(t, []map[string]interface{}{fakeTask("node1", "pending")}, nil)
+
+	if _, err := c.ServiceNodeAddresses(context.Background(), "traefik", ""); err == nil || !strings.Contains(err.Error(), "no running tasks") {
+		t.Errorf("expected no running tasks error, got %v", err)
+	}
+}
+
+func TestHostAddresses(t *testing.T) {
+	c, err := NewClient(context.Background(), "tcp://10.1.2.3:2376", WithMode(ModeStandalone))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer c.Close()
+
+	addresses, err := c.HostAddresses(context.Background(), "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(addresses) != 1 || addresses[0] != "10.1.2.3" {
+		t.Errorf("expected the address of the remote host, got %v", addresses)
+	}
+
+	local, err := NewClient(context.Background(), "unix:///var/run/docker.sock", WithMode(ModeStandalone))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer local.Close()
+
+	if _, err := local.HostAddresses(context.Background(), ""); err == nil {
+		t.Error("expected error for a local daemon without an interface")
+	}
+}
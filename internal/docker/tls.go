@@ -0,0 +1,46 @@
+package docker
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// NewTLSConfig builds the TLS configuration of a Docker host from the ca.pem,
+// cert.pem and key.pem in certPath, the layout of DOCKER_CERT_PATH. The daemon
+// certificate is only verified against ca.pem when verify is set, as with
+// DOCKER_TLS_VERIFY.
+func NewTLSConfig(certPath string, verify bool) (*tls.Config, error) {
+	cfg := &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		InsecureSkipVerify: !verify,
+	}
+
+	caCert, err := os.ReadFile(filepath.Join(certPath, "ca.pem"))
+	if err != nil {
+		return nil, fmt.Errorf("reading CA certificate: %w", err)
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(caCert) {
+		return nil, errors.New("ca.pem contains no PEM certificates")
+	}
+	cfg.RootCAs = pool
+
+	cert, err := tls.LoadX509KeyPair(filepath.Join(certPath, "cert.pem"), filepath.Join(certPath, "key.pem"))
+	if err != nil {
+		return nil, fmt.Errorf("loading client certificate: %w", err)
+	}
+	cfg.Certificates = []tls.Certificate{cert}
+
+	return cfg, nil
+}
+
+// WithTLSConfig connects to the host over TLS with cfg.
+func WithTLSConfig(cfg *tls.Config) ClientOption {
+	return func(c *Client) {
+		c.tlsConfig = cfg
+	}
+}
@@ -0,0 +1,109 @@
+package docker
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// newTLSDaemon starts a fake standalone Docker daemon over TLS and returns it with a
+// DOCKER_CERT_PATH-style directory holding its certificate as ca.pem and cert.pem.
+func newTLSDaemon(t *testing.T) (*httptest.Server, string) {
+	t.Helper()
+
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("API-Version", "1.43")
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{"Swarm": map[string]interface{}{"LocalNodeState": "inactive"}})
+	}))
+	t.Cleanup(server.Close)
+
+	cert := server.TLS.Certificates[0]
+	key, err := x509.MarshalPKCS8PrivateKey(cert.PrivateKey)
+	if err != nil {
+		t.Fatalf("marshaling key: %v", err)
+	}
+
+	dir := t.TempDir()
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.Certificate[0]})
+	files := map[string][]byte{
+		"ca.pem":   certPEM,
+		"cert.pem": certPEM,
+		"key.pem":  pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: key}),
+	}
+	for name, data := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), data, 0o600); err != nil {
+			t.Fatalf("writing %s: %v", name, err)
+		}
+	}
+	return server, dir
+}
+
+func TestWithTLSConfig(t *testing.T) {
+	server, certPath := newTLSDaemon(t)
+	host := "tcp://" + strings.TrimPrefix(server.URL, "https://")
+
+	tlsConfig, err := NewTLSConfig(certPath, true)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	c, err := NewClient(context.Background(), host, WithTLSConfig(tlsConfig))
+	if err != nil {
+		t.Fatalf("expected connection trusting ca.pem to succeed, got %v", err)
+	}
+	defer c.Close()
+	if c.Mode() != ModeStandalone {
+		t.Errorf("expected standalone mode, got %s", c.Mode())
+	}
+
+	if _, err := NewClient(context.Background(), host); err == nil {
+		t.Error("expected connection without TLS to fail")
+	}
+}
+
+func TestNewClient_IgnoresTLSEnvironment(t *testing.T) {
+	// The global settings must not apply to a host configured without TLS
+	t.Setenv("DOCKER_CERT_PATH", filepath.Join(t.TempDir(), "missing"))
+	t.Setenv("DOCKER_TLS_VERIFY", "1")
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("API-Version", "1.43")
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{"Swarm": map[string]interface{}{"LocalNodeState": "inactive"}})
+	}))
+	defer server.Close()
+
+	c, err := NewClient(context.Background(), "tcp://"+strings.TrimPrefix(server.URL, "http://"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	c.Close()
+}
+
+func TestNewTLSConfig(t *testing.T) {
+	_, certPath := newTLSDaemon(t)
+
+	tlsConfig, err := NewTLSConfig(certPath, false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !tlsConfig.InsecureSkipVerify || len(tlsConfig.Certificates) != 1 || tlsConfig.RootCAs == nil {
+		t.Errorf("unexpected TLS configuration: %+v", tlsConfig)
+	}
+
+	if _, err := NewTLSConfig(t.TempDir(), true); err == nil {
+		t.Error("expected error for a directory without certificates")
+	}
+
+	os.Remove(filepath.Join(certPath, "key.pem"))
+	if _, err := NewTLSConfig(certPath, true); err == nil {
+		t.Error("expected error for a certificate without key")
+	}
+}
@@ -4,9 +4,11 @@ package health
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log/slog"
 	"net/http"
+	"strings"
 	"sync"
 	"time"
 
@@ -16,6 +18,19 @@ import (
 // Checker is a function that checks if a dependency is healthy.
 type Checker func(ctx context.Context) error
 
+// degradedError is returned by a checker whose component works with reduced capacity.
+type degradedError struct {
+	msg string
+}
+
+func (e *degradedError) Error() string { return e.msg }
+
+// Degraded returns an error that marks a component degraded rather than unhealthy.
+// It is reported on /health but does not fail /ready.
+func Degraded(msg string) error {
+	return &degradedError{msg: msg}
+}
+
 // Status represents the health status of a component.
 type Status string
 
@@ -51,6 +66,8 @@ type Server struct {
 	mu       sync.RWMutex
 	checkers map[string]Checker
 	ready    bool
+
+	secrets []string
 }
 
 // Option is a functional option for configuring the Server.
@@ -70,6 +87,18 @@ func WithVersion(version string) Option {
 	}
 }
 
+// WithRedactedSecrets hides the given secrets, such as API tokens, in component
+// messages, as a last line of defense against checkers that leak them.
+func WithRedactedSecrets(secrets ...string) Option {
+	return func(s *Server) {
+		for _, secret := range secrets {
+			if secret != "" {
+				s.secrets = append(s.secrets, secret)
+			}
+		}
+	}
+}
+
 // New creates a new health Server.
 func New(port int, opts ...Option) *Server {
 	s := &Server{
@@ -168,23 +197,11 @@ func (s *Server) handleHealth(w http.ResponseWriter, r *http.Request) {
 	defer cancel()
 
 	for name, checker := range checkers {
-		start := time.Now()
-		err := checker(ctx)
-		latency := time.Since(start)
-
-		if err != nil {
+		component := s.check(ctx, checker)
+		if component.Status != StatusHealthy {
 			resp.Status = StatusDegraded
-			resp.Components[name] = ComponentHealth{
-				Status:  StatusUnhealthy,
-				Message: err.Error(),
-				Latency: latency.String(),
-			}
-		} else {
-			resp.Components[name] = ComponentHealth{
-				Status:  StatusHealthy,
-				Latency: latency.String(),
-			}
 		}
+		resp.Components[name] = component
 	}
 
 	statusCode := http.StatusOK
@@ -227,23 +244,11 @@ func (s *Server) handleReady(w http.ResponseWriter, r *http.Request) {
 
 	allHealthy := true
 	for name, checker := range checkers {
-		start := time.Now()
-		err := checker(ctx)
-		latency := time.Since(start)
-
-		if err != nil {
+		component := s.check(ctx, checker)
+		if component.Status == StatusUnhealthy {
 			allHealthy = false
-			resp.Components[name] = ComponentHealth{
-				Status:  StatusUnhealthy,
-				Message: err.Error(),
-				Latency: latency.String(),
-			}
-		} else {
-			resp.Components[name] = ComponentHealth{
-				Status:  StatusHealthy,
-				Latency: latency.String(),
-			}
 		}
+		resp.Components[name] = component
 	}
 
 	if !allHealthy {
@@ -255,6 +260,36 @@ func (s *Server) handleReady(w http.ResponseWriter, r *http.Request) {
 	s.writeJSON(w, http.StatusOK, resp)
 }
 
+// check runs a checker and reports the component's health. A Degraded error marks
+// the component degraded; any other error marks it unhealthy.
+func (s *Server) check(ctx context.Context, checker Checker) ComponentHealth {
+	start := time.Now()
+	err := checker(ctx)
+	component := ComponentHealth{
+		Status:  StatusHealthy,
+		Latency: time.Since(start).String(),
+	}
+
+	var degraded *degradedError
+	switch {
+	case errors.As(err, &degraded):
+		component.Status = StatusDegraded
+		component.Message = s.redact(err.Error())
+	case err != nil:
+		component.Status = StatusUnhealthy
+		component.Message = s.redact(err.Error())
+	}
+	return component
+}
+
+// redact replaces every configured secret in msg.
+func (s *Server) redact(msg string) string {
+	for _, secret := range s.secrets {
+		msg = strings.ReplaceAll(msg, secret, "[REDACTED]")
+	}
+	return msg
+}
+
 // writeJSON writes a JSON response.
 func (s *Server) writeJSON(w http.ResponseWriter, statusCode int, data interface{}) {
 	w.Header().Set("Content-Type", "application/json")
@@ -0,0 +1,252 @@
+// Package labels parses technitium-companion labels set on Docker workloads.
+package labels
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/maxfield-allison/technitium-companion/internal/config"
+	"github.com/maxfield-allison/technitium-companion/internal/technitium"
+)
+
+// Prefix is the common prefix of all technitium-companion workload labels.
+const Prefix = "technitium-companion."
+
+// Workload label keys.
+const (
+	// Enable opts a workload out of DNS management when set to false.
+	Enable = Prefix + "enable"
+	// TargetIP overrides the addresses records point at (comma-separated for dual-stack).
+	TargetIP = Prefix + "target-ip"
+	// TTL overrides the record TTL in seconds.
+	TTL = Prefix + "ttl"
+	// Zone overrides the zone records are created in.
+	Zone = Prefix + "zone"
+	// PTR prefers the workload's hostnames as PTR targets when several hostnames share an address.
+	PTR = Prefix + "ptr"
+	// Network points the workload's records at its own address on the named Docker network.
+	Network = Prefix + "network"
+	// Hosts lists hostnames published for the workload whether or not Traefik serves it.
+	Hosts = Prefix + "hosts"
+	// Records starts the labels declaring additional records, one group of
+	// technitium-companion.records.ID.FIELD labels per record.
+	Records = Prefix + "records."
+)
+
+// Fields of a record declared with technitium-companion.records.ID.FIELD labels.
+const (
+	// RecordType is the record type: SRV, TXT or CNAME.
+	RecordType = "type"
+	// RecordName is the fully qualified name the record is created at.
+	RecordName = "name"
+	// RecordValue is the record data: the text of a TXT record, the target of a
+	// CNAME or "priority weight port target" for an SRV record.
+	RecordValue = "value"
+	// RecordTTL overrides the TTL in seconds.
+	RecordTTL = "ttl"
+)
+
+// Record is an additional record declared through labels, for records Traefik rules
+// cannot express such as SRV records or verification TXT records.
+type Record struct {
+	// ID is the label segment grouping the record's fields.
+	ID   string
+	Type string
+	// Name is the canonical fully qualified name of the record.
+	Name string
+	// Value is the record data in canonical form: CNAME targets are lowercased
+	// without trailing dot and SRV data is formatted by technitium.SRV.String.
+	Value string
+	// TTL is zero when the workload's or global TTL applies.
+	TTL int
+}
+
+// Overrides holds per-workload settings read from labels.
+// Zero values mean the global configuration applies.
+type Overrides struct {
+	// Disabled is true when the workload has opted out of DNS management.
+	Disabled  bool
+	TargetIPs []string
+	TTL       int
+	Zone      string
+	// PreferPTR is true when the workload's hostnames should win the PTR record of their address.
+	PreferPTR bool
+	// Network is the Docker network whose workload address the records point at.
+	Network string
+	// Hosts are hostnames published in addition to those of the workload's Traefik routers.
+	Hosts []string
+}
+
+// ParseOverrides reads the override labels from a workload's labels.
+// Invalid values are reported in the returned errors and left unset, so the
+// global configuration applies to that field instead.
+func ParseOverrides(labels map[string]string) (Overrides, []error) {
+	var o Overrides
+	var errs []error
+
+	if v, ok := lookup(labels, Enable); ok {
+		enabled, err := parseBool(v)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", Enable, err))
+		} else {
+			o.Disabled = !enabled
+		}
+	}
+
+	if v, ok := lookup(labels, TargetIP); ok {
+		ips, err := config.ParseIPList(v)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: not a valid IP address: %v", TargetIP, err))
+		} else {
+			o.TargetIPs = ips
+		}
+	}
+
+	if v, ok := lookup(labels, TTL); ok {
+		ttl, err := strconv.Atoi(v)
+		if err != nil || ttl < 1 {
+			errs = append(errs, fmt.Errorf("%s: must be a positive integer: %s", TTL, v))
+		} else {
+			o.TTL = ttl
+		}
+	}
+
+	if v, ok := lookup(labels, Zone); ok {
+		o.Zone = strings.TrimSuffix(strings.ToLower(v), ".")
+	}
+
+	if v, ok := lookup(labels, PTR); ok {
+		prefer, err := parseBool(v)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", PTR, err))
+		} else {
+			o.PreferPTR = prefer
+		}
+	}
+
+	if v, ok := lookup(labels, Network); ok {
+		o.Network = v
+	}
+
+	if v, ok := lookup(labels, Hosts); ok {
+		for _, host := range strings.Split(v, ",") {
+			if host = technitium.CanonicalName(host); host != "" && !slices.Contains(o.Hosts, host) {
+				o.Hosts = append(o.Hosts, host)
+			}
+		}
+	}
+
+	return o, errs
+}
+
+// ParseRecords reads the records declared with technitium-companion.records.ID.FIELD
+// labels, sorted by ID. A record with an unknown field or an invalid, missing or
+// unsupported type, name or value is reported in the returned errors and skipped.
+func ParseRecords(labels map[string]string) ([]Record, []error) {
+	fields := make(map[string]map[string]string)
+	var errs []error
+	for key, value := range labels {
+		rest, ok := strings.CutPrefix(key, Records)
+		if !ok {
+			continue
+		}
+		id, field, ok := strings.Cut(rest, ".")
+		if !ok || id == "" {
+			errs = append(errs, fmt.Errorf("%s: expected %sID.FIELD", key, Records))
+			continue
+		}
+		if fields[id] == nil {
+			fields[id] = make(map[string]string)
+		}
+		fields[id][field] = strings.TrimSpace(value)
+	}
+
+	ids := make([]string, 0, len(fields))
+	for id := range fields {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	var records []Record
+	for _, id := range ids {
+		rec, err := parseRecord(id, fields[id])
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s%s: %w", Records, id, err))
+			continue
+		}
+		records = append(records, rec)
+	}
+
+	return records, errs
+}
+
+// parseRecord validates the fields of one declared record.
+func parseRecord(id string, fields map[string]string) (Record, error) {
+	for field := range fields {
+		switch field {
+		case RecordType, RecordName, RecordValue, RecordTTL:
+		default:
+			return Record{}, fmt.Errorf("unknown field %q", field)
+		}
+	}
+
+	rec := Record{
+		ID:    id,
+		Type:  strings.ToUpper(fields[RecordType]),
+		Name:  technitium.CanonicalName(fields[RecordName]),
+		Value: fields[RecordValue],
+	}
+	if rec.Name == "" {
+		return Record{}, fmt.Errorf("missing %s", RecordName)
+	}
+	if rec.Value == "" {
+		return Record{}, fmt.Errorf("missing %s", RecordValue)
+	}
+
+	switch rec.Type {
+	case "TXT":
+	case "CNAME":
+		rec.Value = technitium.CanonicalName(rec.Value)
+	case "SRV":
+		srv, err := technitium.ParseSRV(rec.Value)
+		if err != nil {
+			return Record{}, err
+		}
+		rec.Value = srv.String()
+	case "":
+		return Record{}, fmt.Errorf("missing %s", RecordType)
+	default:
+		return Record{}, fmt.Errorf("unsupported type %s, expected SRV, TXT or CNAME", rec.Type)
+	}
+
+	if v, ok := fields[RecordTTL]; ok && v != "" {
+		ttl, err := strconv.Atoi(v)
+		if err != nil || ttl < 1 {
+			return Record{}, fmt.Errorf("%s must be a positive integer: %s", RecordTTL, v)
+		}
+		rec.TTL = ttl
+	}
+
+	return rec, nil
+}
+
+// lookup returns a trimmed, non-empty label value.
+func lookup(labels map[string]string, key string) (string, bool) {
+	v := strings.TrimSpace(labels[key])
+	return v, v != ""
+}
+
+// parseBool parses the boolean spellings accepted throughout the configuration.
+func parseBool(s string) (bool, error) {
+	switch strings.ToLower(s) {
+	case "true", "1", "yes", "on":
+		return true, nil
+	case "false", "0", "no", "off":
+		return false, nil
+	default:
+		return false, fmt.Errorf("not a valid boolean: %s", s)
+	}
+}
@@ -0,0 +1,149 @@
+package labels
+
+import (
+	"testing"
+)
+
+func TestParseOverrides_Empty(t *testing.T) {
+	o, errs := ParseOverrides(map[string]string{
+		"traefik.http.routers.app.rule": "Host(`app.example.com`)",
+	})
+
+	if len(errs) != 0 {
+		t.Errorf("unexpected errors: %v", errs)
+	}
+	if o.Disabled || o.TargetIPs != nil || o.TTL != 0 || o.Zone != "" {
+		t.Errorf("expected zero overrides, got %+v", o)
+	}
+}
+
+func TestParseOverrides_AllSet(t *testing.T) {
+	o, errs := ParseOverrides(map[string]string{
+		Enable:   "true",
+		TargetIP: " 10.0.0.50, 2001:DB8::50 ",
+		TTL:      "60",
+		Zone:     "Lab.Example.com.",
+		PTR:      "yes",
+		Network:  " lan ",
+		Hosts:    "NAS.home.example.com., nas.home.example.com, ,files.home.example.com",
+	})
+
+	if len(errs) != 0 {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+	if o.Disabled {
+		t.Error("expected workload to be enabled")
+	}
+	if len(o.TargetIPs) != 2 || o.TargetIPs[0] != "10.0.0.50" || o.TargetIPs[1] != "2001:db8::50" {
+		t.Errorf("expected target IPs [10.0.0.50 2001:db8::50], got %v", o.TargetIPs)
+	}
+	if o.TTL != 60 {
+		t.Errorf("expected TTL 60, got %d", o.TTL)
+	}
+	if o.Zone != "lab.example.com" {
+		t.Errorf("expected zone lab.example.com, got %s", o.Zone)
+	}
+	if !o.PreferPTR {
+		t.Error("expected PTR preference")
+	}
+	if o.Network != "lan" {
+		t.Errorf("expected network lan, got %q", o.Network)
+	}
+	if len(o.Hosts) != 2 || o.Hosts[0] != "nas.home.example.com" || o.Hosts[1] != "files.home.example.com" {
+		t.Errorf("expected hosts [nas.home.example.com files.home.example.com], got %v", o.Hosts)
+	}
+}
+
+func TestParseOverrides_Disabled(t *testing.T) {
+	for _, v := range []string{"false", "0", "no", "off", "FALSE"} {
+		t.Run(v, func(t *testing.T) {
+			o, errs := ParseOverrides(map[string]string{Enable: v})
+			if len(errs) != 0 {
+				t.Fatalf("unexpected errors: %v", errs)
+			}
+			if !o.Disabled {
+				t.Errorf("expected %s=%s to disable the workload", Enable, v)
+			}
+		})
+	}
+}
+
+func TestParseRecords(t *testing.T) {
+	records, errs := ParseRecords(map[string]string{
+		"traefik.enable":                       "true",
+		Records + "mc.type":                    "srv",
+		Records + "mc.name":                    "_minecraft._tcp.Example.com.",
+		Records + "mc.value":                   "0 5 25565 MC.example.com",
+		Records + "mc.ttl":                     "60",
+		Records + "verify.type":                "TXT",
+		Records + "verify.name":                "example.com",
+		Records + "verify.value":               "google-site-verification=abc",
+		Records + "www.type":                   "CNAME",
+		Records + "www.name":                   "www.example.com",
+		Records + "www.value":                  "App.example.com.",
+		"technitium-companion.ttl":             "300",
+		"technitium-companion.records.ignored": "",
+	})
+
+	// The malformed key without a field is reported
+	if len(errs) != 1 {
+		t.Errorf("expected 1 error, got %v", errs)
+	}
+
+	expected := []Record{
+		{ID: "mc", Type: "SRV", Name: "_minecraft._tcp.example.com", Value: "0 5 25565 mc.example.com", TTL: 60},
+		{ID: "verify", Type: "TXT", Name: "example.com", Value: "google-site-verification=abc"},
+		{ID: "www", Type: "CNAME", Name: "www.example.com", Value: "app.example.com"},
+	}
+	if len(records) != len(expected) {
+		t.Fatalf("expected %d records, got %+v", len(expected), records)
+	}
+	for i := range expected {
+		if records[i] != expected[i] {
+			t.Errorf("record %d = %+v, want %+v", i, records[i], expected[i])
+		}
+	}
+}
+
+func TestParseRecords_Invalid(t *testing.T) {
+	records, errs := ParseRecords(map[string]string{
+		Records + "mx.type":      "MX",
+		Records + "mx.name":      "example.com",
+		Records + "mx.value":     "10 mail.example.com",
+		Records + "srv.type":     "SRV",
+		Records + "srv.name":     "_sip._udp.example.com",
+		Records + "srv.value":    "10 5 sip.example.com",
+		Records + "noname.type":  "TXT",
+		Records + "noname.value": "text",
+		Records + "ttl.type":     "TXT",
+		Records + "ttl.name":     "example.com",
+		Records + "ttl.value":    "text",
+		Records + "ttl.ttl":      "-5",
+		Records + "typo.type":    "TXT",
+		Records + "typo.name":    "example.com",
+		Records + "typo.vaule":   "text",
+	})
+
+	if len(records) != 0 {
+		t.Errorf("expected no valid records, got %+v", records)
+	}
+	if len(errs) != 5 {
+		t.Errorf("expected 5 errors, got %d: %v", len(errs), errs)
+	}
+}
+
+func TestParseOverrides_InvalidValues(t *testing.T) {
+	o, errs := ParseOverrides(map[string]string{
+		Enable:   "maybe",
+		TargetIP: "not-an-ip",
+		TTL:      "0",
+		PTR:      "sometimes",
+	})
+
+	if len(errs) != 4 {
+		t.Errorf("expected 4 errors, got %d: %v", len(errs), errs)
+	}
+	if o.Disabled || o.TargetIPs != nil || o.TTL != 0 || o.PreferPTR {
+		t.Errorf("expected invalid values to be ignored, got %+v", o)
+	}
+}
@@ -16,9 +16,9 @@ var (
 		prometheus.CounterOpts{
 			Namespace: namespace,
 			Name:      "dns_records_created_total",
-			Help:      "Total number of DNS A records created",
+			Help:      "Total number of DNS records created",
 		},
-		[]string{"zone"},
+		[]string{"server", "zone", "type"},
 	)
 
 	// DNSRecordsDeletedTotal counts the total number of DNS records deleted.
@@ -26,9 +26,19 @@ var (
 		prometheus.CounterOpts{
 			Namespace: namespace,
 			Name:      "dns_records_deleted_total",
-			Help:      "Total number of DNS A records deleted",
+			Help:      "Total number of DNS records deleted",
 		},
-		[]string{"zone"},
+		[]string{"server", "zone", "type"},
+	)
+
+	// DNSRecordsUpdatedTotal counts existing DNS records changed to match the desired state.
+	DNSRecordsUpdatedTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "dns_records_updated_total",
+			Help:      "Total number of DNS records updated",
+		},
+		[]string{"server", "zone", "type"},
 	)
 
 	// DNSRecordsExistedTotal counts records that already existed (no action needed).
@@ -36,9 +46,9 @@ var (
 		prometheus.CounterOpts{
 			Namespace: namespace,
 			Name:      "dns_records_existed_total",
-			Help:      "Total number of DNS A records that already existed",
+			Help:      "Total number of DNS records that already existed",
 		},
-		[]string{"zone"},
+		[]string{"server", "zone", "type"},
 	)
 
 	// APIRequestsTotal counts API requests by endpoint and status.
@@ -48,7 +58,7 @@ var (
 			Name:      "api_requests_total",
 			Help:      "Total number of Technitium API requests",
 		},
-		[]string{"endpoint", "status"},
+		[]string{"server", "endpoint", "status"},
 	)
 
 	// APIRequestDuration tracks API request latency.
@@ -59,17 +69,89 @@ var (
 			Help:      "Duration of Technitium API requests in seconds",
 			Buckets:   prometheus.DefBuckets,
 		},
-		[]string{"endpoint"},
+		[]string{"server", "endpoint"},
+	)
+
+	// APIRetriesTotal counts Technitium API requests retried after a transient failure.
+	APIRetriesTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "api_retries_total",
+			Help:      "Total number of Technitium API request retries",
+		},
+		[]string{"server", "endpoint"},
+	)
+
+	// APIWaitDuration tracks how long requests waited for the rate limiter and a free
+	// in-flight slot before being sent.
+	APIWaitDuration = promauto.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "api_wait_duration_seconds",
+			Help:      "Time Technitium API requests waited for the rate limit and concurrency limit in seconds",
+			Buckets:   []float64{0.001, 0.01, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30},
+		},
+		[]string{"server"},
 	)
 
-	// DockerEventsTotal counts Docker events by type.
+	// APIRequestsQueued tracks requests currently waiting for the rate limiter or a free in-flight slot.
+	APIRequestsQueued = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "api_requests_queued",
+			Help:      "Number of Technitium API requests waiting to be sent",
+		},
+		[]string{"server"},
+	)
+
+	// APIRequestsInFlight tracks requests currently sent and awaiting a response.
+	APIRequestsInFlight = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "api_requests_in_flight",
+			Help:      "Number of Technitium API requests awaiting a response",
+		},
+		[]string{"server"},
+	)
+
+	// APICircuitBreakerState is 1 for the current state of the Technitium API circuit breaker.
+	APICircuitBreakerState = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "api_circuit_breaker_state",
+			Help:      "Current state of the Technitium API circuit breaker (1 = current state)",
+		},
+		[]string{"server", "state"},
+	)
+
+	// DockerEventsTotal counts Docker events by host and type.
 	DockerEventsTotal = promauto.NewCounterVec(
 		prometheus.CounterOpts{
 			Namespace: namespace,
 			Name:      "docker_events_total",
 			Help:      "Total number of Docker events processed",
 		},
-		[]string{"type", "action"},
+		[]string{"host", "type", "action"},
+	)
+
+	// DockerHostUp tracks whether each Docker host could be listed in the last reconciliation.
+	DockerHostUp = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "docker_host_up",
+			Help:      "Whether the Docker host could be listed in the last reconciliation (1 = up, 0 = down)",
+		},
+		[]string{"host"},
+	)
+
+	// DockerHostWorkloads tracks the number of workloads of each Docker host in the last reconciliation.
+	DockerHostWorkloads = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "docker_host_workloads",
+			Help:      "Number of workloads of the Docker host in the last reconciliation",
+		},
+		[]string{"host"},
 	)
 
 	// ReconciliationsTotal counts reconciliation runs by result.
@@ -149,30 +231,80 @@ func SetUp() {
 	Up.Set(1)
 }
 
-// RecordAPIRequest records metrics for an API request.
-func RecordAPIRequest(endpoint, status string, durationSeconds float64) {
-	APIRequestsTotal.WithLabelValues(endpoint, status).Inc()
-	APIRequestDuration.WithLabelValues(endpoint).Observe(durationSeconds)
+// RecordAPIRequest records metrics for an API request to a Technitium server.
+func RecordAPIRequest(server, endpoint, status string, durationSeconds float64) {
+	APIRequestsTotal.WithLabelValues(server, endpoint, status).Inc()
+	APIRequestDuration.WithLabelValues(server, endpoint).Observe(durationSeconds)
+}
+
+// RecordAPIRetry increments the retry counter for a server and endpoint.
+func RecordAPIRetry(server, endpoint string) {
+	APIRetriesTotal.WithLabelValues(server, endpoint).Inc()
+}
+
+// RecordAPIWait records how long a request to a server waited before being sent.
+func RecordAPIWait(server string, waitSeconds float64) {
+	APIWaitDuration.WithLabelValues(server).Observe(waitSeconds)
+}
+
+// AddAPIRequestsQueued adjusts the number of requests waiting for a server by delta.
+func AddAPIRequestsQueued(server string, delta float64) {
+	APIRequestsQueued.WithLabelValues(server).Add(delta)
+}
+
+// AddAPIRequestsInFlight adjusts the number of requests in flight to a server by delta.
+func AddAPIRequestsInFlight(server string, delta float64) {
+	APIRequestsInFlight.WithLabelValues(server).Add(delta)
+}
+
+// circuitBreakerStates are the states reported by APICircuitBreakerState.
+var circuitBreakerStates = []string{"closed", "half-open", "open"}
+
+// SetCircuitBreakerState marks state as the current circuit breaker state of a server.
+func SetCircuitBreakerState(server, state string) {
+	for _, s := range circuitBreakerStates {
+		value := 0.0
+		if s == state {
+			value = 1
+		}
+		APICircuitBreakerState.WithLabelValues(server, s).Set(value)
+	}
+}
+
+// RecordDNSRecordCreated increments the created counter for a server, zone and record type.
+func RecordDNSRecordCreated(server, zone, recordType string) {
+	DNSRecordsCreatedTotal.WithLabelValues(server, zone, recordType).Inc()
 }
 
-// RecordDNSRecordCreated increments the created counter for a zone.
-func RecordDNSRecordCreated(zone string) {
-	DNSRecordsCreatedTotal.WithLabelValues(zone).Inc()
+// RecordDNSRecordDeleted increments the deleted counter for a server, zone and record type.
+func RecordDNSRecordDeleted(server, zone, recordType string) {
+	DNSRecordsDeletedTotal.WithLabelValues(server, zone, recordType).Inc()
 }
 
-// RecordDNSRecordDeleted increments the deleted counter for a zone.
-func RecordDNSRecordDeleted(zone string) {
-	DNSRecordsDeletedTotal.WithLabelValues(zone).Inc()
+// RecordDNSRecordUpdated increments the updated counter for a server, zone and record type.
+func RecordDNSRecordUpdated(server, zone, recordType string) {
+	DNSRecordsUpdatedTotal.WithLabelValues(server, zone, recordType).Inc()
 }
 
-// RecordDNSRecordExisted increments the existed counter for a zone.
-func RecordDNSRecordExisted(zone string) {
-	DNSRecordsExistedTotal.WithLabelValues(zone).Inc()
+// RecordDNSRecordExisted increments the existed counter for a server, zone and record type.
+func RecordDNSRecordExisted(server, zone, recordType string) {
+	DNSRecordsExistedTotal.WithLabelValues(server, zone, recordType).Inc()
 }
 
 // RecordDockerEvent increments the Docker events counter.
-func RecordDockerEvent(eventType, action string) {
-	DockerEventsTotal.WithLabelValues(eventType, action).Inc()
+func RecordDockerEvent(host, eventType, action string) {
+	DockerEventsTotal.WithLabelValues(host, eventType, action).Inc()
+}
+
+// RecordDockerHost records whether a Docker host could be listed and how many
+// workloads it contributed, which are its last known ones when it is down.
+func RecordDockerHost(host string, up bool, workloads int) {
+	value := 0.0
+	if up {
+		value = 1
+	}
+	DockerHostUp.WithLabelValues(host).Set(value)
+	DockerHostWorkloads.WithLabelValues(host).Set(float64(workloads))
 }
 
 // RecordReconciliation records metrics for a reconciliation run.
@@ -39,17 +39,17 @@ func TestRecordAPIRequest(t *testing.T) {
 	APIRequestsTotal.Reset()
 	APIRequestDuration.Reset()
 
-	RecordAPIRequest("/api/zones/records/add", "success", 0.5)
-	RecordAPIRequest("/api/zones/records/add", "error", 0.1)
-	RecordAPIRequest("/api/zones/records/get", "success", 0.2)
+	RecordAPIRequest("dns1", "/api/zones/records/add", "success", 0.5)
+	RecordAPIRequest("dns1", "/api/zones/records/add", "error", 0.1)
+	RecordAPIRequest("dns1", "/api/zones/records/get", "success", 0.2)
 
 	// Check total requests
 	expected := `
 		# HELP technitium_companion_api_requests_total Total number of Technitium API requests
 		# TYPE technitium_companion_api_requests_total counter
-		technitium_companion_api_requests_total{endpoint="/api/zones/records/add",status="error"} 1
-		technitium_companion_api_requests_total{endpoint="/api/zones/records/add",status="success"} 1
-		technitium_companion_api_requests_total{endpoint="/api/zones/records/get",status="success"} 1
+		technitium_companion_api_requests_total{endpoint="/api/zones/records/add",server="dns1",status="error"} 1
+		technitium_companion_api_requests_total{endpoint="/api/zones/records/add",server="dns1",status="success"} 1
+		technitium_companion_api_requests_total{endpoint="/api/zones/records/get",server="dns1",status="success"} 1
 	`
 	if err := testutil.CollectAndCompare(APIRequestsTotal, strings.NewReader(expected)); err != nil {
 		t.Errorf("unexpected metric: %v", err)
@@ -62,20 +62,88 @@ func TestRecordAPIRequest(t *testing.T) {
 	}
 }
 
+func TestRecordAPIRetry(t *testing.T) {
+	APIRetriesTotal.Reset()
+
+	RecordAPIRetry("dns1", "/api/zones/records/get")
+	RecordAPIRetry("dns1", "/api/zones/records/get")
+
+	expected := `
+		# HELP technitium_companion_api_retries_total Total number of Technitium API request retries
+		# TYPE technitium_companion_api_retries_total counter
+		technitium_companion_api_retries_total{endpoint="/api/zones/records/get",server="dns1"} 2
+	`
+	if err := testutil.CollectAndCompare(APIRetriesTotal, strings.NewReader(expected)); err != nil {
+		t.Errorf("unexpected metric: %v", err)
+	}
+}
+
+func TestRecordAPIWait(t *testing.T) {
+	APIWaitDuration.Reset()
+
+	RecordAPIWait("dns1", 0.02)
+	RecordAPIWait("dns2", 1.5)
+
+	if count := testutil.CollectAndCount(APIWaitDuration); count != 2 {
+		t.Errorf("expected 2 histogram metrics, got %d", count)
+	}
+}
+
+func TestAddAPIRequestsQueuedAndInFlight(t *testing.T) {
+	APIRequestsQueued.Reset()
+	APIRequestsInFlight.Reset()
+
+	AddAPIRequestsQueued("dns1", 1)
+	AddAPIRequestsQueued("dns1", 1)
+	AddAPIRequestsQueued("dns1", -1)
+	AddAPIRequestsInFlight("dns1", 1)
+
+	if queued := testutil.ToFloat64(APIRequestsQueued.WithLabelValues("dns1")); queued != 1 {
+		t.Errorf("expected 1 queued request, got %f", queued)
+	}
+	if inFlight := testutil.ToFloat64(APIRequestsInFlight.WithLabelValues("dns1")); inFlight != 1 {
+		t.Errorf("expected 1 request in flight, got %f", inFlight)
+	}
+}
+
+func TestSetCircuitBreakerState(t *testing.T) {
+	APICircuitBreakerState.Reset()
+
+	SetCircuitBreakerState("dns1", "closed")
+	SetCircuitBreakerState("dns1", "open")
+
+	expected := `
+		# HELP technitium_companion_api_circuit_breaker_state Current state of the Technitium API circuit breaker (1 = current state)
+		# TYPE technitium_companion_api_circuit_breaker_state gauge
+		technitium_companion_api_circuit_breaker_state{server="dns1",state="closed"} 0
+		technitium_companion_api_circuit_breaker_state{server="dns1",state="half-open"} 0
+		technitium_companion_api_circuit_breaker_state{server="dns1",state="open"} 1
+	`
+	if err := testutil.CollectAndCompare(APICircuitBreakerState, strings.NewReader(expected)); err != nil {
+		t.Errorf("unexpected metric: %v", err)
+	}
+}
+
 func TestRecordDNSRecordCreated(t *testing.T) {
 	DNSRecordsCreatedTotal.Reset()
 
-	RecordDNSRecordCreated("local.example.com")
-	RecordDNSRecordCreated("local.example.com")
-	RecordDNSRecordCreated("other.example.com")
+	RecordDNSRecordCreated("dns1", "local.example.com", "A")
+	RecordDNSRecordCreated("dns1", "local.example.com", "A")
+	RecordDNSRecordCreated("dns1", "local.example.com", "AAAA")
+	RecordDNSRecordCreated("dns1", "other.example.com", "A")
 
 	// Verify counts
-	localCount := testutil.ToFloat64(DNSRecordsCreatedTotal.WithLabelValues("local.example.com"))
+	localCount := testutil.ToFloat64(DNSRecordsCreatedTotal.WithLabelValues("dns1", "local.example.com", "A"))
 	if localCount != 2 {
-		t.Errorf("expected 2 records created for local.example.com, got %f", localCount)
+		t.Errorf("expected 2 A records created for local.example.com, got %f", localCount)
 	}
 
-	otherCount := testutil.ToFloat64(DNSRecordsCreatedTotal.WithLabelValues("other.example.com"))
+	localAAAACount := testutil.ToFloat64(DNSRecordsCreatedTotal.WithLabelValues("dns1", "local.example.com", "AAAA"))
+	if localAAAACount != 1 {
+		t.Errorf("expected 1 AAAA record created for local.example.com, got %f", localAAAACount)
+	}
+
+	otherCount := testutil.ToFloat64(DNSRecordsCreatedTotal.WithLabelValues("dns1", "other.example.com", "A"))
 	if otherCount != 1 {
 		t.Errorf("expected 1 record created for other.example.com, got %f", otherCount)
 	}
@@ -84,21 +152,32 @@ func TestRecordDNSRecordCreated(t *testing.T) {
 func TestRecordDNSRecordDeleted(t *testing.T) {
 	DNSRecordsDeletedTotal.Reset()
 
-	RecordDNSRecordDeleted("local.example.com")
+	RecordDNSRecordDeleted("dns1", "local.example.com", "AAAA")
 
-	count := testutil.ToFloat64(DNSRecordsDeletedTotal.WithLabelValues("local.example.com"))
+	count := testutil.ToFloat64(DNSRecordsDeletedTotal.WithLabelValues("dns1", "local.example.com", "AAAA"))
 	if count != 1 {
 		t.Errorf("expected 1 record deleted, got %f", count)
 	}
 }
 
+func TestRecordDNSRecordUpdated(t *testing.T) {
+	DNSRecordsUpdatedTotal.Reset()
+
+	RecordDNSRecordUpdated("dns1", "local.example.com", "A")
+
+	count := testutil.ToFloat64(DNSRecordsUpdatedTotal.WithLabelValues("dns1", "local.example.com", "A"))
+	if count != 1 {
+		t.Errorf("expected 1 record updated, got %f", count)
+	}
+}
+
 func TestRecordDNSRecordExisted(t *testing.T) {
 	DNSRecordsExistedTotal.Reset()
 
-	RecordDNSRecordExisted("local.example.com")
-	RecordDNSRecordExisted("local.example.com")
+	RecordDNSRecordExisted("dns1", "local.example.com", "A")
+	RecordDNSRecordExisted("dns1", "local.example.com", "A")
 
-	count := testutil.ToFloat64(DNSRecordsExistedTotal.WithLabelValues("local.example.com"))
+	count := testutil.ToFloat64(DNSRecordsExistedTotal.WithLabelValues("dns1", "local.example.com", "A"))
 	if count != 2 {
 		t.Errorf("expected 2 records existed, got %f", count)
 	}
@@ -107,20 +186,44 @@ func TestRecordDNSRecordExisted(t *testing.T) {
 func TestRecordDockerEvent(t *testing.T) {
 	DockerEventsTotal.Reset()
 
-	RecordDockerEvent("service", "create")
-	RecordDockerEvent("service", "update")
-	RecordDockerEvent("container", "start")
+	RecordDockerEvent("default", "service", "create")
+	RecordDockerEvent("default", "service", "update")
+	RecordDockerEvent("default", "container", "start")
+	RecordDockerEvent("edge1", "container", "start")
 
 	// Verify
-	serviceCreate := testutil.ToFloat64(DockerEventsTotal.WithLabelValues("service", "create"))
+	serviceCreate := testutil.ToFloat64(DockerEventsTotal.WithLabelValues("default", "service", "create"))
 	if serviceCreate != 1 {
 		t.Errorf("expected 1 service create event, got %f", serviceCreate)
 	}
 
-	containerStart := testutil.ToFloat64(DockerEventsTotal.WithLabelValues("container", "start"))
+	containerStart := testutil.ToFloat64(DockerEventsTotal.WithLabelValues("default", "container", "start"))
 	if containerStart != 1 {
 		t.Errorf("expected 1 container start event, got %f", containerStart)
 	}
+
+	edgeStart := testutil.ToFloat64(DockerEventsTotal.WithLabelValues("edge1", "container", "start"))
+	if edgeStart != 1 {
+		t.Errorf("expected 1 container start event on edge1, got %f", edgeStart)
+	}
+}
+
+func TestRecordDockerHost(t *testing.T) {
+	DockerHostUp.Reset()
+	DockerHostWorkloads.Reset()
+
+	RecordDockerHost("edge1", true, 12)
+	RecordDockerHost("edge2", false, 3)
+
+	if up := testutil.ToFloat64(DockerHostUp.WithLabelValues("edge1")); up != 1 {
+		t.Errorf("expected edge1 up, got %f", up)
+	}
+	if up := testutil.ToFloat64(DockerHostUp.WithLabelValues("edge2")); up != 0 {
+		t.Errorf("expected edge2 down, got %f", up)
+	}
+	if workloads := testutil.ToFloat64(DockerHostWorkloads.WithLabelValues("edge2")); workloads != 3 {
+		t.Errorf("expected 3 workloads on edge2, got %f", workloads)
+	}
 }
 
 func TestRecordReconciliation(t *testing.T) {
@@ -0,0 +1,105 @@
+package reconciler
+
+import (
+	"strings"
+
+	"github.com/maxfield-allison/technitium-companion/internal/technitium"
+)
+
+// ownershipPrefix is prepended to a hostname to form the name of its ownership TXT record.
+// Keeping the marker on a separate name avoids conflicting with the managed record itself.
+const ownershipPrefix = "_technitium-companion."
+
+// ownershipHeritage identifies TXT records written by technitium-companion.
+const ownershipHeritage = "heritage=technitium-companion"
+
+// ownershipWildcard stands in for the * label of a wildcard hostname in its ownership
+// record name, since a * anywhere but the leftmost label is not a wildcard.
+const ownershipWildcard = "_wildcard"
+
+// ownershipName returns the name of the ownership TXT record for a hostname.
+func ownershipName(hostname string) string {
+	if rest, ok := strings.CutPrefix(hostname, "*."); ok {
+		hostname = ownershipWildcard + "." + rest
+	}
+	return ownershipPrefix + hostname
+}
+
+// ownedHostname returns the hostname an ownership TXT record name refers to.
+// Returns false if the name is not an ownership record name.
+func ownedHostname(name string) (string, bool) {
+	if !strings.HasPrefix(name, ownershipPrefix) {
+		return "", false
+	}
+	hostname := strings.TrimPrefix(name, ownershipPrefix)
+	if hostname == "" {
+		return "", false
+	}
+	if rest, ok := strings.CutPrefix(hostname, ownershipWildcard+"."); ok {
+		hostname = "*." + rest
+	}
+	return hostname, true
+}
+
+// ownershipValue returns the TXT record value marking a hostname as owned by this instance.
+func ownershipValue(ownerID string) string {
+	return ownershipHeritage + ",owner=" + ownerID
+}
+
+// isOwnershipRecord checks if a record is an ownership TXT record written by this instance.
+func isOwnershipRecord(rec technitium.Record, ownerID string) bool {
+	if rec.Type != "TXT" {
+		return false
+	}
+	return rec.RData.Text == ownershipValue(ownerID)
+}
+
+// ownedHostnames returns the set of hostnames this instance owns, based on the
+// ownership TXT records present in a zone listing.
+func ownedHostnames(records []technitium.Record, ownerID string) map[string]struct{} {
+	owned := make(map[string]struct{})
+	for _, rec := range records {
+		if !isOwnershipRecord(rec, ownerID) {
+			continue
+		}
+		if hostname, ok := ownedHostname(rec.Name); ok {
+			owned[hostname] = struct{}{}
+		}
+	}
+	return owned
+}
+
+// provenanceRecords returns the provenance TXT records written by this instance
+// among the records at an ownership record name.
+func provenanceRecords(records []technitium.Record, ownerID string) []technitium.Record {
+	var out []technitium.Record
+	for _, rec := range records {
+		if rec.Type != "TXT" {
+			continue
+		}
+		if prov, ok := technitium.ParseProvenance(rec.RData.Text); ok && prov.Owner == ownerID {
+			out = append(out, rec)
+		}
+	}
+	return out
+}
+
+// ownedRecords returns the records of a zone listing whose comments carry provenance
+// written by this instance.
+func ownedRecords(zone string, records []technitium.Record, ownerID string) []OwnedRecord {
+	var owned []OwnedRecord
+	for _, rec := range records {
+		prov, ok := rec.Provenance()
+		if !ok || prov.Owner != ownerID {
+			continue
+		}
+		owned = append(owned, OwnedRecord{
+			Zone:       zone,
+			Name:       rec.Name,
+			Type:       rec.Type,
+			Value:      rec.Value(),
+			Provenance: prov,
+		})
+	}
+	return owned
+}
@@ -0,0 +1,341 @@
+package reconciler
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/maxfield-allison/technitium-companion/internal/config"
+	"github.com/maxfield-allison/technitium-companion/internal/docker"
+	"github.com/maxfield-allison/technitium-companion/internal/technitium"
+	"github.com/maxfield-allison/technitium-companion/internal/traefik"
+)
+
+// TestOwnershipName verifies ownership record names round-trip to their hostname.
+func TestOwnershipName(t *testing.T) {
+	name := ownershipName("app.example.com")
+	if name != "_technitium-companion.app.example.com" {
+		t.Errorf("unexpected ownership name: %s", name)
+	}
+
+	hostname, ok := ownedHostname(name)
+	if !ok || hostname != "app.example.com" {
+		t.Errorf("ownedHostname(%q) = %q, %v", name, hostname, ok)
+	}
+
+	name = ownershipName("*.apps.example.com")
+	if name != "_technitium-companion._wildcard.apps.example.com" {
+		t.Errorf("unexpected wildcard ownership name: %s", name)
+	}
+	hostname, ok = ownedHostname(name)
+	if !ok || hostname != "*.apps.example.com" {
+		t.Errorf("ownedHostname(%q) = %q, %v", name, hostname, ok)
+	}
+
+	if _, ok := ownedHostname("app.example.com"); ok {
+		t.Error("expected plain hostname not to be an ownership name")
+	}
+	if _, ok := ownedHostname(ownershipPrefix); ok {
+		t.Error("expected bare prefix not to be an ownership name")
+	}
+}
+
+// TestOwnedHostnames verifies only this instance's ownership records are recognized.
+func TestOwnedHostnames(t *testing.T) {
+	records := []technitium.Record{
+		{Name: ownershipName("mine.example.com"), Type: "TXT", RData: technitium.RData{Text: ownershipValue("default")}},
+		{Name: ownershipName("other.example.com"), Type: "TXT", RData: technitium.RData{Text: ownershipValue("other")}},
+		{Name: ownershipName("manual.example.com"), Type: "TXT", RData: technitium.RData{Text: "v=spf1 -all"}},
+		{Name: "mine.example.com", Type: "A", RData: technitium.RData{IPAddress: "10.0.0.1"}},
+	}
+
+	owned := ownedHostnames(records, "default")
+
+	if len(owned) != 1 {
+		t.Fatalf("expected 1 owned hostname, got %d: %v", len(owned), owned)
+	}
+	if _, ok := owned["mine.example.com"]; !ok {
+		t.Errorf("expected mine.example.com to be owned, got %v", owned)
+	}
+}
+
+// TestReconcileWorkloads_CleansUpOrphans verifies owned records without a workload are
+// removed while unowned and still-referenced records are left alone.
+func TestReconcileWorkloads_CleansUpOrphans(t *testing.T) {
+	fake, client := newFakeTechnitium(t,
+		fakeRecord{Name: "kept.example.com", Type: "A", TTL: 300, Value: "10.0.0.1"},
+		fakeRecord{Name: ownershipName("kept.example.com"), Type: "TXT", TTL: 300, Value: ownershipValue("default")},
+		fakeRecord{Name: "orphan.example.com", Type: "A", TTL: 300, Value: "10.0.0.1"},
+		fakeRecord{Name: ownershipName("orphan.example.com"), Type: "TXT", TTL: 300, Value: ownershipValue("default")},
+		fakeRecord{Name: "manual.example.com", Type: "A", TTL: 300, Value: "10.0.0.1"},
+	)
+
+	cfg := &config.Config{
+		TechnitiumZone: "example.com",
+		TargetIP:       "10.0.0.1",
+		TTL:            300,
+		OrphanCleanup:  true,
+		OwnerID:        "default",
+	}
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	rec := New(cfg, nil, traefik.NewParser(), []*technitium.Client{client}, WithLogger(logger))
+
+	workloads := []docker.Workload{
+		{Name: "kept", Labels: map[string]string{"traefik.http.routers.kept.rule": "Host(`kept.example.com`)"}},
+	}
+	result := &ReconcileResult{}
+
+	rec.reconcileWorkloads(context.Background(), workloads, result)
+
+	if len(result.Errors) != 0 {
+		t.Fatalf("unexpected errors: %v", result.Errors)
+	}
+	if result.RecordsDeleted != 1 {
+		t.Errorf("expected 1 record deleted, got %d", result.RecordsDeleted)
+	}
+	if fake.has("orphan.example.com", "A", "10.0.0.1") {
+		t.Error("expected orphaned A record to be deleted")
+	}
+	if fake.has(ownershipName("orphan.example.com"), "TXT", ownershipValue("default")) {
+		t.Error("expected orphaned ownership record to be deleted")
+	}
+	if !fake.has("kept.example.com", "A", "10.0.0.1") {
+		t.Error("expected referenced A record to be kept")
+	}
+	if !fake.has("manual.example.com", "A", "10.0.0.1") {
+		t.Error("expected unowned A record to be kept")
+	}
+}
+
+// TestReconcileWorkloads_ClaimsCreatedRecords verifies new records get an ownership record
+// while records that already existed are left unclaimed.
+func TestReconcileWorkloads_ClaimsCreatedRecords(t *testing.T) {
+	fake, client := newFakeTechnitium(t,
+		fakeRecord{Name: "existing.example.com", Type: "A", TTL: 300, Value: "10.0.0.1"},
+	)
+
+	cfg := &config.Config{
+		TechnitiumZone: "example.com",
+		TargetIP:       "10.0.0.1",
+		TTL:            300,
+		OrphanCleanup:  true,
+		OwnerID:        "default",
+	}
+	rec := New(cfg, nil, traefik.NewParser(), []*technitium.Client{client})
+
+	workloads := []docker.Workload{
+		{Name: "app", Labels: map[string]string{
+			"traefik.http.routers.new.rule":      "Host(`new.example.com`)",
+			"traefik.http.routers.existing.rule": "Host(`existing.example.com`)",
+		}},
+	}
+	result := &ReconcileResult{}
+
+	rec.reconcileWorkloads(context.Background(), workloads, result)
+
+	if result.RecordsCreated != 1 {
+		t.Errorf("expected 1 record created, got %d", result.RecordsCreated)
+	}
+	if !fake.has(ownershipName("new.example.com"), "TXT", ownershipValue("default")) {
+		t.Error("expected created record to be claimed")
+	}
+	if fake.has(ownershipName("existing.example.com"), "TXT", ownershipValue("default")) {
+		t.Error("expected pre-existing record not to be claimed")
+	}
+}
+
+// TestReconcileWorkloads_OrphanDryRun verifies dry run reports orphans without deleting them.
+func TestReconcileWorkloads_OrphanDryRun(t *testing.T) {
+	fake, client := newFakeTechnitium(t,
+		fakeRecord{Name: "orphan.example.com", Type: "A", TTL: 300, Value: "10.0.0.1"},
+		fakeRecord{Name: ownershipName("orphan.example.com"), Type: "TXT", TTL: 300, Value: ownershipValue("default")},
+	)
+
+	cfg := &config.Config{
+		TechnitiumZone: "example.com",
+		TargetIP:       "10.0.0.1",
+		OrphanCleanup:  true,
+		OwnerID:        "default",
+		DryRun:         true,
+	}
+	rec := New(cfg, nil, traefik.NewParser(), []*technitium.Client{client})
+
+	result := &ReconcileResult{}
+	rec.reconcileWorkloads(context.Background(), nil, result)
+
+	if result.RecordsDeleted != 1 {
+		t.Errorf("expected 1 record reported as deleted, got %d", result.RecordsDeleted)
+	}
+	if !fake.has("orphan.example.com", "A", "10.0.0.1") {
+		t.Error("dry run must not delete records")
+	}
+}
+
+// TestReconcileWorkloads_Provenance verifies created records carry their workload's
+// provenance in comments, updates keep the original creation time, and provenance
+// read back from the zone is reported as owned records.
+func TestReconcileWorkloads_Provenance(t *testing.T) {
+	created := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	oldProvenance := technitium.Provenance{Owner: "test", Workload: "web", Created: created}
+
+	fake, client := newFakeTechnitium(t,
+		fakeRecord{Name: "web.example.com", Type: "A", TTL: 60, Value: "10.0.0.1", Comments: oldProvenance.String()},
+		fakeRecord{Name: "other.example.com", Type: "A", TTL: 300, Value: "10.0.0.9", Comments: "added by hand"},
+	)
+
+	cfg := &config.Config{
+		TechnitiumZone: "example.com",
+		TargetIP:       "10.0.0.1",
+		TTL:            300,
+		OwnerID:        "test",
+	}
+	rec := New(cfg, nil, traefik.NewParser(), []*technitium.Client{client})
+
+	workloads := []docker.Workload{
+		{
+			Name: "media_app",
+			Type: "service",
+			Labels: map[string]string{
+				"traefik.http.routers.app.rule": "Host(`app.example.com`)",
+				"com.docker.stack.namespace":    "media",
+			},
+		},
+		{Name: "web", Type: "container", Labels: map[string]string{"traefik.http.routers.web.rule": "Host(`web.example.com`)"}},
+	}
+	result := &ReconcileResult{}
+
+	rec.reconcileWorkloads(context.Background(), workloads, result)
+
+	if len(result.Errors) != 0 {
+		t.Fatalf("unexpected errors: %v", result.Errors)
+	}
+
+	app, ok := fake.find("app.example.com", "A")
+	if !ok {
+		t.Fatal("expected app.example.com A record")
+	}
+	prov, ok := technitium.ParseProvenance(app.Comments)
+	if !ok {
+		t.Fatalf("expected provenance in comments, got %q", app.Comments)
+	}
+	if prov.Owner != "test" || prov.Workload != "media_app" || prov.WorkloadType != "service" ||
+		prov.Stack != "media" || prov.Router != "app" || prov.Created.IsZero() {
+		t.Errorf("unexpected provenance: %+v", prov)
+	}
+
+	web, _ := fake.find("web.example.com", "A")
+	prov, ok = technitium.ParseProvenance(web.Comments)
+	if !ok || !prov.Created.Equal(created) || prov.WorkloadType != "container" {
+		t.Errorf("expected TTL update to keep the creation time, got %q", web.Comments)
+	}
+
+	if len(result.OwnedRecords) != 1 || result.OwnedRecords[0].Name != "web.example.com" ||
+		result.OwnedRecords[0].Provenance.Workload != "web" {
+		t.Errorf("expected web.example.com reported as owned, got %+v", result.OwnedRecords)
+	}
+}
+
+// TestReconcileWorkloads_UpdateKeepsForeignComments verifies updating a record this
+// instance did not write keeps its comments instead of claiming it, and a record of
+// another instance is never repointed.
+func TestReconcileWorkloads_UpdateKeepsForeignComments(t *testing.T) {
+	otherOwner := technitium.Provenance{Owner: "other", Workload: "web"}
+	fake, client := newFakeTechnitium(t,
+		fakeRecord{Name: "app.example.com", Type: "A", TTL: 60, Value: "10.0.0.1", Comments: "added by hand"},
+		fakeRecord{Name: "web.example.com", Type: "A", TTL: 300, Value: "10.0.0.9", Comments: otherOwner.String()},
+	)
+
+	cfg := &config.Config{
+		TechnitiumZone: "example.com",
+		TargetIP:       "10.0.0.1",
+		TTL:            300,
+		OwnerID:        "test",
+	}
+	rec := New(cfg, nil, traefik.NewParser(), []*technitium.Client{client})
+
+	workloads := []docker.Workload{
+		{Name: "app", Type: "container", Labels: map[string]string{"traefik.http.routers.app.rule": "Host(`app.example.com`)"}},
+		{Name: "web", Type: "container", Labels: map[string]string{"traefik.http.routers.web.rule": "Host(`web.example.com`)"}},
+	}
+	result := &ReconcileResult{}
+
+	rec.reconcileWorkloads(context.Background(), workloads, result)
+
+	if len(result.Errors) != 0 || result.RecordsUpdated != 1 || result.RecordsCreated != 1 {
+		t.Fatalf("expected 1 update and 1 create without errors, got %d, %d and %v",
+			result.RecordsUpdated, result.RecordsCreated, result.Errors)
+	}
+	if app, _ := fake.find("app.example.com", "A"); app.TTL != 300 || app.Comments != "added by hand" {
+		t.Errorf("expected TTL update to keep the comments, got %+v", app)
+	}
+	if !fake.has("web.example.com", "A", "10.0.0.9") || !fake.has("web.example.com", "A", "10.0.0.1") {
+		t.Error("expected the other instance's record to stay next to ours")
+	}
+
+	// A second run changes nothing and owns only the record it created
+	result = &ReconcileResult{}
+	rec.reconcileWorkloads(context.Background(), workloads, result)
+	if result.RecordsUpdated != 0 || result.RecordsCreated != 0 || result.RecordsDeleted != 0 {
+		t.Errorf("expected no changes, got %+v", result)
+	}
+	if len(result.OwnedRecords) != 1 || result.OwnedRecords[0].Name != "web.example.com" {
+		t.Errorf("expected only the created web record owned, got %+v", result.OwnedRecords)
+	}
+}
+
+// TestReconcileWorkloads_ProvenanceTXT verifies the optional provenance TXT record is
+// written once per created hostname and removed with the hostname's orphaned records.
+func TestReconcileWorkloads_ProvenanceTXT(t *testing.T) {
+	goneProvenance := technitium.Provenance{Owner: "test", Workload: "gone"}
+	fake, client := newFakeTechnitium(t,
+		fakeRecord{Name: "gone.example.com", Type: "A", TTL: 300, Value: "10.0.0.1"},
+		fakeRecord{Name: ownershipName("gone.example.com"), Type: "TXT", TTL: 300, Value: ownershipValue("test")},
+		fakeRecord{Name: ownershipName("gone.example.com"), Type: "TXT", TTL: 300, Value: goneProvenance.String()},
+	)
+
+	cfg := &config.Config{
+		TechnitiumZone: "example.com",
+		TargetIP:       "10.0.0.1",
+		TargetIPs:      []string{"10.0.0.1", "2001:db8::1"},
+		TTL:            300,
+		OrphanCleanup:  true,
+		OwnerID:        "test",
+		ProvenanceTXT:  true,
+	}
+	rec := New(cfg, nil, traefik.NewParser(), []*technitium.Client{client})
+
+	workloads := []docker.Workload{
+		{Name: "app", Type: "service", Labels: map[string]string{"traefik.http.routers.app.rule": "Host(`app.example.com`)"}},
+	}
+	result := &ReconcileResult{}
+
+	rec.reconcileWorkloads(context.Background(), workloads, result)
+
+	if len(result.Errors) != 0 {
+		t.Fatalf("unexpected errors: %v", result.Errors)
+	}
+
+	var provenanceTXTs int
+	for _, r := range fake.records {
+		if r.Name != ownershipName("app.example.com") || r.Type != "TXT" {
+			continue
+		}
+		if prov, ok := technitium.ParseProvenance(r.Value); ok && prov.Workload == "app" {
+			provenanceTXTs++
+		}
+	}
+	if provenanceTXTs != 1 {
+		t.Errorf("expected 1 provenance TXT record for app.example.com, got %d", provenanceTXTs)
+	}
+	if !fake.has(ownershipName("app.example.com"), "TXT", ownershipValue("test")) {
+		t.Error("expected app.example.com to be claimed")
+	}
+	if fake.has(ownershipName("gone.example.com"), "TXT", goneProvenance.String()) {
+		t.Error("expected provenance TXT of the orphaned hostname to be deleted")
+	}
+	if result.RecordsCreated != 2 || result.RecordsDeleted != 1 {
+		t.Errorf("expected TXT records not to be counted, got %d created and %d deleted", result.RecordsCreated, result.RecordsDeleted)
+	}
+}
@@ -0,0 +1,478 @@
+package reconciler
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/maxfield-allison/technitium-companion/internal/technitium"
+)
+
+// desiredRecord is a DNS record that should exist according to the workloads' labels.
+type desiredRecord struct {
+	Zone     string
+	Name     string
+	Type     string
+	Value    string
+	TTL      int
+	Workload string
+
+	// Provenance details of the workload, written to the record's comments
+	WorkloadType string
+	Stack        string
+	Router       string
+	Host         string
+
+	// PreferPTR marks address records whose hostname should win the PTR record of a shared address.
+	PreferPTR bool
+}
+
+// recordChange is a single record operation in a reconciliation plan.
+type recordChange struct {
+	Zone     string
+	Name     string
+	Type     string
+	Value    string
+	TTL      int
+	Workload string
+
+	// OldValue is the value being replaced; only set for updates.
+	OldValue string
+	// Ownership marks bookkeeping TXT records, which are not counted in results or metrics.
+	Ownership bool
+	// Provenance is written to the record's comments by creates and by updates of
+	// records this instance wrote. For deletes it is the provenance read back from the
+	// record, if it had any.
+	Provenance technitium.Provenance
+	// Comments are the existing comments of a record being updated that this instance
+	// did not write; the update keeps them instead of writing Provenance, which is empty.
+	Comments string
+}
+
+// plan is the set of changes needed to move a zone from its current state to the desired state.
+type plan struct {
+	// Displaced are owned records removed to make room for a desired record that
+	// cannot coexist with them, such as an A record where a CNAME is wanted.
+	// They are applied before Creates.
+	Displaced []recordChange
+	Creates   []recordChange
+	Updates   []recordChange
+	Deletes   []recordChange
+	Unchanged []recordChange
+	// Stale are records at a desired name and type whose value is no longer wanted
+	// and that were not repointed by an update, e.g. a second address left behind
+	// when the target IP changed.
+	Stale []recordChange
+	// Conflicts are desired records skipped because records this instance does
+	// not own occupy the name.
+	Conflicts []recordChange
+}
+
+// planOptions controls how computePlan treats records outside the desired set.
+type planOptions struct {
+	// OrphanCleanup enables claiming created records and deleting owned records that are no longer desired.
+	OrphanCleanup bool
+	// OwnerID identifies this instance's ownership records.
+	OwnerID string
+	// Partial indicates the desired records cover only part of the zone, so records
+	// outside them cannot be treated as orphans, nor other values at a desired name
+	// as stale.
+	Partial bool
+	// ProvenanceTXT adds a TXT record carrying the provenance next to the ownership
+	// record of every hostname that gets a record created.
+	ProvenanceTXT bool
+	// Now is the creation time stamped on new records.
+	Now time.Time
+}
+
+// provenance returns the provenance of a record created for d.
+func (opts planOptions) provenance(d desiredRecord) technitium.Provenance {
+	return technitium.Provenance{
+		Owner:        opts.OwnerID,
+		Workload:     d.Workload,
+		WorkloadType: d.WorkloadType,
+		Stack:        d.Stack,
+		Router:       d.Router,
+		Host:         d.Host,
+		Created:      opts.Now,
+	}
+}
+
+// updating turns change into an update of rec. A record this instance wrote gets its
+// provenance refreshed, keeping its creation time; any other record keeps its comments,
+// so updating a record never makes it look owned.
+func (opts planOptions) updating(change recordChange, rec technitium.Record) recordChange {
+	change.OldValue = rec.Value()
+	if !ownedByProvenance(rec, opts.OwnerID) {
+		change.Provenance = technitium.Provenance{}
+		change.Comments = rec.Comments
+		return change
+	}
+	if prev, _ := rec.Provenance(); !prev.Created.IsZero() {
+		change.Provenance.Created = prev.Created
+	}
+	return change
+}
+
+// managedTypes are the record types orphan cleanup removes from an owned hostname.
+var managedTypes = map[string]struct{}{
+	"A":     {},
+	"AAAA":  {},
+	"CNAME": {},
+	"PTR":   {},
+}
+
+// additiveTypes are record types a name commonly holds several of, written by
+// different parties, such as verification TXT records at a zone apex. Declaring one
+// never claims its name; instead only records whose provenance names this instance
+// are repointed, pruned or removed as orphans, one record at a time.
+var additiveTypes = map[string]struct{}{
+	"SRV": {},
+	"TXT": {},
+}
+
+// exclusiveTypes are record types a name should hold a single value of. A desired
+// value is not added next to one written by someone else, but reported as a conflict.
+var exclusiveTypes = map[string]struct{}{
+	"CNAME": {},
+	"PTR":   {},
+}
+
+// ownedByProvenance reports whether a record's comments show this instance wrote it.
+func ownedByProvenance(rec technitium.Record, ownerID string) bool {
+	prov, ok := rec.Provenance()
+	return ok && prov.Owner == ownerID
+}
+
+// computePlan diffs the desired records of a zone against the records currently in it.
+// Desired records are deduplicated by name, type and value; the first workload wins.
+func computePlan(zone string, desired []desiredRecord, existing []technitium.Record, opts planOptions) plan {
+	var p plan
+
+	// Index existing records by lowercased hostname
+	byName := make(map[string][]technitium.Record)
+	for _, rec := range existing {
+		name := strings.ToLower(rec.Name)
+		byName[name] = append(byName[name], rec)
+	}
+
+	var owned map[string]struct{}
+	if opts.OrphanCleanup {
+		owned = make(map[string]struct{})
+		for hostname := range ownedHostnames(existing, opts.OwnerID) {
+			owned[strings.ToLower(hostname)] = struct{}{}
+		}
+	}
+
+	desiredNames := make(map[string]struct{})
+	seen := make(map[string]struct{})
+	claimed := make(map[string]struct{})
+	displaced := make(map[string]struct{})
+	described := make(map[string]struct{})
+
+	// describe adds the provenance TXT record of a hostname that gets a record created,
+	// unless it already has one from this instance
+	describe := func(change recordChange) {
+		if !opts.ProvenanceTXT {
+			return
+		}
+		name := strings.ToLower(change.Name)
+		if _, ok := described[name]; ok {
+			return
+		}
+		described[name] = struct{}{}
+		if len(provenanceRecords(byName[ownershipName(name)], opts.OwnerID)) > 0 {
+			return
+		}
+		p.Creates = append(p.Creates, recordChange{
+			Zone:      zone,
+			Name:      ownershipName(change.Name),
+			Type:      "TXT",
+			Value:     change.Provenance.String(),
+			TTL:       change.TTL,
+			Workload:  change.Workload,
+			Ownership: true,
+		})
+	}
+
+	// Group desired records by name and type, keeping first-seen order, so stale
+	// values of the same type can be paired with the values replacing them.
+	var groupKeys []string
+	groups := make(map[string][]desiredRecord)
+	for _, d := range desired {
+		name := strings.ToLower(d.Name)
+		key := name + "|" + d.Type + "|" + d.Value
+		if _, ok := seen[key]; ok {
+			continue
+		}
+		seen[key] = struct{}{}
+		desiredNames[name] = struct{}{}
+
+		groupKey := name + "|" + d.Type
+		if _, ok := groups[groupKey]; !ok {
+			groupKeys = append(groupKeys, groupKey)
+		}
+		groups[groupKey] = append(groups[groupKey], d)
+	}
+
+	for _, groupKey := range groupKeys {
+		group := groups[groupKey]
+		name := strings.ToLower(group[0].Name)
+		recordType := group[0].Type
+
+		_, additive := additiveTypes[recordType]
+
+		var missing []recordChange
+		wanted := make(map[string]struct{})
+		for _, d := range group {
+			wanted[d.Value] = struct{}{}
+
+			change := recordChange{
+				Zone:       zone,
+				Name:       d.Name,
+				Type:       d.Type,
+				Value:      d.Value,
+				TTL:        d.TTL,
+				Workload:   d.Workload,
+				Provenance: opts.provenance(d),
+			}
+
+			// Conflicting records are displaced when the hostname is owned, or when this
+			// instance wrote every one of them, e.g. switching from address to cname mode
+			// without orphan cleanup
+			if conflicts := conflictingRecords(byName[name], d); len(conflicts) > 0 {
+				_, isOwned := owned[name]
+				if !allManaged(conflicts) || (!isOwned && !allOwnedByProvenance(conflicts, opts.OwnerID)) {
+					p.Conflicts = append(p.Conflicts, change)
+					continue
+				}
+
+				for _, rec := range conflicts {
+					key := name + "|" + rec.Type + "|" + rec.Value()
+					if _, ok := displaced[key]; ok {
+						continue
+					}
+					displaced[key] = struct{}{}
+					prov, _ := rec.Provenance()
+					p.Displaced = append(p.Displaced, recordChange{
+						Zone:       zone,
+						Name:       rec.Name,
+						Type:       rec.Type,
+						Value:      rec.Value(),
+						TTL:        rec.TTL,
+						Workload:   d.Workload,
+						Provenance: prov,
+					})
+				}
+				p.Creates = append(p.Creates, change)
+				if !additive {
+					describe(change)
+				}
+				continue
+			}
+
+			match, found := findRecord(byName[name], d.Type, d.Value)
+			if found {
+				if match.TTL != d.TTL {
+					p.Updates = append(p.Updates, opts.updating(change, match))
+				} else {
+					p.Unchanged = append(p.Unchanged, change)
+				}
+				continue
+			}
+
+			missing = append(missing, change)
+		}
+
+		// Records of the same type pointing elsewhere are drift: each is repointed
+		// at a missing value in place, and any left over are deleted. Only records this
+		// instance wrote or whose hostname it owns are drift; others were written by
+		// hand or by another instance and are left alone. In a partial run they may be
+		// wanted by a workload that was left out, so they are kept.
+		var stale []technitium.Record
+		foreign := false
+		_, isOwned := owned[name]
+		for _, rec := range byName[name] {
+			if rec.Type != recordType {
+				continue
+			}
+			if _, ok := wanted[rec.Value()]; ok {
+				continue
+			}
+			if !ownedByProvenance(rec, opts.OwnerID) && (additive || !isOwned) {
+				foreign = true
+				continue
+			}
+			if !opts.Partial {
+				stale = append(stale, rec)
+			}
+		}
+
+		_, exclusive := exclusiveTypes[recordType]
+		for i, change := range missing {
+			if i < len(stale) {
+				p.Updates = append(p.Updates, opts.updating(change, stale[i]))
+				continue
+			}
+			if exclusive && foreign {
+				p.Conflicts = append(p.Conflicts, change)
+				continue
+			}
+
+			p.Creates = append(p.Creates, change)
+			if additive {
+				continue
+			}
+			describe(change)
+
+			// Claim the hostname alongside the record we create. Hostnames whose
+			// records all existed beforehand are never claimed.
+			if opts.OrphanCleanup {
+				if _, ok := owned[name]; ok {
+					continue
+				}
+				if _, ok := claimed[name]; ok {
+					continue
+				}
+				claimed[name] = struct{}{}
+				p.Creates = append(p.Creates, recordChange{
+					Zone:      zone,
+					Name:      ownershipName(change.Name),
+					Type:      "TXT",
+					Value:     ownershipValue(opts.OwnerID),
+					TTL:       change.TTL,
+					Workload:  change.Workload,
+					Ownership: true,
+				})
+			}
+		}
+
+		for i := len(missing); i < len(stale); i++ {
+			prov, _ := stale[i].Provenance()
+			p.Stale = append(p.Stale, recordChange{
+				Zone:       zone,
+				Name:       stale[i].Name,
+				Type:       stale[i].Type,
+				Value:      stale[i].Value(),
+				TTL:        stale[i].TTL,
+				Workload:   group[0].Workload,
+				Provenance: prov,
+			})
+		}
+	}
+
+	if !opts.OrphanCleanup || opts.Partial {
+		return p
+	}
+
+	// Delete owned hostnames nobody references anymore, in a stable order
+	orphans := make([]string, 0, len(owned))
+	for name := range owned {
+		if _, ok := desiredNames[name]; !ok {
+			orphans = append(orphans, name)
+		}
+	}
+	sort.Strings(orphans)
+
+	for _, name := range orphans {
+		for _, rec := range byName[name] {
+			if _, ok := managedTypes[rec.Type]; !ok {
+				continue
+			}
+			prov, _ := rec.Provenance()
+			p.Deletes = append(p.Deletes, recordChange{
+				Zone:       zone,
+				Name:       rec.Name,
+				Type:       rec.Type,
+				Value:      rec.Value(),
+				TTL:        rec.TTL,
+				Provenance: prov,
+			})
+		}
+		for _, rec := range provenanceRecords(byName[ownershipName(name)], opts.OwnerID) {
+			p.Deletes = append(p.Deletes, recordChange{
+				Zone:      zone,
+				Name:      rec.Name,
+				Type:      "TXT",
+				Value:     rec.Value(),
+				Ownership: true,
+			})
+		}
+		// The ownership record goes last so a partial failure is retried on the next run
+		p.Deletes = append(p.Deletes, recordChange{
+			Zone:      zone,
+			Name:      ownershipName(name),
+			Type:      "TXT",
+			Value:     ownershipValue(opts.OwnerID),
+			Ownership: true,
+		})
+	}
+
+	// Records of additive types this instance wrote are orphaned once no workload
+	// declares a record of their name and type
+	for _, rec := range existing {
+		if _, ok := additiveTypes[rec.Type]; !ok || !ownedByProvenance(rec, opts.OwnerID) {
+			continue
+		}
+		if _, ok := groups[strings.ToLower(rec.Name)+"|"+rec.Type]; ok {
+			continue
+		}
+		prov, _ := rec.Provenance()
+		p.Deletes = append(p.Deletes, recordChange{
+			Zone:       zone,
+			Name:       rec.Name,
+			Type:       rec.Type,
+			Value:      rec.Value(),
+			TTL:        rec.TTL,
+			Provenance: prov,
+		})
+	}
+
+	return p
+}
+
+// conflictingRecords returns the records at a name that cannot coexist with a desired
+// record: a CNAME excludes every record of another type at its name. A CNAME with a
+// different target is drift rather than a conflict and is repointed instead.
+func conflictingRecords(records []technitium.Record, d desiredRecord) []technitium.Record {
+	var conflicts []technitium.Record
+	for _, rec := range records {
+		if rec.Type == d.Type {
+			continue
+		}
+		if d.Type == "CNAME" || rec.Type == "CNAME" {
+			conflicts = append(conflicts, rec)
+		}
+	}
+	return conflicts
+}
+
+// allManaged reports whether every record is of a type the companion manages.
+func allManaged(records []technitium.Record) bool {
+	for _, rec := range records {
+		if _, ok := managedTypes[rec.Type]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// allOwnedByProvenance reports whether this instance wrote every record.
+func allOwnedByProvenance(records []technitium.Record, ownerID string) bool {
+	for _, rec := range records {
+		if !ownedByProvenance(rec, ownerID) {
+			return false
+		}
+	}
+	return true
+}
+
+// findRecord returns the record with the given type and value, if present.
+func findRecord(records []technitium.Record, recordType, value string) (technitium.Record, bool) {
+	for _, rec := range records {
+		if rec.Type == recordType && rec.Value() == value {
+			return rec, true
+		}
+	}
+	return technitium.Record{}, false
+}
@@ -0,0 +1,686 @@
+package reconciler
+
+import (
+	"context"
+	"testing"
+
+	"github.com/maxfield-allison/technitium-companion/internal/config"
+	"github.com/maxfield-allison/technitium-companion/internal/docker"
+	"github.com/maxfield-allison/technitium-companion/internal/technitium"
+	"github.com/maxfield-allison/technitium-companion/internal/traefik"
+)
+
+// aRecord builds a Technitium A record for plan tests.
+func aRecord(name, ip string, ttl int) technitium.Record {
+	return technitium.Record{Name: name, Type: "A", TTL: ttl, RData: technitium.RData{IPAddress: ip}}
+}
+
+// TestComputePlan_CreatesMissing verifies missing records are created and present ones left alone.
+func TestComputePlan_CreatesMissing(t *testing.T) {
+	desired := []desiredRecord{
+		{Zone: "example.com", Name: "new.example.com", Type: "A", Value: "10.0.0.1", TTL: 300},
+		{Zone: "example.com", Name: "old.example.com", Type: "A", Value: "10.0.0.1", TTL: 300},
+	}
+	existing := []technitium.Record{
+		aRecord("old.example.com", "10.0.0.1", 300),
+	}
+
+	p := computePlan("example.com", desired, existing, planOptions{})
+
+	if len(p.Creates) != 1 || p.Creates[0].Name != "new.example.com" {
+		t.Errorf("expected a single create for new.example.com, got %+v", p.Creates)
+	}
+	if len(p.Unchanged) != 1 {
+		t.Errorf("expected 1 unchanged record, got %d", len(p.Unchanged))
+	}
+	if len(p.Updates) != 0 || len(p.Deletes) != 0 {
+		t.Errorf("expected no updates or deletes, got %+v / %+v", p.Updates, p.Deletes)
+	}
+}
+
+// TestComputePlan_UpdatesTTL verifies a TTL mismatch produces an in-place update.
+func TestComputePlan_UpdatesTTL(t *testing.T) {
+	desired := []desiredRecord{
+		{Zone: "example.com", Name: "app.example.com", Type: "A", Value: "10.0.0.1", TTL: 600},
+	}
+	existing := []technitium.Record{
+		aRecord("app.example.com", "10.0.0.1", 300),
+	}
+
+	p := computePlan("example.com", desired, existing, planOptions{})
+
+	if len(p.Updates) != 1 {
+		t.Fatalf("expected 1 update, got %d", len(p.Updates))
+	}
+	u := p.Updates[0]
+	if u.OldValue != "10.0.0.1" || u.Value != "10.0.0.1" || u.TTL != 600 {
+		t.Errorf("unexpected update: %+v", u)
+	}
+}
+
+// ownedARecord builds an A record carrying the provenance of owner.
+func ownedARecord(name, ip, owner string) technitium.Record {
+	rec := aRecord(name, ip, 300)
+	rec.Comments = technitium.Provenance{Owner: owner, Workload: "app"}.String()
+	return rec
+}
+
+// TestComputePlan_RepointsStaleAddress verifies an owned record pointing at an old address
+// is updated in place rather than joined by a second record, and extra stale records are deleted.
+func TestComputePlan_RepointsStaleAddress(t *testing.T) {
+	desired := []desiredRecord{
+		{Zone: "example.com", Name: "app.example.com", Type: "A", Value: "10.0.0.2", TTL: 300},
+		{Zone: "example.com", Name: "db.example.com", Type: "A", Value: "10.0.0.2", TTL: 300},
+	}
+	existing := []technitium.Record{
+		ownedARecord("app.example.com", "10.0.0.1", "default"),
+		aRecord("db.example.com", "10.0.0.2", 300),
+		aRecord("db.example.com", "10.0.0.9", 300),
+		{Name: "db.example.com", Type: "AAAA", TTL: 300, RData: technitium.RData{IPAddress: "2001:db8::9"}},
+		{Name: ownershipName("db.example.com"), Type: "TXT", RData: technitium.RData{Text: ownershipValue("default")}},
+	}
+
+	p := computePlan("example.com", desired, existing, planOptions{OrphanCleanup: true, OwnerID: "default"})
+
+	if len(p.Updates) != 1 {
+		t.Fatalf("expected 1 update, got %+v", p.Updates)
+	}
+	if u := p.Updates[0]; u.Name != "app.example.com" || u.OldValue != "10.0.0.1" || u.Value != "10.0.0.2" {
+		t.Errorf("unexpected update: %+v", u)
+	}
+	if len(p.Creates) != 0 {
+		t.Errorf("expected no creates, got %+v", p.Creates)
+	}
+	if len(p.Stale) != 1 || p.Stale[0].Name != "db.example.com" || p.Stale[0].Value != "10.0.0.9" {
+		t.Errorf("expected the stale db.example.com A record to be deleted, got %+v", p.Stale)
+	}
+	if len(p.Unchanged) != 1 {
+		t.Errorf("expected 1 unchanged record, got %d", len(p.Unchanged))
+	}
+}
+
+// TestComputePlan_LeavesForeignRecords verifies records written by hand or by another
+// instance at a desired name are neither repointed nor deleted.
+func TestComputePlan_LeavesForeignRecords(t *testing.T) {
+	desired := []desiredRecord{
+		{Zone: "example.com", Name: "app.example.com", Type: "A", Value: "10.0.0.2", TTL: 300},
+		{Zone: "example.com", Name: "web.example.com", Type: "A", Value: "10.0.0.2", TTL: 300},
+		{Zone: "example.com", Name: "alias.example.com", Type: "CNAME", Value: "traefik.example.com", TTL: 300},
+	}
+	existing := []technitium.Record{
+		ownedARecord("app.example.com", "10.0.0.1", "other"),
+		aRecord("web.example.com", "10.0.0.9", 300),
+		{Name: "alias.example.com", Type: "CNAME", TTL: 300, RData: technitium.RData{CNAME: "elsewhere.example.com"}},
+	}
+
+	p := computePlan("example.com", desired, existing, planOptions{OrphanCleanup: true, OwnerID: "default"})
+
+	if len(p.Updates) != 0 || len(p.Stale) != 0 || len(p.Deletes) != 0 || len(p.Displaced) != 0 {
+		t.Errorf("expected foreign records untouched, got updates %+v, stale %+v, deletes %+v", p.Updates, p.Stale, p.Deletes)
+	}
+	var created []string
+	for _, c := range p.Creates {
+		if !c.Ownership {
+			created = append(created, c.Name)
+		}
+	}
+	if len(created) != 2 || created[0] != "app.example.com" || created[1] != "web.example.com" {
+		t.Errorf("expected address records added next to the foreign ones, got %+v", created)
+	}
+	if len(p.Conflicts) != 1 || p.Conflicts[0].Name != "alias.example.com" {
+		t.Errorf("expected the foreign CNAME to conflict, got %+v", p.Conflicts)
+	}
+}
+
+// TestComputePlan_Deduplicates verifies a hostname shared by several workloads is planned once.
+func TestComputePlan_Deduplicates(t *testing.T) {
+	desired := []desiredRecord{
+		{Zone: "example.com", Name: "app.example.com", Type: "A", Value: "10.0.0.1", TTL: 300, Workload: "first"},
+		{Zone: "example.com", Name: "APP.example.com", Type: "A", Value: "10.0.0.1", TTL: 300, Workload: "second"},
+	}
+
+	p := computePlan("example.com", desired, nil, planOptions{})
+
+	if len(p.Creates) != 1 {
+		t.Fatalf("expected 1 create, got %d", len(p.Creates))
+	}
+	if p.Creates[0].Workload != "first" {
+		t.Errorf("expected first workload to win, got %s", p.Creates[0].Workload)
+	}
+}
+
+// TestComputePlan_OrphansRequireCleanup verifies owned orphans are only deleted when cleanup is enabled.
+func TestComputePlan_OrphansRequireCleanup(t *testing.T) {
+	existing := []technitium.Record{
+		aRecord("gone.example.com", "10.0.0.1", 300),
+		{Name: ownershipName("gone.example.com"), Type: "TXT", RData: technitium.RData{Text: ownershipValue("default")}},
+	}
+
+	p := computePlan("example.com", nil, existing, planOptions{})
+	if len(p.Deletes) != 0 {
+		t.Errorf("expected no deletes without orphan cleanup, got %+v", p.Deletes)
+	}
+
+	p = computePlan("example.com", nil, existing, planOptions{OrphanCleanup: true, OwnerID: "default"})
+	if len(p.Deletes) != 2 {
+		t.Fatalf("expected 2 deletes, got %+v", p.Deletes)
+	}
+	if p.Deletes[0].Type != "A" || !p.Deletes[1].Ownership {
+		t.Errorf("expected A record before ownership record, got %+v", p.Deletes)
+	}
+}
+
+// TestComputePlan_CNAMEConflicts verifies a CNAME is only created over address records
+// the instance owns, and repointed in place when it targets another name.
+func TestComputePlan_CNAMEConflicts(t *testing.T) {
+	desired := []desiredRecord{
+		{Zone: "example.com", Name: "owned.example.com", Type: "CNAME", Value: "traefik.example.com", TTL: 300},
+		{Zone: "example.com", Name: "manual.example.com", Type: "CNAME", Value: "traefik.example.com", TTL: 300},
+		{Zone: "example.com", Name: "moved.example.com", Type: "CNAME", Value: "traefik.example.com", TTL: 300},
+	}
+	existing := []technitium.Record{
+		aRecord("owned.example.com", "10.0.0.1", 300),
+		{Name: "owned.example.com", Type: "AAAA", TTL: 300, RData: technitium.RData{IPAddress: "2001:db8::1"}},
+		{Name: ownershipName("owned.example.com"), Type: "TXT", RData: technitium.RData{Text: ownershipValue("default")}},
+		aRecord("manual.example.com", "10.0.0.9", 300),
+		{Name: "moved.example.com", Type: "CNAME", TTL: 300, RData: technitium.RData{CNAME: "old.example.com"}},
+		{Name: ownershipName("moved.example.com"), Type: "TXT", RData: technitium.RData{Text: ownershipValue("default")}},
+	}
+
+	p := computePlan("example.com", desired, existing, planOptions{OrphanCleanup: true, OwnerID: "default"})
+
+	if len(p.Displaced) != 2 || p.Displaced[0].Type != "A" || p.Displaced[1].Type != "AAAA" {
+		t.Errorf("expected owned A and AAAA records to be displaced, got %+v", p.Displaced)
+	}
+	if len(p.Creates) != 1 || p.Creates[0].Name != "owned.example.com" || p.Creates[0].Type != "CNAME" {
+		t.Errorf("expected a CNAME create for owned.example.com, got %+v", p.Creates)
+	}
+	if len(p.Conflicts) != 1 || p.Conflicts[0].Name != "manual.example.com" {
+		t.Errorf("expected manual.example.com to conflict, got %+v", p.Conflicts)
+	}
+	if len(p.Updates) != 1 || p.Updates[0].Name != "moved.example.com" || p.Updates[0].OldValue != "old.example.com" {
+		t.Errorf("expected moved.example.com to be repointed, got %+v", p.Updates)
+	}
+}
+
+// TestReconcileWorkloads_CNAMEMode verifies hostnames become CNAMEs to the canonical
+// ingress name, skipping the canonical name itself and names held by foreign records.
+func TestReconcileWorkloads_CNAMEMode(t *testing.T) {
+	fake, client := newFakeTechnitium(t,
+		fakeRecord{Name: "traefik.example.com", Type: "A", TTL: 300, Value: "10.0.0.1"},
+		fakeRecord{Name: "legacy.example.com", Type: "A", TTL: 300, Value: "10.0.0.7"},
+	)
+
+	cfg := &config.Config{
+		TechnitiumZone: "example.com",
+		RecordMode:     config.RecordModeCNAME,
+		CNAMETarget:    "traefik.example.com",
+		TTL:            300,
+	}
+	rec := New(cfg, nil, traefik.NewParser(), []*technitium.Client{client})
+
+	workloads := []docker.Workload{
+		{Name: "traefik", Labels: map[string]string{"traefik.http.routers.t.rule": "Host(`traefik.example.com`)"}},
+		{Name: "app", Labels: map[string]string{"traefik.http.routers.app.rule": "Host(`app.example.com`) || Host(`legacy.example.com`)"}},
+	}
+	result := &ReconcileResult{}
+
+	rec.reconcileWorkloads(context.Background(), workloads, result)
+
+	if len(result.Errors) != 0 {
+		t.Fatalf("unexpected errors: %v", result.Errors)
+	}
+	if !fake.has("app.example.com", "CNAME", "traefik.example.com") {
+		t.Error("expected app.example.com CNAME to traefik.example.com")
+	}
+	if fake.has("traefik.example.com", "CNAME", "traefik.example.com") {
+		t.Error("expected no CNAME loop at the canonical name")
+	}
+	if !fake.has("legacy.example.com", "A", "10.0.0.7") || fake.has("legacy.example.com", "CNAME", "traefik.example.com") {
+		t.Error("expected unowned legacy.example.com A record to be left alone")
+	}
+	if len(result.HostnamesConflicted) != 1 || result.HostnamesConflicted[0] != "legacy.example.com" {
+		t.Errorf("expected legacy.example.com to be reported as conflicted, got %v", result.HostnamesConflicted)
+	}
+}
+
+// TestReconcileWorkloads_SwitchToCNAMEMode verifies switching a hostname from address
+// records to a CNAME replaces the address records the instance wrote, without relying
+// on the ownership records of orphan cleanup.
+func TestReconcileWorkloads_SwitchToCNAMEMode(t *testing.T) {
+	fake, client := newFakeTechnitium(t)
+
+	cfg := &config.Config{
+		TechnitiumZone: "example.com",
+		TargetIP:       "10.0.0.1",
+		TTL:            300,
+		OwnerID:        "test",
+	}
+	rec := New(cfg, nil, traefik.NewParser(), []*technitium.Client{client})
+
+	workloads := []docker.Workload{
+		{Name: "app", Labels: map[string]string{"traefik.http.routers.app.rule": "Host(`app.example.com`)"}},
+	}
+	result := &ReconcileResult{}
+	rec.reconcileWorkloads(context.Background(), workloads, result)
+	if !fake.has("app.example.com", "A", "10.0.0.1") {
+		t.Fatal("expected app.example.com A record")
+	}
+
+	cfg.RecordMode = config.RecordModeCNAME
+	cfg.CNAMETarget = "traefik.example.com"
+	result = &ReconcileResult{}
+	rec.reconcileWorkloads(context.Background(), workloads, result)
+
+	if len(result.Errors) != 0 || len(result.HostnamesConflicted) != 0 {
+		t.Fatalf("unexpected errors %v or conflicts %v", result.Errors, result.HostnamesConflicted)
+	}
+	if !fake.has("app.example.com", "CNAME", "traefik.example.com") {
+		t.Error("expected app.example.com CNAME to traefik.example.com")
+	}
+	if fake.has("app.example.com", "A", "10.0.0.1") {
+		t.Error("expected the A record to be replaced by the CNAME")
+	}
+}
+
+// TestReconcileWorkloads_SingleZoneFetch verifies a large reconcile reads the zone once
+// and only writes the records that are missing.
+func TestReconcileWorkloads_SingleZoneFetch(t *testing.T) {
+	fake, client := newFakeTechnitium(t,
+		fakeRecord{Name: "existing.example.com", Type: "A", TTL: 300, Value: "10.0.0.1"},
+	)
+
+	cfg := &config.Config{
+		TechnitiumZone: "example.com",
+		TargetIP:       "10.0.0.1",
+		TTL:            300,
+	}
+	rec := New(cfg, nil, traefik.NewParser(), []*technitium.Client{client})
+
+	workloads := []docker.Workload{
+		{Name: "a", Labels: map[string]string{"traefik.http.routers.a.rule": "Host(`a.example.com`) || Host(`existing.example.com`)"}},
+		{Name: "b", Labels: map[string]string{"traefik.http.routers.b.rule": "Host(`b.example.com`)"}},
+		{Name: "c", Labels: map[string]string{"traefik.http.routers.c.rule": "Host(`existing.example.com`)"}},
+	}
+	result := &ReconcileResult{}
+
+	rec.reconcileWorkloads(context.Background(), workloads, result)
+
+	if len(result.Errors) != 0 {
+		t.Fatalf("unexpected errors: %v", result.Errors)
+	}
+	if fake.calls["/api/zones/records/get"] != 1 {
+		t.Errorf("expected 1 records/get call, got %d", fake.calls["/api/zones/records/get"])
+	}
+	if fake.calls["/api/zones/records/add"] != 2 {
+		t.Errorf("expected 2 records/add calls, got %d", fake.calls["/api/zones/records/add"])
+	}
+	if result.RecordsCreated != 2 {
+		t.Errorf("expected 2 records created, got %d", result.RecordsCreated)
+	}
+	if result.RecordsExisted != 1 {
+		t.Errorf("expected 1 record existed, got %d", result.RecordsExisted)
+	}
+}
+
+// TestReconcileWorkloads_DualStack verifies IPv4 and IPv6 targets produce A and AAAA
+// records that are managed together, including orphan cleanup.
+func TestReconcileWorkloads_DualStack(t *testing.T) {
+	fake, client := newFakeTechnitium(t,
+		fakeRecord{Name: "web.example.com", Type: "AAAA", TTL: 300, Value: "2001:db8::1"},
+		fakeRecord{Name: "gone.example.com", Type: "A", TTL: 300, Value: "10.0.0.1"},
+		fakeRecord{Name: "gone.example.com", Type: "AAAA", TTL: 300, Value: "2001:db8::1"},
+		fakeRecord{Name: ownershipName("gone.example.com"), Type: "TXT", TTL: 300, Value: ownershipValue("test")},
+	)
+
+	cfg := &config.Config{
+		TechnitiumZone: "example.com",
+		TargetIP:       "10.0.0.1",
+		TargetIPs:      []string{"10.0.0.1", "2001:db8::1"},
+		TTL:            300,
+		OrphanCleanup:  true,
+		OwnerID:        "test",
+	}
+	rec := New(cfg, nil, traefik.NewParser(), []*technitium.Client{client})
+
+	workloads := []docker.Workload{
+		{Name: "web", Labels: map[string]string{"traefik.http.routers.web.rule": "Host(`web.example.com`)"}},
+	}
+	result := &ReconcileResult{}
+
+	rec.reconcileWorkloads(context.Background(), workloads, result)
+
+	if len(result.Errors) != 0 {
+		t.Fatalf("unexpected errors: %v", result.Errors)
+	}
+	if !fake.has("web.example.com", "A", "10.0.0.1") || !fake.has("web.example.com", "AAAA", "2001:db8::1") {
+		t.Error("expected both A and AAAA records for web.example.com")
+	}
+	if fake.has("gone.example.com", "A", "10.0.0.1") || fake.has("gone.example.com", "AAAA", "2001:db8::1") {
+		t.Error("expected both orphaned address records to be deleted")
+	}
+	if result.RecordsCreated != 1 || result.RecordsExisted != 1 || result.RecordsDeleted != 2 {
+		t.Errorf("expected 1 created, 1 existed, 2 deleted, got %d/%d/%d",
+			result.RecordsCreated, result.RecordsExisted, result.RecordsDeleted)
+	}
+}
+
+// TestReconcileWorkloads_Wildcards verifies wildcard hosts are published, with an
+// ownership record, only when wildcards are allowed.
+func TestReconcileWorkloads_Wildcards(t *testing.T) {
+	workloads := []docker.Workload{
+		{Name: "apps", Labels: map[string]string{
+			"traefik.http.routers.apps.rule": "HostRegexp(`{sub:[a-z]+}.apps.example.com`) || Host(`apps.example.com`)",
+		}},
+	}
+
+	for _, allow := range []bool{false, true} {
+		fake, client := newFakeTechnitium(t)
+
+		cfg := &config.Config{
+			TechnitiumZone: "example.com",
+			TargetIP:       "10.0.0.1",
+			TTL:            300,
+			AllowWildcards: allow,
+			OrphanCleanup:  true,
+			OwnerID:        "test",
+		}
+		rec := New(cfg, nil, traefik.NewParser(), []*technitium.Client{client})
+		result := &ReconcileResult{}
+
+		rec.reconcileWorkloads(context.Background(), workloads, result)
+
+		if len(result.Errors) != 0 {
+			t.Fatalf("unexpected errors: %v", result.Errors)
+		}
+		if !fake.has("apps.example.com", "A", "10.0.0.1") {
+			t.Error("expected A record for apps.example.com")
+		}
+		if got := fake.has("*.apps.example.com", "A", "10.0.0.1"); got != allow {
+			t.Errorf("AllowWildcards=%v: wildcard record present = %v", allow, got)
+		}
+		if got := fake.has("_technitium-companion._wildcard.apps.example.com", "TXT", ownershipValue("test")); got != allow {
+			t.Errorf("AllowWildcards=%v: wildcard ownership record present = %v", allow, got)
+		}
+	}
+}
+
+// TestReconcileWorkloads_CorrectsDrift verifies a stale address is replaced through the
+// update endpoint and reported as updated.
+func TestReconcileWorkloads_CorrectsDrift(t *testing.T) {
+	prov := technitium.Provenance{Owner: "test", Workload: "app"}
+	fake, client := newFakeTechnitium(t,
+		fakeRecord{Name: "app.example.com", Type: "A", TTL: 300, Value: "10.0.0.1", Comments: prov.String()},
+	)
+
+	cfg := &config.Config{
+		TechnitiumZone: "example.com",
+		TargetIP:       "10.0.0.2",
+		TTL:            300,
+		OwnerID:        "test",
+	}
+	rec := New(cfg, nil, traefik.NewParser(), []*technitium.Client{client})
+
+	workloads := []docker.Workload{
+		{Name: "app", Labels: map[string]string{"traefik.http.routers.app.rule": "Host(`app.example.com`)"}},
+	}
+	result := &ReconcileResult{}
+
+	rec.reconcileWorkloads(context.Background(), workloads, result)
+
+	if len(result.Errors) != 0 {
+		t.Fatalf("unexpected errors: %v", result.Errors)
+	}
+	if fake.calls["/api/zones/records/update"] != 1 || fake.calls["/api/zones/records/add"] != 0 {
+		t.Errorf("expected a single update and no add, got %v", fake.calls)
+	}
+	if !fake.has("app.example.com", "A", "10.0.0.2") || fake.has("app.example.com", "A", "10.0.0.1") {
+		t.Error("expected app.example.com to point only at 10.0.0.2")
+	}
+	if result.RecordsUpdated != 1 {
+		t.Errorf("expected 1 record updated, got %d", result.RecordsUpdated)
+	}
+}
+
+// TestReconcileWorkloads_MultipleZones verifies hostnames are routed to their most specific
+// zone and hostnames outside every zone are reported instead of sent to the API.
+func TestReconcileWorkloads_MultipleZones(t *testing.T) {
+	fake, client := newFakeTechnitium(t)
+
+	cfg := &config.Config{
+		TechnitiumZone:  "example.com",
+		TechnitiumZones: []string{"example.com", "lab.example.com", "example.net"},
+		TargetIP:        "10.0.0.1",
+		TTL:             300,
+	}
+	rec := New(cfg, nil, traefik.NewParser(), []*technitium.Client{client})
+
+	workloads := []docker.Workload{
+		{Name: "app", Labels: map[string]string{
+			"traefik.http.routers.a.rule": "Host(`app.lab.example.com`) || Host(`app.example.net`)",
+			"traefik.http.routers.b.rule": "Host(`app.example.org`)",
+		}},
+	}
+	result := &ReconcileResult{}
+
+	rec.reconcileWorkloads(context.Background(), workloads, result)
+
+	if len(result.Errors) != 0 {
+		t.Fatalf("unexpected errors: %v", result.Errors)
+	}
+	if result.RecordsCreated != 2 {
+		t.Errorf("expected 2 records created, got %d", result.RecordsCreated)
+	}
+	if len(result.HostnamesUnmatched) != 1 || result.HostnamesUnmatched[0] != "app.example.org" {
+		t.Errorf("expected app.example.org to be unmatched, got %v", result.HostnamesUnmatched)
+	}
+	if !fake.hasInZone("lab.example.com", "app.lab.example.com", "A", "10.0.0.1") {
+		t.Error("expected app.lab.example.com in zone lab.example.com")
+	}
+	if !fake.hasInZone("example.net", "app.example.net", "A", "10.0.0.1") {
+		t.Error("expected app.example.net in zone example.net")
+	}
+	if fake.calls["/api/zones/records/get"] != 3 {
+		t.Errorf("expected one records/get per zone, got %d", fake.calls["/api/zones/records/get"])
+	}
+}
+
+// TestReconcileWorkloads_LabelOverrides verifies per-workload target IP, TTL, zone and opt-out labels.
+func TestReconcileWorkloads_LabelOverrides(t *testing.T) {
+	fake, client := newFakeTechnitium(t)
+
+	cfg := &config.Config{
+		TechnitiumZone:  "example.com",
+		TechnitiumZones: []string{"example.com", "lab.example.com"},
+		TargetIP:        "10.0.0.1",
+		TTL:             300,
+	}
+	rec := New(cfg, nil, traefik.NewParser(), []*technitium.Client{client})
+
+	workloads := []docker.Workload{
+		{Name: "vip", Labels: map[string]string{
+			"traefik.http.routers.vip.rule":  "Host(`vip.lab.example.com`)",
+			"technitium-companion.target-ip": "10.0.0.50",
+			"technitium-companion.ttl":       "60",
+			"technitium-companion.zone":      "example.com",
+		}},
+		{Name: "optout", Labels: map[string]string{
+			"traefik.http.routers.optout.rule": "Host(`optout.example.com`)",
+			"technitium-companion.enable":      "false",
+		}},
+		{Name: "outside", Labels: map[string]string{
+			"traefik.http.routers.outside.rule": "Host(`outside.example.com`)",
+			"technitium-companion.zone":         "lab.example.com",
+		}},
+	}
+	result := &ReconcileResult{}
+
+	rec.reconcileWorkloads(context.Background(), workloads, result)
+
+	if len(result.Errors) != 0 {
+		t.Fatalf("unexpected errors: %v", result.Errors)
+	}
+	if result.RecordsCreated != 1 {
+		t.Errorf("expected 1 record created, got %d", result.RecordsCreated)
+	}
+	if !fake.hasInZone("example.com", "vip.lab.example.com", "A", "10.0.0.50") {
+		t.Error("expected vip record with overridden IP in overridden zone")
+	}
+	if fake.has("optout.example.com", "A", "10.0.0.1") {
+		t.Error("expected opted-out workload to get no record")
+	}
+	if len(result.HostnamesUnmatched) != 1 || result.HostnamesUnmatched[0] != "outside.example.com" {
+		t.Errorf("expected outside.example.com to be unmatched, got %v", result.HostnamesUnmatched)
+	}
+
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+	for _, r := range fake.records {
+		if r.Name == "vip.lab.example.com" && r.TTL != 60 {
+			t.Errorf("expected overridden TTL 60, got %d", r.TTL)
+		}
+	}
+}
+
+// TestReconcileWorkloads_InvalidLabelFallsBack verifies an invalid override is reported
+// and the global value used instead.
+func TestReconcileWorkloads_InvalidLabelFallsBack(t *testing.T) {
+	fake, client := newFakeTechnitium(t)
+
+	cfg := &config.Config{
+		TechnitiumZone: "example.com",
+		TargetIP:       "10.0.0.1",
+		TTL:            300,
+	}
+	rec := New(cfg, nil, traefik.NewParser(), []*technitium.Client{client})
+
+	workloads := []docker.Workload{
+		{Name: "app", Labels: map[string]string{
+			"traefik.http.routers.app.rule":  "Host(`app.example.com`)",
+			"technitium-companion.target-ip": "bogus",
+		}},
+	}
+	result := &ReconcileResult{}
+
+	rec.reconcileWorkloads(context.Background(), workloads, result)
+
+	if len(result.Errors) != 1 {
+		t.Errorf("expected 1 error for the invalid label, got %v", result.Errors)
+	}
+	if !fake.has("app.example.com", "A", "10.0.0.1") {
+		t.Error("expected record with the global target IP")
+	}
+}
+
+// TestComputePlan_AdditiveTypes verifies declared TXT and SRV records leave records
+// written by others alone, claim no hostname, and repoint or remove only records whose
+// provenance names this instance.
+func TestComputePlan_AdditiveTypes(t *testing.T) {
+	ours := technitium.Provenance{Owner: "default", Workload: "app"}.String()
+	desired := []desiredRecord{
+		{Zone: "example.com", Name: "example.com", Type: "TXT", Value: "verification=new", TTL: 300, Workload: "app"},
+	}
+	existing := []technitium.Record{
+		{Name: "example.com", Type: "TXT", TTL: 300, RData: technitium.RData{Text: "v=spf1 -all"}},
+		{Name: "example.com", Type: "TXT", TTL: 300, RData: technitium.RData{Text: "verification=old"}, Comments: ours},
+		{Name: "_minecraft._tcp.example.com", Type: "SRV", TTL: 300, RData: technitium.RData{Port: 25565, Target: "mc.example.com"}, Comments: ours},
+		{Name: "_sip._udp.example.com", Type: "SRV", TTL: 300, RData: technitium.RData{Port: 5060, Target: "sip.example.com"}},
+	}
+
+	p := computePlan("example.com", desired, existing, planOptions{OrphanCleanup: true, OwnerID: "default"})
+
+	if len(p.Updates) != 1 || p.Updates[0].OldValue != "verification=old" || p.Updates[0].Value != "verification=new" {
+		t.Errorf("expected our TXT record to be repointed, got %+v", p.Updates)
+	}
+	if len(p.Creates) != 0 || len(p.Stale) != 0 {
+		t.Errorf("expected no creates, ownership claims or stale records, got %+v / %+v", p.Creates, p.Stale)
+	}
+	if len(p.Deletes) != 1 || p.Deletes[0].Type != "SRV" || p.Deletes[0].Value != "0 0 25565 mc.example.com" {
+		t.Errorf("expected only our orphaned SRV record to be deleted, got %+v", p.Deletes)
+	}
+
+	// Without orphan cleanup records no workload declares are kept
+	p = computePlan("example.com", nil, existing, planOptions{OwnerID: "default"})
+	if len(p.Deletes) != 0 {
+		t.Errorf("expected no deletes without orphan cleanup, got %+v", p.Deletes)
+	}
+}
+
+// TestReconcileWorkloads_DeclaredRecords verifies SRV, TXT and CNAME records declared
+// through labels are created, updated and, with orphan cleanup, removed again.
+func TestReconcileWorkloads_DeclaredRecords(t *testing.T) {
+	fake, client := newFakeTechnitium(t,
+		fakeRecord{Zone: "example.com", Name: "example.com", Type: "TXT", TTL: 300, Value: "v=spf1 -all"},
+	)
+
+	cfg := &config.Config{
+		TechnitiumZone: "example.com",
+		TargetIP:       "10.0.0.1",
+		TTL:            300,
+		OrphanCleanup:  true,
+		OwnerID:        "default",
+	}
+	rec := New(cfg, nil, traefik.NewParser(), []*technitium.Client{client})
+
+	declare := func(port string) []docker.Workload {
+		return []docker.Workload{{Name: "minecraft", Labels: map[string]string{
+			"technitium-companion.records.srv.type":     "SRV",
+			"technitium-companion.records.srv.name":     "_minecraft._tcp.example.com",
+			"technitium-companion.records.srv.value":    "0 5 " + port + " mc.example.com",
+			"technitium-companion.records.verify.type":  "TXT",
+			"technitium-companion.records.verify.name":  "example.com",
+			"technitium-companion.records.verify.value": "verification=abc",
+			"technitium-companion.records.mc.type":      "CNAME",
+			"technitium-companion.records.mc.name":      "mc.example.com",
+			"technitium-companion.records.mc.value":     "host1.example.com",
+		}}}
+	}
+
+	result := &ReconcileResult{}
+	rec.reconcileWorkloads(context.Background(), declare("25565"), result)
+
+	if len(result.Errors) != 0 {
+		t.Fatalf("unexpected errors: %v", result.Errors)
+	}
+	if result.RecordsCreated != 3 {
+		t.Errorf("expected 3 records created, got %d", result.RecordsCreated)
+	}
+	if !fake.has("_minecraft._tcp.example.com", "SRV", "0 5 25565 mc.example.com") {
+		t.Error("expected SRV record")
+	}
+	if !fake.has("example.com", "TXT", "verification=abc") || !fake.has("example.com", "TXT", "v=spf1 -all") {
+		t.Error("expected the declared TXT record next to the existing one")
+	}
+	if !fake.has("mc.example.com", "CNAME", "host1.example.com") {
+		t.Error("expected CNAME record")
+	}
+	if fake.has("_technitium-companion.example.com", "TXT", ownershipValue("default")) {
+		t.Error("expected the zone apex not to be claimed for a TXT record")
+	}
+
+	result = &ReconcileResult{}
+	rec.reconcileWorkloads(context.Background(), declare("25566"), result)
+
+	if result.RecordsUpdated != 1 || !fake.has("_minecraft._tcp.example.com", "SRV", "0 5 25566 mc.example.com") {
+		t.Errorf("expected the SRV record to be repointed, got %d updates", result.RecordsUpdated)
+	}
+
+	result = &ReconcileResult{}
+	rec.reconcileWorkloads(context.Background(), nil, result)
+
+	if len(result.Errors) != 0 {
+		t.Fatalf("unexpected errors: %v", result.Errors)
+	}
+	if result.RecordsDeleted != 3 {
+		t.Errorf("expected 3 records deleted, got %d", result.RecordsDeleted)
+	}
+	if _, ok := fake.find("_minecraft._tcp.example.com", "SRV"); ok {
+		t.Error("expected SRV record to be removed")
+	}
+	if fake.has("example.com", "TXT", "verification=abc") || !fake.has("example.com", "TXT", "v=spf1 -all") {
+		t.Error("expected only the declared TXT record to be removed")
+	}
+	if _, ok := fake.find("mc.example.com", "CNAME"); ok {
+		t.Error("expected CNAME record to be removed")
+	}
+}
@@ -0,0 +1,104 @@
+package reconciler
+
+import (
+	"fmt"
+	"log/slog"
+	"net"
+	"sort"
+	"strings"
+
+	"github.com/maxfield-allison/technitium-companion/internal/technitium"
+	"github.com/maxfield-allison/technitium-companion/internal/traefik"
+)
+
+// ptrRecords returns one PTR record per address among the desired A and AAAA records,
+// placed in the most specific configured reverse zone. When several hostnames share an
+// address, primaryRecord picks the one the PTR points at. Wildcard hostnames cannot be
+// PTR targets, and addresses outside every reverse zone are logged and skipped.
+func (r *Reconciler) ptrRecords(desired []desiredRecord) []desiredRecord {
+	var addresses []string
+	candidates := make(map[string][]desiredRecord)
+	for _, d := range desired {
+		if (d.Type != "A" && d.Type != "AAAA") || traefik.IsWildcard(d.Name) {
+			continue
+		}
+		if _, ok := candidates[d.Value]; !ok {
+			addresses = append(addresses, d.Value)
+		}
+		candidates[d.Value] = append(candidates[d.Value], d)
+	}
+
+	var ptrs []desiredRecord
+	for _, ip := range addresses {
+		name, err := reverseName(ip)
+		if err != nil {
+			r.logger.Warn("cannot build PTR record, skipping",
+				slog.String("ip", ip),
+				slog.String("error", err.Error()),
+			)
+			continue
+		}
+
+		zone, ok := r.cfg.ReverseZoneFor(name)
+		if !ok {
+			r.logger.Warn("address matches no configured reverse zone, skipping PTR record",
+				slog.String("ip", ip),
+				slog.String("name", name),
+			)
+			continue
+		}
+
+		// The PTR inherits the TTL and provenance of its primary hostname
+		ptr := primaryRecord(candidates[ip])
+		ptr.Value = technitium.CanonicalName(ptr.Name)
+		ptr.Zone = zone
+		ptr.Name = name
+		ptr.Type = "PTR"
+		ptrs = append(ptrs, ptr)
+	}
+
+	return ptrs
+}
+
+// primaryRecord picks the hostname a shared address's PTR record points at: hostnames of
+// workloads labeled as PTR targets first, then the name with the fewest labels, then the
+// alphabetically first, so the choice is stable across runs and independent of workload order.
+func primaryRecord(records []desiredRecord) desiredRecord {
+	sorted := make([]desiredRecord, len(records))
+	copy(sorted, records)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		a, b := sorted[i], sorted[j]
+		if a.PreferPTR != b.PreferPTR {
+			return a.PreferPTR
+		}
+		if la, lb := strings.Count(a.Name, "."), strings.Count(b.Name, "."); la != lb {
+			return la < lb
+		}
+		return technitium.CanonicalName(a.Name) < technitium.CanonicalName(b.Name)
+	})
+	return sorted[0]
+}
+
+// reverseName returns the in-addr.arpa or ip6.arpa name of an IP address,
+// e.g. 1.0.0.10.in-addr.arpa for 10.0.0.1.
+func reverseName(ip string) (string, error) {
+	parsed := net.ParseIP(ip)
+	if parsed == nil {
+		return "", fmt.Errorf("invalid IP address %q", ip)
+	}
+
+	if v4 := parsed.To4(); v4 != nil {
+		return fmt.Sprintf("%d.%d.%d.%d.in-addr.arpa", v4[3], v4[2], v4[1], v4[0]), nil
+	}
+
+	const hexDigits = "0123456789abcdef"
+	var b strings.Builder
+	for i := len(parsed) - 1; i >= 0; i-- {
+		b.WriteByte(hexDigits[parsed[i]&0x0f])
+		b.WriteByte('.')
+		b.WriteByte(hexDigits[parsed[i]>>4])
+		b.WriteByte('.')
+	}
+	b.WriteString("ip6.arpa")
+	return b.String(), nil
+}
@@ -0,0 +1,170 @@
+package reconciler
+
+import (
+	"context"
+	"testing"
+
+	"github.com/maxfield-allison/technitium-companion/internal/config"
+	"github.com/maxfield-allison/technitium-companion/internal/docker"
+	"github.com/maxfield-allison/technitium-companion/internal/technitium"
+	"github.com/maxfield-allison/technitium-companion/internal/traefik"
+)
+
+func TestReverseName(t *testing.T) {
+	tests := []struct {
+		ip   string
+		want string
+	}{
+		{"10.0.0.1", "1.0.0.10.in-addr.arpa"},
+		{"192.168.1.20", "20.1.168.192.in-addr.arpa"},
+		{"2001:db8::1", "1.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.8.b.d.0.1.0.0.2.ip6.arpa"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.ip, func(t *testing.T) {
+			got, err := reverseName(tt.ip)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("reverseName(%q) = %q, want %q", tt.ip, got, tt.want)
+			}
+		})
+	}
+
+	if _, err := reverseName("not-an-ip"); err == nil {
+		t.Error("expected error for invalid address")
+	}
+}
+
+func TestPrimaryRecord(t *testing.T) {
+	tests := []struct {
+		name    string
+		records []desiredRecord
+		want    string
+	}{
+		{
+			name: "fewest labels wins",
+			records: []desiredRecord{
+				{Name: "app.lab.example.com"},
+				{Name: "web.example.com"},
+			},
+			want: "web.example.com",
+		},
+		{
+			name: "alphabetical among equal depth",
+			records: []desiredRecord{
+				{Name: "zeta.example.com"},
+				{Name: "Alpha.example.com"},
+			},
+			want: "Alpha.example.com",
+		},
+		{
+			name: "label preference beats depth",
+			records: []desiredRecord{
+				{Name: "web.example.com"},
+				{Name: "traefik.lab.example.com", PreferPTR: true},
+			},
+			want: "traefik.lab.example.com",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := primaryRecord(tt.records); got.Name != tt.want {
+				t.Errorf("primaryRecord = %s, want %s", got.Name, tt.want)
+			}
+		})
+	}
+}
+
+// TestReconcileWorkloads_PTRRecords verifies each target address gets a PTR record for
+// its primary hostname, an owned PTR for another hostname is repointed, and addresses
+// outside every reverse zone are skipped.
+func TestReconcileWorkloads_PTRRecords(t *testing.T) {
+	oldProvenance := technitium.Provenance{Owner: "test", Workload: "old"}
+	fake, client := newFakeTechnitium(t,
+		fakeRecord{Zone: "0.10.in-addr.arpa", Name: "2.0.0.10.in-addr.arpa", Type: "PTR", TTL: 300, Value: "old.example.com", Comments: oldProvenance.String()},
+	)
+
+	cfg := &config.Config{
+		TechnitiumZone: "example.com",
+		TargetIP:       "10.0.0.1",
+		TargetIPs:      []string{"10.0.0.1", "2001:db8::1"},
+		TTL:            300,
+		AllowWildcards: true,
+		PTRRecords:     true,
+		ReverseZones:   []string{"10.in-addr.arpa", "0.10.in-addr.arpa"},
+		OwnerID:        "test",
+	}
+	rec := New(cfg, nil, traefik.NewParser(), []*technitium.Client{client})
+
+	workloads := []docker.Workload{
+		{Name: "app", Labels: map[string]string{"traefik.http.routers.app.rule": "Host(`app.lab.example.com`) || Host(`*.example.com`)"}},
+		{Name: "web", Labels: map[string]string{"traefik.http.routers.web.rule": "Host(`web.example.com`)"}},
+		{Name: "api", Labels: map[string]string{
+			"traefik.http.routers.api.rule":  "Host(`api.example.com`)",
+			"technitium-companion.target-ip": "10.0.0.2",
+		}},
+	}
+	result := &ReconcileResult{}
+
+	rec.reconcileWorkloads(context.Background(), workloads, result)
+
+	if len(result.Errors) != 0 {
+		t.Fatalf("unexpected errors: %v", result.Errors)
+	}
+	if !fake.hasInZone("0.10.in-addr.arpa", "1.0.0.10.in-addr.arpa", "PTR", "web.example.com") {
+		t.Error("expected PTR for 10.0.0.1 to point at web.example.com in the most specific reverse zone")
+	}
+	if !fake.hasInZone("0.10.in-addr.arpa", "2.0.0.10.in-addr.arpa", "PTR", "api.example.com") {
+		t.Error("expected PTR for 10.0.0.2 to be repointed at api.example.com")
+	}
+	if fake.has("2.0.0.10.in-addr.arpa", "PTR", "old.example.com") {
+		t.Error("expected stale PTR target to be replaced")
+	}
+	// Seven address records and one PTR; the IPv6 target lies outside every reverse zone
+	if fake.calls["/api/zones/records/add"] != 8 {
+		t.Errorf("expected 7 address records and 1 PTR added, got %d adds", fake.calls["/api/zones/records/add"])
+	}
+}
+
+// TestReconcileWorkloads_PTROrphanCleanup verifies owned PTR records are claimed when
+// created and removed with their forward records once no workload uses the address.
+func TestReconcileWorkloads_PTROrphanCleanup(t *testing.T) {
+	fake, client := newFakeTechnitium(t,
+		fakeRecord{Zone: "10.in-addr.arpa", Name: "9.0.0.10.in-addr.arpa", Type: "PTR", TTL: 300, Value: "gone.example.com"},
+		fakeRecord{Zone: "10.in-addr.arpa", Name: ownershipName("9.0.0.10.in-addr.arpa"), Type: "TXT", TTL: 300, Value: ownershipValue("test")},
+	)
+
+	cfg := &config.Config{
+		TechnitiumZone: "example.com",
+		TargetIP:       "10.0.0.1",
+		TTL:            300,
+		OrphanCleanup:  true,
+		OwnerID:        "test",
+		PTRRecords:     true,
+		ReverseZones:   []string{"10.in-addr.arpa"},
+	}
+	rec := New(cfg, nil, traefik.NewParser(), []*technitium.Client{client})
+
+	workloads := []docker.Workload{
+		{Name: "app", Labels: map[string]string{"traefik.http.routers.app.rule": "Host(`app.example.com`)"}},
+	}
+	result := &ReconcileResult{}
+
+	rec.reconcileWorkloads(context.Background(), workloads, result)
+
+	if len(result.Errors) != 0 {
+		t.Fatalf("unexpected errors: %v", result.Errors)
+	}
+	if !fake.hasInZone("10.in-addr.arpa", ownershipName("1.0.0.10.in-addr.arpa"), "TXT", ownershipValue("test")) {
+		t.Error("expected the new PTR record to be claimed")
+	}
+	if fake.has("9.0.0.10.in-addr.arpa", "PTR", "gone.example.com") {
+		t.Error("expected orphaned PTR record to be deleted")
+	}
+	if fake.has(ownershipName("9.0.0.10.in-addr.arpa"), "TXT", ownershipValue("test")) {
+		t.Error("expected ownership record of the orphaned PTR to be deleted")
+	}
+}
@@ -3,13 +3,18 @@ package reconciler
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
+	"net"
+	"slices"
+	"strings"
 	"sync"
 	"time"
 
 	"github.com/maxfield-allison/technitium-companion/internal/config"
 	"github.com/maxfield-allison/technitium-companion/internal/docker"
+	"github.com/maxfield-allison/technitium-companion/internal/labels"
 	"github.com/maxfield-allison/technitium-companion/internal/metrics"
 	"github.com/maxfield-allison/technitium-companion/internal/technitium"
 	"github.com/maxfield-allison/technitium-companion/internal/traefik"
@@ -23,25 +28,129 @@ type ReconcileResult struct {
 	HostnamesFound int
 	// HostnamesFiltered is the number of hostnames that matched include/exclude filters.
 	HostnamesFiltered int
-	// RecordsCreated is the number of new DNS A records created.
+	// HostnamesUnmatched lists filtered hostnames that belong to no configured zone and were skipped.
+	HostnamesUnmatched []string
+	// HostnamesConflicted lists hostnames skipped because records the companion does not own
+	// occupy the name and cannot coexist with the desired record (e.g. an A record where a CNAME is wanted).
+	HostnamesConflicted []string
+	// RecordsCreated is the number of new DNS address records created.
 	RecordsCreated int
-	// RecordsExisted is the number of DNS A records that already existed.
+	// RecordsUpdated is the number of existing DNS records repointed or given a new TTL to match the desired state.
+	RecordsUpdated int
+	// RecordsExisted is the number of DNS address records that already existed.
 	RecordsExisted int
+	// RecordsDeleted is the number of orphaned, stale or conflicting DNS records removed.
+	RecordsDeleted int
+	// OwnedRecords lists the records in the reconciled zones whose comments show this
+	// instance wrote them, as found before any changes were applied.
+	OwnedRecords []OwnedRecord
 	// Errors contains any errors encountered during reconciliation.
 	Errors []error
+	// Servers holds the record counts and errors of each Technitium server. The
+	// record counts above are their sums.
+	Servers []ServerResult
+	// Hosts holds the workload and hostname counts of each Docker host.
+	Hosts []HostResult
+	// Partial is set when a Docker host was left out of the run because it has never
+	// been listed or its target IPs never derived. Stale and orphaned records are kept,
+	// since they may belong to that host.
+	Partial bool
 	// Duration is how long the reconciliation took.
 	Duration time.Duration
 }
 
-// Reconciler scans Docker workloads and ensures DNS records exist.
+// ServerResult contains the outcome of a reconciliation run on one Technitium server.
+type ServerResult struct {
+	Server         string
+	RecordsCreated int
+	RecordsUpdated int
+	RecordsExisted int
+	RecordsDeleted int
+	Errors         []error
+}
+
+// HostResult contains the workloads found on one Docker host in a reconciliation run.
+type HostResult struct {
+	Host             string
+	WorkloadsScanned int
+	HostnamesFound   int
+	// TargetIPs are the addresses derived for the host in node target IP mode.
+	TargetIPs []string
+	// Err is set when the host could not be listed; the workloads it had when it was
+	// last listed were reconciled instead, or none if it was never listed.
+	Err error
+}
+
+// host returns the result of the named Docker host, or nil if it was not listed.
+func (r *ReconcileResult) host(name string) *HostResult {
+	for i := range r.Hosts {
+		if r.Hosts[i].Host == name {
+			return &r.Hosts[i]
+		}
+	}
+	return nil
+}
+
+// addServer folds the result of reconciling one server into r, prefixing its
+// errors with the server name.
+func (r *ReconcileResult) addServer(server string, sub *ReconcileResult) {
+	r.RecordsCreated += sub.RecordsCreated
+	r.RecordsUpdated += sub.RecordsUpdated
+	r.RecordsExisted += sub.RecordsExisted
+	r.RecordsDeleted += sub.RecordsDeleted
+	r.OwnedRecords = append(r.OwnedRecords, sub.OwnedRecords...)
+
+	// Every server sees the same conflicts as long as they hold the same records
+	for _, hostname := range sub.HostnamesConflicted {
+		if !slices.Contains(r.HostnamesConflicted, hostname) {
+			r.HostnamesConflicted = append(r.HostnamesConflicted, hostname)
+		}
+	}
+
+	for _, err := range sub.Errors {
+		r.Errors = append(r.Errors, fmt.Errorf("server %s: %w", server, err))
+	}
+
+	r.Servers = append(r.Servers, ServerResult{
+		Server:         server,
+		RecordsCreated: sub.RecordsCreated,
+		RecordsUpdated: sub.RecordsUpdated,
+		RecordsExisted: sub.RecordsExisted,
+		RecordsDeleted: sub.RecordsDeleted,
+		Errors:         sub.Errors,
+	})
+}
+
+// OwnedRecord is a record whose provenance names the workload it was written for.
+type OwnedRecord struct {
+	Server     string
+	Zone       string
+	Name       string
+	Type       string
+	Value      string
+	Provenance technitium.Provenance
+}
+
+// Reconciler scans the workloads of every Docker host and ensures DNS records exist
+// on every Technitium server.
 type Reconciler struct {
-	cfg        *config.Config
-	docker     *docker.Client
-	parser     *traefik.Parser
-	technitium *technitium.Client
-	logger     *slog.Logger
+	cfg     *config.Config
+	hosts   []*docker.Client
+	parser  *traefik.Parser
+	servers []*technitium.Client
+	logger  *slog.Logger
 
 	mu sync.Mutex
+	// lastWorkloads holds the workloads of each Docker host as last listed, standing
+	// in for a host that cannot be listed
+	lastWorkloads map[string][]docker.Workload
+	// nodeTargets holds the target IPs last derived for each Docker host in node
+	// target IP mode
+	nodeTargets map[string][]string
+	// networkTargets holds the addresses of workloads on their Docker network as
+	// resolved in this reconciliation, lastNetworkTargets those of the previous one
+	networkTargets     map[string][]string
+	lastNetworkTargets map[string][]string
 }
 
 // Option is a functional option for configuring the Reconciler.
@@ -54,20 +163,25 @@ func WithLogger(logger *slog.Logger) Option {
 	}
 }
 
-// New creates a new Reconciler.
+// New creates a new Reconciler that publishes the workloads of every host in
+// dockerClients and writes records to each of techClients. The servers are
+// reconciled independently, so one being down does not hold back the others.
 func New(
 	cfg *config.Config,
-	dockerClient *docker.Client,
+	dockerClients []*docker.Client,
 	parser *traefik.Parser,
-	techClient *technitium.Client,
+	techClients []*technitium.Client,
 	opts ...Option,
 ) *Reconciler {
 	r := &Reconciler{
-		cfg:        cfg,
-		docker:     dockerClient,
-		parser:     parser,
-		technitium: techClient,
-		logger:     slog.Default(),
+		cfg:            cfg,
+		hosts:          dockerClients,
+		parser:         parser,
+		servers:        techClients,
+		logger:         slog.Default(),
+		lastWorkloads:  make(map[string][]docker.Workload),
+		nodeTargets:    make(map[string][]string),
+		networkTargets: make(map[string][]string),
 	}
 
 	for _, opt := range opts {
@@ -78,6 +192,7 @@ func New(
 }
 
 // Reconcile scans all Docker workloads and ensures DNS records exist for Traefik-labeled services.
+// The zone is fetched once and only the differences from the desired state are applied.
 // It returns a result containing statistics about the reconciliation run.
 func (r *Reconciler) Reconcile(ctx context.Context) (*ReconcileResult, error) {
 	r.mu.Lock()
@@ -87,32 +202,20 @@ func (r *Reconciler) Reconcile(ctx context.Context) (*ReconcileResult, error) {
 	result := &ReconcileResult{}
 
 	r.logger.Info("starting reconciliation",
-		slog.String("mode", string(r.docker.Mode())),
+		slog.Int("docker_hosts", len(r.hosts)),
 		slog.Bool("dry_run", r.cfg.DryRun),
 	)
 
 	// List all workloads (services in Swarm, containers in standalone)
-	workloads, err := r.docker.ListWorkloads(ctx)
+	workloads, err := r.listWorkloads(ctx, result)
 	if err != nil {
-		return nil, fmt.Errorf("listing workloads: %w", err)
+		return nil, err
 	}
+	workloads = r.resolveTargets(ctx, workloads, result)
 
 	result.WorkloadsScanned = len(workloads)
-	r.logger.Debug("scanned workloads",
-		slog.Int("count", len(workloads)),
-	)
 
-	// Process each workload
-	for _, workload := range workloads {
-		if err := r.processWorkload(ctx, workload, result); err != nil {
-			r.logger.Error("failed to process workload",
-				slog.String("name", workload.Name),
-				slog.String("type", workload.Type),
-				slog.String("error", err.Error()),
-			)
-			result.Errors = append(result.Errors, fmt.Errorf("workload %s: %w", workload.Name, err))
-		}
-	}
+	r.reconcileWorkloads(ctx, workloads, result)
 
 	result.Duration = time.Since(start)
 
@@ -127,208 +230,885 @@ func (r *Reconciler) Reconcile(ctx context.Context) (*ReconcileResult, error) {
 		slog.Int("workloads_scanned", result.WorkloadsScanned),
 		slog.Int("hostnames_found", result.HostnamesFound),
 		slog.Int("hostnames_filtered", result.HostnamesFiltered),
+		slog.Int("hostnames_unmatched", len(result.HostnamesUnmatched)),
+		slog.Int("hostnames_conflicted", len(result.HostnamesConflicted)),
 		slog.Int("records_created", result.RecordsCreated),
+		slog.Int("records_updated", result.RecordsUpdated),
 		slog.Int("records_existed", result.RecordsExisted),
+		slog.Int("records_deleted", result.RecordsDeleted),
+		slog.Int("records_owned", len(result.OwnedRecords)),
 		slog.Int("errors", len(result.Errors)),
+		slog.Bool("partial", result.Partial),
 		slog.Duration("duration", result.Duration),
 	)
 
+	for _, s := range result.Servers {
+		if len(s.Errors) > 0 {
+			r.logger.Warn("technitium server not fully reconciled",
+				slog.String("server", s.Server),
+				slog.Int("errors", len(s.Errors)),
+			)
+		}
+	}
+
+	for _, h := range result.Hosts {
+		r.logger.Debug("reconciled docker host",
+			slog.String("docker_host", h.Host),
+			slog.Int("workloads_scanned", h.WorkloadsScanned),
+			slog.Int("hostnames_found", h.HostnamesFound),
+			slog.Any("target_ips", h.TargetIPs),
+			slog.Bool("stale", h.Err != nil),
+		)
+	}
+
 	return result, nil
 }
 
-// processWorkload extracts hostnames from a workload's Traefik labels and ensures DNS records exist.
-func (r *Reconciler) processWorkload(ctx context.Context, workload docker.Workload, result *ReconcileResult) error {
-	// Extract hostnames from Traefik labels
-	hosts := r.parser.ExtractHosts(workload.Labels)
-	if len(hosts) == 0 {
-		r.logger.Debug("no traefik hosts found",
+// listWorkloads lists the workloads of every Docker host. A host that cannot be listed
+// contributes the workloads it had when it was last listed, so its records are neither
+// removed as orphans nor pruned while it is unreachable. A host that has never been
+// listed is skipped and makes the run partial for the same reason; only when no host
+// can be listed at all does the run fail.
+func (r *Reconciler) listWorkloads(ctx context.Context, result *ReconcileResult) ([]docker.Workload, error) {
+	var workloads []docker.Workload
+	unlisted := 0
+	for _, host := range r.hosts {
+		listed, err := host.ListWorkloads(ctx)
+		if err != nil {
+			last, ok := r.lastWorkloads[host.Name()]
+			if !ok {
+				r.logger.Error("failed to list workloads of docker host that was never listed, skipping it",
+					slog.String("docker_host", host.Name()),
+					slog.String("error", err.Error()),
+				)
+				metrics.RecordDockerHost(host.Name(), false, 0)
+				result.Errors = append(result.Errors, fmt.Errorf("docker host %s: %w", host.Name(), err))
+				result.Hosts = append(result.Hosts, HostResult{Host: host.Name(), Err: err})
+				result.Partial = true
+				unlisted++
+				continue
+			}
+
+			r.logger.Error("failed to list workloads, keeping the last known workloads of docker host",
+				slog.String("docker_host", host.Name()),
+				slog.Int("workloads", len(last)),
+				slog.String("error", err.Error()),
+			)
+			result.Errors = append(result.Errors, fmt.Errorf("docker host %s: %w", host.Name(), err))
+			listed = last
+		} else {
+			r.lastWorkloads[host.Name()] = listed
+		}
+
+		metrics.RecordDockerHost(host.Name(), err == nil, len(listed))
+		result.Hosts = append(result.Hosts, HostResult{
+			Host:             host.Name(),
+			WorkloadsScanned: len(listed),
+			Err:              err,
+		})
+		workloads = append(workloads, listed...)
+
+		r.logger.Debug("scanned workloads",
+			slog.String("docker_host", host.Name()),
+			slog.String("mode", string(host.Mode())),
+			slog.Int("count", len(listed)),
+		)
+	}
+
+	if unlisted == len(r.hosts) {
+		return nil, fmt.Errorf("no docker host could be listed: %w", errors.Join(result.Errors...))
+	}
+	return workloads, nil
+}
+
+// reconcileWorkloads builds the desired record set from all workloads, fetches each zone once,
+// and applies only the differences between the two.
+func (r *Reconciler) reconcileWorkloads(ctx context.Context, workloads []docker.Workload, result *ReconcileResult) {
+	// Network addresses of workloads that are gone are dropped
+	r.lastNetworkTargets, r.networkTargets = r.networkTargets, make(map[string][]string)
+
+	var desired []desiredRecord
+	for _, workload := range workloads {
+		found := result.HostnamesFound
+		desired = append(desired, r.processWorkload(ctx, workload, result)...)
+		if h := result.host(workload.Host); h != nil {
+			h.HostnamesFound += result.HostnamesFound - found
+		}
+	}
+
+	r.reconcileDesired(ctx, desired, result.Partial, result)
+}
+
+// reconcileDesired plans and applies desired records zone by zone on every server. When
+// partial is set the desired records do not describe the whole zone, so only zones they
+// touch are fetched and orphan cleanup is skipped. Servers are reconciled concurrently, and
+// a failure in one zone or on one server does not block the others.
+//
+// PTR records are derived from the desired address records in full runs only: a partial
+// set of hostnames could pick a different primary hostname for a shared address.
+func (r *Reconciler) reconcileDesired(ctx context.Context, desired []desiredRecord, partial bool, result *ReconcileResult) {
+	configured := r.cfg.Zones()
+	if r.cfg.PTRRecords && !partial {
+		desired = append(desired, r.ptrRecords(desired)...)
+		configured = append(configured[:len(configured):len(configured)], r.cfg.ReverseZones...)
+	}
+
+	byZone := make(map[string][]desiredRecord)
+	for _, d := range desired {
+		byZone[d.Zone] = append(byZone[d.Zone], d)
+	}
+
+	// Configured zones are always fetched so orphans can be found in them; zones only
+	// reached through a workload's zone label are fetched while they have desired records.
+	var zones []string
+	seen := make(map[string]struct{})
+	for _, zone := range configured {
+		if _, ok := byZone[zone]; partial && !ok {
+			continue
+		}
+		seen[zone] = struct{}{}
+		zones = append(zones, zone)
+	}
+	for _, d := range desired {
+		if _, ok := seen[d.Zone]; !ok {
+			seen[d.Zone] = struct{}{}
+			zones = append(zones, d.Zone)
+		}
+	}
+
+	results := make([]*ReconcileResult, len(r.servers))
+	var wg sync.WaitGroup
+	for i, server := range r.servers {
+		results[i] = &ReconcileResult{}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			r.reconcileServer(ctx, server, zones, byZone, partial, results[i])
+		}()
+	}
+	wg.Wait()
+
+	for i, server := range r.servers {
+		result.addServer(server.Name(), results[i])
+	}
+}
+
+// reconcileServer plans and applies the desired records of each zone on one server. A
+// zone that does not exist on the server is skipped; the run on the server is abandoned
+// once it rejects the credentials or its circuit breaker opens, since every further
+// request would fail the same way.
+func (r *Reconciler) reconcileServer(ctx context.Context, server *technitium.Client, zones []string, byZone map[string][]desiredRecord, partial bool, result *ReconcileResult) {
+	for _, zone := range zones {
+		existing, err := server.ListZoneRecords(ctx, zone)
+		if err != nil {
+			if abortsServer(err) {
+				r.abortServer(server, err, result)
+				return
+			}
+			msg := "failed to fetch zone records"
+			if errors.Is(err, technitium.ErrZoneNotFound) {
+				msg = "zone does not exist on technitium server, skipping"
+			}
+			r.logger.Error(msg,
+				slog.String("server", server.Name()),
+				slog.String("zone", zone),
+				slog.String("error", err.Error()),
+			)
+			result.Errors = append(result.Errors, fmt.Errorf("zone %s: %w", zone, err))
+			continue
+		}
+
+		owned := ownedRecords(zone, existing, r.cfg.OwnerID)
+		for i := range owned {
+			owned[i].Server = server.Name()
+		}
+		result.OwnedRecords = append(result.OwnedRecords, owned...)
+
+		p := computePlan(zone, byZone[zone], existing, planOptions{
+			OrphanCleanup: r.cfg.OrphanCleanup,
+			OwnerID:       r.cfg.OwnerID,
+			Partial:       partial,
+			ProvenanceTXT: r.cfg.ProvenanceTXT,
+			Now:           time.Now(),
+		})
+
+		r.logger.Debug("computed reconciliation plan",
+			slog.String("server", server.Name()),
+			slog.String("zone", zone),
+			slog.Int("creates", len(p.Creates)),
+			slog.Int("updates", len(p.Updates)),
+			slog.Int("deletes", len(p.Deletes)),
+			slog.Int("stale", len(p.Stale)),
+			slog.Int("displaced", len(p.Displaced)),
+			slog.Int("conflicts", len(p.Conflicts)),
+			slog.Int("unchanged", len(p.Unchanged)),
+		)
+
+		if err := r.applyPlan(ctx, server, zone, p, result); err != nil {
+			if abortsServer(err) {
+				r.abortServer(server, err, result)
+				return
+			}
+			r.logger.Error("zone disappeared during reconciliation, skipping",
+				slog.String("server", server.Name()),
+				slog.String("zone", zone),
+				slog.String("error", err.Error()),
+			)
+			result.Errors = append(result.Errors, fmt.Errorf("zone %s: %w", zone, err))
+		}
+	}
+}
+
+// abortsServer reports whether a failure makes further requests to a server pointless
+// for the rest of the run.
+func abortsServer(err error) bool {
+	return errors.Is(err, technitium.ErrUnauthorized) || errors.Is(err, technitium.ErrCircuitOpen)
+}
+
+// abortServer logs and collects the failure that ended the run on a server.
+func (r *Reconciler) abortServer(server *technitium.Client, err error, result *ReconcileResult) {
+	r.logger.Error("aborting reconciliation on technitium server",
+		slog.String("server", server.Name()),
+		slog.String("error", err.Error()),
+	)
+	result.Errors = append(result.Errors, fmt.Errorf("aborted: %w", err))
+}
+
+// processWorkload extracts hostnames from a workload's Traefik labels and returns the
+// records that should exist for them, followed by the records the workload declares
+// through labels. A workload Traefik does not expose contributes only the hostnames and
+// records it declares itself.
+func (r *Reconciler) processWorkload(ctx context.Context, workload docker.Workload, result *ReconcileResult) []desiredRecord {
+	logger := r.workloadLogger(workload)
+	overrides, errs := labels.ParseOverrides(workload.Labels)
+	for _, err := range errs {
+		logger.Warn("ignoring invalid workload label",
+			slog.String("workload", workload.Name),
+			slog.String("error", err.Error()),
+		)
+		result.Errors = append(result.Errors, fmt.Errorf("workload %s: %w", workload.Name, err))
+	}
+
+	if overrides.Disabled {
+		logger.Debug("workload opted out of DNS management",
 			slog.String("workload", workload.Name),
 		)
 		return nil
 	}
 
+	// Routers of workloads Traefik's Docker provider filters out are not served
+	exposed, err := r.parser.Exposed(workload.Labels)
+	if err != nil {
+		logger.Warn("ignoring workload traefik does not serve",
+			slog.String("workload", workload.Name),
+			slog.String("error", err.Error()),
+		)
+		result.Errors = append(result.Errors, fmt.Errorf("workload %s: %w", workload.Name, err))
+	}
+	var parsed traefik.Result
+	if exposed {
+		// Extract hostnames from Traefik labels
+		parsed = r.parser.Parse(workload.Labels)
+		for _, d := range parsed.Diagnostics {
+			logger.Warn("traefik rule not fully published",
+				slog.String("workload", workload.Name),
+				slog.String("router", d.Router),
+				slog.String("reason", d.Message),
+			)
+		}
+	} else {
+		logger.Debug("workload not exposed by traefik",
+			slog.String("workload", workload.Name),
+		)
+	}
+
+	// The first router serving a hostname is recorded in its provenance
+	routers := make(map[string]string)
+	for _, route := range parsed.Routes {
+		if _, ok := routers[route.Hostname]; !ok {
+			routers[route.Hostname] = route.Router
+		}
+	}
+
+	declared := r.declaredRecords(workload, overrides, result)
+
+	hosts := parsed.Hosts
+	for _, host := range overrides.Hosts {
+		if !slices.Contains(hosts, host) {
+			hosts = append(hosts, host)
+		}
+	}
+	if len(hosts) == 0 {
+		logger.Debug("no hostnames found",
+			slog.String("workload", workload.Name),
+		)
+		return declared
+	}
+
 	result.HostnamesFound += len(hosts)
 
-	r.logger.Debug("found traefik hosts",
+	logger.Debug("found hostnames",
 		slog.String("workload", workload.Name),
 		slog.Any("hosts", hosts),
 	)
 
-	// Process each hostname
+	// A workload on a macvlan or ipvlan network is published at its own address there
+	if network := r.networkFor(overrides); network != "" && len(overrides.TargetIPs) == 0 {
+		ips, err := r.networkAddresses(ctx, workload, network)
+		if err != nil {
+			logger.Warn("failed to resolve workload address on docker network",
+				slog.String("workload", workload.Name),
+				slog.String("network", network),
+				slog.Any("kept_addresses", ips),
+				slog.String("error", err.Error()),
+			)
+			result.Errors = append(result.Errors, fmt.Errorf("workload %s: %w", workload.Name, err))
+		}
+		if len(ips) == 0 {
+			return declared
+		}
+		overrides.TargetIPs = ips
+	}
+
+	var desired []desiredRecord
 	for _, host := range hosts {
-		if err := r.ensureRecord(ctx, workload.Name, host, result); err != nil {
-			return fmt.Errorf("ensuring record for %s: %w", host, err)
+		for _, d := range r.desiredForHost(workload, overrides, host, result) {
+			d.WorkloadType = workload.Type
+			d.Stack = workload.Stack()
+			d.Router = routers[host]
+			d.Host = provenanceHost(workload)
+			desired = append(desired, d)
 		}
 	}
 
-	return nil
+	return append(desired, declared...)
+}
+
+// workloadLogger returns the logger for messages about a workload, naming the Docker
+// host it was listed on.
+func (r *Reconciler) workloadLogger(workload docker.Workload) *slog.Logger {
+	if workload.Host == "" {
+		return r.logger
+	}
+	return r.logger.With(slog.String("docker_host", workload.Host))
 }
 
-// ensureRecord ensures a DNS A record exists for a hostname.
-func (r *Reconciler) ensureRecord(ctx context.Context, workloadName, hostname string, result *ReconcileResult) error {
+// provenanceHost returns the Docker host recorded in the provenance of a workload's
+// records. The default host of a single-host setup is left out.
+func provenanceHost(workload docker.Workload) string {
+	if workload.Host == config.DefaultDockerHostName {
+		return ""
+	}
+	return workload.Host
+}
+
+// declaredRecords returns the SRV, TXT and CNAME records a workload declares with
+// technitium-companion.records labels. They are taken as written: the include/exclude
+// filters do not apply to them. Records outside their zone are reported in the result
+// and skipped.
+func (r *Reconciler) declaredRecords(workload docker.Workload, overrides labels.Overrides, result *ReconcileResult) []desiredRecord {
+	logger := r.workloadLogger(workload)
+	records, errs := labels.ParseRecords(workload.Labels)
+	for _, err := range errs {
+		logger.Warn("ignoring invalid record label",
+			slog.String("workload", workload.Name),
+			slog.String("error", err.Error()),
+		)
+		result.Errors = append(result.Errors, fmt.Errorf("workload %s: %w", workload.Name, err))
+	}
+
+	var desired []desiredRecord
+	for _, rec := range records {
+		zone, ok := r.cfg.ZoneFor(rec.Name)
+		if overrides.Zone != "" {
+			zone, ok = overrides.Zone, inZone(rec.Name, overrides.Zone)
+		}
+		if !ok {
+			logger.Warn("declared record matches no configured zone, skipping",
+				slog.String("name", rec.Name),
+				slog.String("type", rec.Type),
+				slog.String("zone_override", overrides.Zone),
+				slog.String("workload", workload.Name),
+			)
+			result.HostnamesUnmatched = append(result.HostnamesUnmatched, rec.Name)
+			continue
+		}
+
+		if rec.Type == "CNAME" && rec.Name == zone {
+			logger.Warn("cannot create a CNAME at the zone apex, skipping",
+				slog.String("hostname", rec.Name),
+				slog.String("zone", zone),
+				slog.String("workload", workload.Name),
+			)
+			result.Errors = append(result.Errors, fmt.Errorf("hostname %s: CNAME not allowed at zone apex", rec.Name))
+			continue
+		}
+
+		ttl := r.cfg.TTL
+		if overrides.TTL != 0 {
+			ttl = overrides.TTL
+		}
+		if rec.TTL != 0 {
+			ttl = rec.TTL
+		}
+
+		desired = append(desired, desiredRecord{
+			Zone:         zone,
+			Name:         rec.Name,
+			Type:         rec.Type,
+			Value:        rec.Value,
+			TTL:          ttl,
+			Workload:     workload.Name,
+			WorkloadType: workload.Type,
+			Stack:        workload.Stack(),
+			Host:         provenanceHost(workload),
+		})
+	}
+
+	return desired
+}
+
+// desiredForHost returns the records a hostname should have, applying the workload's
+// label overrides on top of the global configuration. Hostnames rejected by the
+// include/exclude filters are skipped silently; wildcards, unless allowed, are
+// logged and skipped; hostnames outside their zone are reported in the result
+// and skipped.
+func (r *Reconciler) desiredForHost(workload docker.Workload, overrides labels.Overrides, hostname string, result *ReconcileResult) []desiredRecord {
+	logger := r.workloadLogger(workload)
 	// Apply include/exclude filters
 	if !r.cfg.MatchesFilters(hostname) {
-		r.logger.Debug("hostname filtered out",
+		logger.Debug("hostname filtered out",
 			slog.String("hostname", hostname),
-			slog.String("workload", workloadName),
+			slog.String("workload", workload.Name),
 		)
 		return nil
 	}
 
 	result.HostnamesFiltered++
 
-	// Dry run mode - log what would be created
-	if r.cfg.DryRun {
-		r.logger.Info("DRY RUN: would ensure A record",
+	if traefik.IsWildcard(hostname) && !r.cfg.AllowWildcards {
+		logger.Warn("wildcard hostname requires ALLOW_WILDCARDS, skipping",
 			slog.String("hostname", hostname),
-			slog.String("zone", r.cfg.TechnitiumZone),
-			slog.String("ip", r.cfg.TargetIP),
-			slog.Int("ttl", r.cfg.TTL),
-			slog.String("workload", workloadName),
+			slog.String("workload", workload.Name),
 		)
-		result.RecordsCreated++ // Count as would-be-created for reporting
 		return nil
 	}
 
-	// Ensure the A record exists
-	created, err := r.technitium.EnsureARecord(
-		ctx,
-		r.cfg.TechnitiumZone,
-		hostname,
-		r.cfg.TargetIP,
-		r.cfg.TTL,
-	)
-	if err != nil {
-		return fmt.Errorf("creating A record: %w", err)
+	zone, ok := r.cfg.ZoneFor(hostname)
+	if overrides.Zone != "" {
+		zone, ok = overrides.Zone, inZone(hostname, overrides.Zone)
+	}
+	if !ok {
+		logger.Warn("hostname matches no configured zone, skipping",
+			slog.String("hostname", hostname),
+			slog.String("zone_override", overrides.Zone),
+			slog.String("workload", workload.Name),
+		)
+		result.HostnamesUnmatched = append(result.HostnamesUnmatched, hostname)
+		return nil
+	}
+
+	ttl := r.cfg.TTL
+	if overrides.TTL != 0 {
+		ttl = overrides.TTL
 	}
 
-	if created {
-		result.RecordsCreated++
-		metrics.RecordDNSRecordCreated(r.cfg.TechnitiumZone)
-		r.logger.Info("created A record",
+	// An explicit target IP label always produces address records, even in cname mode
+	targets := overrides.TargetIPs
+	if len(targets) == 0 {
+		if r.cfg.RecordMode == config.RecordModeCNAME {
+			return r.cnameRecords(zone, hostname, ttl, workload.Name, result)
+		}
+		targets = r.targetsFor(workload.Host)
+	}
+
+	desired := addressRecords(zone, hostname, targets, ttl, workload.Name)
+	for i := range desired {
+		desired[i].PreferPTR = overrides.PreferPTR
+	}
+	return desired
+}
+
+// addressRecords returns one A or AAAA record per target address, so both
+// families are managed together.
+func addressRecords(zone, hostname string, targetIPs []string, ttl int, workload string) []desiredRecord {
+	desired := make([]desiredRecord, 0, len(targetIPs))
+	for _, ip := range targetIPs {
+		desired = append(desired, desiredRecord{
+			Zone:     zone,
+			Name:     hostname,
+			Type:     addressRecordType(ip),
+			Value:    technitium.CanonicalIP(ip),
+			TTL:      ttl,
+			Workload: workload,
+		})
+	}
+	return desired
+}
+
+// cnameRecords returns the CNAME record pointing hostname at the canonical ingress name.
+// The zone apex and the canonical name itself cannot be CNAMEs and are skipped.
+func (r *Reconciler) cnameRecords(zone, hostname string, ttl int, workload string, result *ReconcileResult) []desiredRecord {
+	name := technitium.CanonicalName(hostname)
+
+	if name == zone {
+		r.logger.Warn("cannot create a CNAME at the zone apex, skipping",
 			slog.String("hostname", hostname),
-			slog.String("zone", r.cfg.TechnitiumZone),
-			slog.String("ip", r.cfg.TargetIP),
-			slog.String("workload", workloadName),
+			slog.String("zone", zone),
+			slog.String("workload", workload),
 		)
-	} else {
-		result.RecordsExisted++
-		metrics.RecordDNSRecordExisted(r.cfg.TechnitiumZone)
-		r.logger.Debug("A record already exists",
+		result.Errors = append(result.Errors, fmt.Errorf("hostname %s: CNAME not allowed at zone apex", hostname))
+		return nil
+	}
+
+	if name == r.cfg.CNAMETarget {
+		r.logger.Debug("hostname is the CNAME target itself, skipping",
 			slog.String("hostname", hostname),
-			slog.String("ip", r.cfg.TargetIP),
+			slog.String("workload", workload),
 		)
+		return nil
 	}
 
-	return nil
+	return []desiredRecord{{
+		Zone:     zone,
+		Name:     hostname,
+		Type:     "CNAME",
+		Value:    r.cfg.CNAMETarget,
+		TTL:      ttl,
+		Workload: workload,
+	}}
 }
 
-// ReconcileHostnames ensures DNS records exist for a specific set of hostnames.
-// This is useful for event-driven reconciliation when a new service is created.
-func (r *Reconciler) ReconcileHostnames(ctx context.Context, workloadName string, hostnames []string) (*ReconcileResult, error) {
-	r.mu.Lock()
-	defer r.mu.Unlock()
-
-	start := time.Now()
-	result := &ReconcileResult{
-		HostnamesFound: len(hostnames),
+// addressRecordType returns "AAAA" for IPv6 addresses and "A" otherwise.
+func addressRecordType(ip string) string {
+	if parsed := net.ParseIP(ip); parsed != nil && parsed.To4() == nil {
+		return "AAAA"
 	}
+	return "A"
+}
 
-	r.logger.Debug("reconciling specific hostnames",
-		slog.String("workload", workloadName),
-		slog.Any("hostnames", hostnames),
-	)
+// inZone reports whether hostname is the zone apex or a name inside zone.
+func inZone(hostname, zone string) bool {
+	hostname = strings.TrimSuffix(strings.ToLower(hostname), ".")
+	return hostname == zone || strings.HasSuffix(hostname, "."+zone)
+}
 
-	for _, hostname := range hostnames {
-		if err := r.ensureRecord(ctx, workloadName, hostname, result); err != nil {
-			r.logger.Error("failed to ensure record",
-				slog.String("hostname", hostname),
-				slog.String("error", err.Error()),
-			)
-			result.Errors = append(result.Errors, fmt.Errorf("hostname %s: %w", hostname, err))
+// applyPlan executes a plan against a Technitium server, or logs it in dry run mode.
+// Failures are collected per record so one bad hostname does not block the rest. It
+// stops and returns the error when a change fails in a way that dooms the rest of the
+// plan: the zone no longer exists, or the server cannot be used at all.
+func (r *Reconciler) applyPlan(ctx context.Context, server *technitium.Client, zone string, p plan, result *ReconcileResult) error {
+	if !r.cfg.DryRun {
+		for _, c := range p.Unchanged {
+			metrics.RecordDNSRecordExisted(server.Name(), zone, c.Type)
 		}
 	}
+	result.RecordsExisted += len(p.Unchanged)
 
-	result.Duration = time.Since(start)
-	return result, nil
-}
+	for _, c := range p.Conflicts {
+		r.logger.Warn("hostname has conflicting records not owned by this instance, skipping",
+			slog.String("server", server.Name()),
+			slog.String("hostname", c.Name),
+			slog.String("zone", zone),
+			slog.String("type", c.Type),
+			slog.String("workload", c.Workload),
+		)
+		result.HostnamesConflicted = append(result.HostnamesConflicted, c.Name)
+	}
 
-// DeleteHostnames removes DNS records for a specific set of hostnames.
-// This is useful when a service is removed (if orphan cleanup is enabled).
-func (r *Reconciler) DeleteHostnames(ctx context.Context, workloadName string, hostnames []string) (int, error) {
-	r.mu.Lock()
-	defer r.mu.Unlock()
+	// Hostnames whose record could not be created are not claimed
+	failed := make(map[string]struct{})
 
-	deleted := 0
+	// Conflicting records go first; a hostname whose records could not be
+	// cleared is not created, since Technitium would reject the new record.
+	blocked := make(map[string]struct{})
+	for _, c := range p.Displaced {
+		if err := r.applyChange(ctx, server, "replace", c); err != nil {
+			if abortsPlan(err) {
+				return err
+			}
+			blocked[strings.ToLower(c.Name)] = struct{}{}
+			r.recordChangeError(server, c, err, result)
+			continue
+		}
+		result.RecordsDeleted++
+	}
 
-	r.logger.Debug("deleting hostnames",
-		slog.String("workload", workloadName),
-		slog.Any("hostnames", hostnames),
-	)
+	for _, c := range p.Creates {
+		if c.Ownership {
+			if hostname, ok := ownedHostname(c.Name); ok {
+				if _, skip := failed[strings.ToLower(hostname)]; skip {
+					continue
+				}
+			}
+		} else if _, skip := blocked[strings.ToLower(c.Name)]; skip {
+			continue
+		}
+		if err := r.applyChange(ctx, server, "create", c); err != nil {
+			if abortsPlan(err) {
+				return err
+			}
+			failed[strings.ToLower(c.Name)] = struct{}{}
+			r.recordChangeError(server, c, err, result)
+			continue
+		}
+		if !c.Ownership {
+			result.RecordsCreated++
+		}
+	}
 
-	for _, hostname := range hostnames {
-		// Apply include/exclude filters
-		if !r.cfg.MatchesFilters(hostname) {
+	for _, c := range p.Updates {
+		if err := r.applyChange(ctx, server, "update", c); err != nil {
+			if abortsPlan(err) {
+				return err
+			}
+			r.recordChangeError(server, c, err, result)
 			continue
 		}
+		result.RecordsUpdated++
+	}
 
-		// Dry run mode
-		if r.cfg.DryRun {
-			r.logger.Info("DRY RUN: would delete A record",
-				slog.String("hostname", hostname),
-				slog.String("zone", r.cfg.TechnitiumZone),
-				slog.String("ip", r.cfg.TargetIP),
-				slog.String("workload", workloadName),
-			)
-			deleted++
+	for _, c := range p.Stale {
+		if err := r.applyChange(ctx, server, "prune", c); err != nil {
+			if abortsPlan(err) {
+				return err
+			}
+			r.recordChangeError(server, c, err, result)
+			continue
+		}
+		result.RecordsDeleted++
+	}
+
+	// Deletes are grouped by hostname with the ownership record last; stop at the
+	// first failure for a hostname so its ownership record survives for a retry.
+	for _, c := range p.Deletes {
+		if c.Ownership {
+			if hostname, ok := ownedHostname(c.Name); ok {
+				if _, skip := failed[strings.ToLower(hostname)]; skip {
+					continue
+				}
+			}
+		}
+		if err := r.applyChange(ctx, server, "delete", c); err != nil {
+			if abortsPlan(err) {
+				return err
+			}
+			failed[strings.ToLower(c.Name)] = struct{}{}
+			r.recordChangeError(server, c, err, result)
 			continue
 		}
+		if !c.Ownership {
+			result.RecordsDeleted++
+		}
+	}
+
+	return nil
+}
+
+// abortsPlan reports whether a failed change leaves no point in applying the rest of
+// a zone's plan.
+func abortsPlan(err error) bool {
+	return errors.Is(err, technitium.ErrZoneNotFound) || abortsServer(err)
+}
+
+// applyChange performs a single record operation on a server. In dry run mode it only
+// logs the change.
+func (r *Reconciler) applyChange(ctx context.Context, server *technitium.Client, action string, c recordChange) error {
+	if r.cfg.DryRun {
+		r.logger.Info(fmt.Sprintf("DRY RUN: would %s %s record", action, c.Type),
+			slog.String("server", server.Name()),
+			slog.String("hostname", c.Name),
+			slog.String("zone", c.Zone),
+			slog.String("value", c.Value),
+			slog.Int("ttl", c.TTL),
+			slog.String("workload", c.Workload),
+		)
+		return nil
+	}
+
+	var err error
+	switch action {
+	case "create":
+		err = createRecord(ctx, server, c)
+	case "update":
+		err = updateRecord(ctx, server, c)
+	case "delete", "replace", "prune":
+		err = deleteRecord(ctx, server, c)
+	}
+	if err != nil {
+		return err
+	}
+
+	if c.Ownership {
+		return nil
+	}
 
-		// Check if record exists before deleting
-		exists, err := r.technitium.HasARecord(
-			ctx,
-			r.cfg.TechnitiumZone,
-			hostname,
-			r.cfg.TargetIP,
+	switch action {
+	case "create":
+		metrics.RecordDNSRecordCreated(server.Name(), c.Zone, c.Type)
+		r.logger.Info(fmt.Sprintf("created %s record", c.Type),
+			slog.String("server", server.Name()),
+			slog.String("hostname", c.Name),
+			slog.String("zone", c.Zone),
+			slog.String("value", c.Value),
+			slog.String("workload", c.Workload),
+		)
+	case "update":
+		metrics.RecordDNSRecordUpdated(server.Name(), c.Zone, c.Type)
+		r.logger.Info(fmt.Sprintf("updated %s record", c.Type),
+			slog.String("server", server.Name()),
+			slog.String("hostname", c.Name),
+			slog.String("zone", c.Zone),
+			slog.String("old_value", c.OldValue),
+			slog.String("value", c.Value),
+			slog.Int("ttl", c.TTL),
+			slog.String("workload", c.Workload),
+		)
+	case "delete":
+		metrics.RecordDNSRecordDeleted(server.Name(), c.Zone, c.Type)
+		r.logger.Info(fmt.Sprintf("deleted orphaned %s record", c.Type),
+			slog.String("server", server.Name()),
+			slog.String("hostname", c.Name),
+			slog.String("zone", c.Zone),
+			slog.String("value", c.Value),
+			slog.String("created_by", c.Provenance.Workload),
+		)
+	case "prune":
+		metrics.RecordDNSRecordDeleted(server.Name(), c.Zone, c.Type)
+		r.logger.Info(fmt.Sprintf("deleted stale %s record", c.Type),
+			slog.String("server", server.Name()),
+			slog.String("hostname", c.Name),
+			slog.String("zone", c.Zone),
+			slog.String("value", c.Value),
+			slog.String("workload", c.Workload),
+			slog.String("created_by", c.Provenance.Workload),
+		)
+	case "replace":
+		metrics.RecordDNSRecordDeleted(server.Name(), c.Zone, c.Type)
+		r.logger.Info(fmt.Sprintf("deleted conflicting %s record", c.Type),
+			slog.String("server", server.Name()),
+			slog.String("hostname", c.Name),
+			slog.String("zone", c.Zone),
+			slog.String("value", c.Value),
+			slog.String("workload", c.Workload),
+			slog.String("created_by", c.Provenance.Workload),
 		)
+	}
+
+	return nil
+}
+
+// createRecord adds a record through the type-specific Technitium client method,
+// stamping its provenance in the record's comments. Ownership records carry none.
+func createRecord(ctx context.Context, server *technitium.Client, c recordChange) error {
+	prov := technitium.WithProvenance(c.Provenance)
+	switch c.Type {
+	case "A":
+		return server.AddARecord(ctx, c.Zone, c.Name, c.Value, c.TTL, prov)
+	case "AAAA":
+		return server.AddAAAARecord(ctx, c.Zone, c.Name, c.Value, c.TTL, prov)
+	case "CNAME":
+		return server.AddCNAMERecord(ctx, c.Zone, c.Name, c.Value, c.TTL, prov)
+	case "TXT":
+		if c.Ownership {
+			return server.AddTXTRecord(ctx, c.Zone, c.Name, c.Value, c.TTL)
+		}
+		return server.AddTXTRecord(ctx, c.Zone, c.Name, c.Value, c.TTL, prov)
+	case "PTR":
+		return server.AddPTRRecord(ctx, c.Zone, c.Name, c.Value, c.TTL, prov)
+	case "SRV":
+		srv, err := technitium.ParseSRV(c.Value)
 		if err != nil {
-			r.logger.Error("failed to check record existence",
-				slog.String("hostname", hostname),
-				slog.String("error", err.Error()),
-			)
-			continue
+			return err
 		}
+		return server.AddSRVRecord(ctx, c.Zone, c.Name, srv, c.TTL, prov)
+	default:
+		return fmt.Errorf("unsupported record type %s", c.Type)
+	}
+}
 
-		if !exists {
-			r.logger.Debug("A record does not exist, skipping delete",
-				slog.String("hostname", hostname),
-			)
-			continue
+// updateRecord replaces a record's value and TTL through the type-specific Technitium
+// client method, refreshing its provenance or keeping the comments of a record this
+// instance did not write.
+func updateRecord(ctx context.Context, server *technitium.Client, c recordChange) error {
+	prov := technitium.WithComments(c.Comments)
+	if c.Provenance != (technitium.Provenance{}) {
+		prov = technitium.WithProvenance(c.Provenance)
+	}
+	switch c.Type {
+	case "A":
+		return server.UpdateARecord(ctx, c.Zone, c.Name, c.OldValue, c.Value, c.TTL, prov)
+	case "AAAA":
+		return server.UpdateAAAARecord(ctx, c.Zone, c.Name, c.OldValue, c.Value, c.TTL, prov)
+	case "CNAME":
+		return server.UpdateCNAMERecord(ctx, c.Zone, c.Name, c.Value, c.TTL, prov)
+	case "PTR":
+		return server.UpdatePTRRecord(ctx, c.Zone, c.Name, c.OldValue, c.Value, c.TTL, prov)
+	case "TXT":
+		return server.UpdateTXTRecord(ctx, c.Zone, c.Name, c.OldValue, c.Value, c.TTL, prov)
+	case "SRV":
+		oldSRV, err := technitium.ParseSRV(c.OldValue)
+		if err != nil {
+			return err
+		}
+		srv, err := technitium.ParseSRV(c.Value)
+		if err != nil {
+			return err
 		}
+		return server.UpdateSRVRecord(ctx, c.Zone, c.Name, oldSRV, srv, c.TTL, prov)
+	default:
+		return fmt.Errorf("unsupported record type %s", c.Type)
+	}
+}
 
-		// Delete the record
-		if err := r.technitium.DeleteARecord(
-			ctx,
-			r.cfg.TechnitiumZone,
-			hostname,
-			r.cfg.TargetIP,
-		); err != nil {
-			r.logger.Error("failed to delete A record",
-				slog.String("hostname", hostname),
-				slog.String("error", err.Error()),
-			)
-			continue
+// deleteRecord removes a record through the type-specific Technitium client method.
+func deleteRecord(ctx context.Context, server *technitium.Client, c recordChange) error {
+	switch c.Type {
+	case "A":
+		return server.DeleteARecord(ctx, c.Zone, c.Name, c.Value)
+	case "AAAA":
+		return server.DeleteAAAARecord(ctx, c.Zone, c.Name, c.Value)
+	case "CNAME":
+		return server.DeleteCNAMERecord(ctx, c.Zone, c.Name)
+	case "TXT":
+		return server.DeleteTXTRecord(ctx, c.Zone, c.Name, c.Value)
+	case "PTR":
+		return server.DeletePTRRecord(ctx, c.Zone, c.Name, c.Value)
+	case "SRV":
+		srv, err := technitium.ParseSRV(c.Value)
+		if err != nil {
+			return err
 		}
+		return server.DeleteSRVRecord(ctx, c.Zone, c.Name, srv)
+	default:
+		return fmt.Errorf("unsupported record type %s", c.Type)
+	}
+}
 
-		deleted++
-		metrics.RecordDNSRecordDeleted(r.cfg.TechnitiumZone)
-		r.logger.Info("deleted A record",
-			slog.String("hostname", hostname),
-			slog.String("zone", r.cfg.TechnitiumZone),
-			slog.String("ip", r.cfg.TargetIP),
-			slog.String("workload", workloadName),
+// recordChangeError logs and collects a failed record change. A record Technitium
+// rejects as conflicting was written by someone else since the zone was fetched, so the
+// hostname is reported as conflicted like one found in the plan rather than as an error.
+func (r *Reconciler) recordChangeError(server *technitium.Client, c recordChange, err error, result *ReconcileResult) {
+	if errors.Is(err, technitium.ErrRecordConflict) {
+		r.logger.Warn("technitium rejected record as conflicting, skipping",
+			slog.String("server", server.Name()),
+			slog.String("hostname", c.Name),
+			slog.String("type", c.Type),
+			slog.String("workload", c.Workload),
+			slog.String("error", err.Error()),
 		)
+		if !slices.Contains(result.HostnamesConflicted, c.Name) {
+			result.HostnamesConflicted = append(result.HostnamesConflicted, c.Name)
+		}
+		return
 	}
 
-	return deleted, nil
+	r.logger.Error("failed to apply record change",
+		slog.String("server", server.Name()),
+		slog.String("hostname", c.Name),
+		slog.String("type", c.Type),
+		slog.String("workload", c.Workload),
+		slog.String("error", err.Error()),
+	)
+	result.Errors = append(result.Errors, fmt.Errorf("hostname %s: %w", c.Name, err))
 }
@@ -3,12 +3,23 @@ package reconciler
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
 	"os"
 	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
 
 	"github.com/maxfield-allison/technitium-companion/internal/config"
+	"github.com/maxfield-allison/technitium-companion/internal/docker"
+	"github.com/maxfield-allison/technitium-companion/internal/technitium"
 	"github.com/maxfield-allison/technitium-companion/internal/traefik"
 )
 
@@ -233,8 +244,8 @@ func TestReconciler_FilterMatching(t *testing.T) {
 	}
 }
 
-// TestReconcileHostnames_Empty tests reconciling empty hostname list.
-func TestReconcileHostnames_Empty(t *testing.T) {
+// TestReconciler_ConcurrencySafe tests that the reconciler uses mutex properly.
+func TestReconciler_ConcurrencySafe(t *testing.T) {
 	cfg := &config.Config{
 		TechnitiumZone: "example.com",
 		TargetIP:       "10.0.0.1",
@@ -246,52 +257,593 @@ func TestReconcileHostnames_Empty(t *testing.T) {
 
 	rec := New(cfg, nil, parser, nil, WithLogger(logger))
 
-	result, err := rec.ReconcileHostnames(context.Background(), "test-workload", []string{})
+	// Verify the mutex exists (can't easily test locking without races)
+	// This just ensures the struct has the field
+	_ = rec
+}
+
+// fakeRecord is a record stored by fakeTechnitium. An empty Zone matches any zone.
+type fakeRecord struct {
+	Zone     string
+	Name     string
+	Type     string
+	TTL      int
+	Value    string
+	Comments string
+}
+
+// TestReconcileWorkloads_MultipleServers verifies every server receives the desired
+// records and a failing server is reported without holding back the others.
+func TestReconcileWorkloads_MultipleServers(t *testing.T) {
+	fake1, _ := newFakeTechnitium(t)
+	fake2, _ := newFakeTechnitium(t,
+		fakeRecord{Zone: "example.com", Name: "app.example.com", Type: "A", TTL: 300, Value: "10.0.0.1"},
+	)
+	down := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	t.Cleanup(down.Close)
+
+	servers := []*technitium.Client{
+		technitium.NewClient(fake1.url, "test-token", technitium.WithName("dns1")),
+		technitium.NewClient(fake2.url, "test-token", technitium.WithName("dns2")),
+		technitium.NewClient(down.URL, "test-token", technitium.WithName("dns3")),
+	}
+
+	cfg := &config.Config{
+		TechnitiumZone: "example.com",
+		TargetIP:       "10.0.0.1",
+		TTL:            300,
+	}
+	rec := New(cfg, nil, traefik.NewParser(), servers)
+
+	workloads := []docker.Workload{
+		{Name: "app", Labels: map[string]string{"traefik.http.routers.app.rule": "Host(`app.example.com`)"}},
+	}
+	result := &ReconcileResult{}
+
+	rec.reconcileWorkloads(context.Background(), workloads, result)
+
+	if !fake1.has("app.example.com", "A", "10.0.0.1") {
+		t.Error("expected record to be created on dns1")
+	}
+	if result.RecordsCreated != 1 || result.RecordsExisted != 1 {
+		t.Errorf("expected 1 record created and 1 existing, got %d created and %d existing", result.RecordsCreated, result.RecordsExisted)
+	}
+	if len(result.Errors) != 1 || !strings.Contains(result.Errors[0].Error(), "server dns3") {
+		t.Errorf("expected a single error for dns3, got %v", result.Errors)
+	}
+
+	if len(result.Servers) != 3 {
+		t.Fatalf("expected 3 server results, got %d", len(result.Servers))
+	}
+	for _, s := range result.Servers {
+		wantErrors := 0
+		if s.Server == "dns3" {
+			wantErrors = 1
+		}
+		if len(s.Errors) != wantErrors {
+			t.Errorf("server %s: expected %d errors, got %v", s.Server, wantErrors, s.Errors)
+		}
+	}
+}
+
+// newFailingTechnitium starts a Technitium server that answers with the JSON status
+// and error message fail returns for a request, or with an empty zone when fail
+// returns an empty status. It returns the client and the number of requests served.
+func newFailingTechnitium(t *testing.T, fail func(path, zone string) (string, string)) (*technitium.Client, *atomic.Int32) {
+	t.Helper()
+
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		parseForm(t, r)
+
+		resp := map[string]interface{}{"status": "ok"}
+		if status, message := fail(r.URL.Path, r.PostForm.Get("zone")); status != "" {
+			resp = map[string]interface{}{"status": status, "errorMessage": message}
+		} else if r.URL.Path == "/api/zones/records/get" {
+			resp["response"] = map[string]interface{}{
+				"zone":    map[string]interface{}{"name": r.PostForm.Get("zone"), "type": "Primary"},
+				"records": []interface{}{},
+			}
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(resp)
+	}))
+	t.Cleanup(server.Close)
+
+	return technitium.NewClient(server.URL, "test-token"), &calls
+}
+
+// TestReconcileWorkloads_AbortsOnUnauthorized verifies a rejected token ends the run on
+// the server after the first request with a single error.
+func TestReconcileWorkloads_AbortsOnUnauthorized(t *testing.T) {
+	client, calls := newFailingTechnitium(t, func(path, zone string) (string, string) {
+		return "invalid-token", "Invalid token or session expired."
+	})
+
+	cfg := &config.Config{
+		TechnitiumZones: []string{"example.com", "example.net"},
+		TargetIP:        "10.0.0.1",
+		TTL:             300,
+	}
+	rec := New(cfg, nil, traefik.NewParser(), []*technitium.Client{client})
+
+	workloads := []docker.Workload{
+		{Name: "app", Labels: map[string]string{"traefik.http.routers.app.rule": "Host(`app.example.com`)"}},
+		{Name: "web", Labels: map[string]string{"traefik.http.routers.web.rule": "Host(`web.example.net`)"}},
+	}
+	result := &ReconcileResult{}
+
+	rec.reconcileWorkloads(context.Background(), workloads, result)
+
+	if n := calls.Load(); n != 1 {
+		t.Errorf("expected a single request, got %d", n)
+	}
+	if len(result.Errors) != 1 || !errors.Is(result.Errors[0], technitium.ErrUnauthorized) {
+		t.Errorf("expected a single unauthorized error, got %v", result.Errors)
+	}
+}
+
+// TestReconcileWorkloads_ErrorClasses verifies a missing zone only skips that zone and a
+// record Technitium rejects as conflicting is reported as a conflict, not an error.
+func TestReconcileWorkloads_ErrorClasses(t *testing.T) {
+	client, _ := newFailingTechnitium(t, func(path, zone string) (string, string) {
+		switch {
+		case zone == "example.net":
+			return "error", "No such zone was found: example.net"
+		case path == "/api/zones/records/add":
+			return "error", "Cannot add record: record already exists."
+		}
+		return "", ""
+	})
+
+	cfg := &config.Config{
+		TechnitiumZones: []string{"example.com", "example.net"},
+		TargetIP:        "10.0.0.1",
+		TTL:             300,
+	}
+	rec := New(cfg, nil, traefik.NewParser(), []*technitium.Client{client})
+
+	workloads := []docker.Workload{
+		{Name: "app", Labels: map[string]string{"traefik.http.routers.app.rule": "Host(`app.example.com`)"}},
+		{Name: "web", Labels: map[string]string{"traefik.http.routers.web.rule": "Host(`web.example.net`)"}},
+	}
+	result := &ReconcileResult{}
+
+	rec.reconcileWorkloads(context.Background(), workloads, result)
+
+	if len(result.Errors) != 1 || !errors.Is(result.Errors[0], technitium.ErrZoneNotFound) {
+		t.Errorf("expected a single zone not found error, got %v", result.Errors)
+	}
+	if len(result.HostnamesConflicted) != 1 || result.HostnamesConflicted[0] != "app.example.com" {
+		t.Errorf("expected app.example.com to be conflicted, got %v", result.HostnamesConflicted)
+	}
+	if result.RecordsCreated != 0 {
+		t.Errorf("expected no records created, got %d", result.RecordsCreated)
+	}
+}
+
+// fakeRecordFields maps record types to the API parameter and rData field holding their value.
+var fakeRecordFields = map[string]string{
+	"A":     "ipAddress",
+	"AAAA":  "ipAddress",
+	"CNAME": "cname",
+	"TXT":   "text",
+	"PTR":   "ptrName",
+	"SRV":   "target",
+}
+
+// fakeSRVFields are the API parameters and rData fields of an SRV record, whose value
+// the fake stores as "priority weight port target".
+var fakeSRVFields = []string{"priority", "weight", "port", "target"}
+
+// fakeValue returns the value of a record in a request, from the new* parameters when
+// prefix is "new".
+func fakeValue(q url.Values, recordType, prefix string) string {
+	param := func(field string) string {
+		if prefix == "" {
+			return q.Get(field)
+		}
+		return q.Get(prefix + strings.ToUpper(field[:1]) + field[1:])
+	}
+	if recordType != "SRV" {
+		return param(fakeRecordFields[recordType])
+	}
+	if param("target") == "" {
+		return ""
+	}
+	values := make([]string, len(fakeSRVFields))
+	for i, field := range fakeSRVFields {
+		values[i] = param(field)
+	}
+	return strings.Join(values, " ")
+}
+
+// fakeRData returns the rData of a stored record as the API reports it.
+func fakeRData(rec fakeRecord) map[string]interface{} {
+	if rec.Type != "SRV" {
+		return map[string]interface{}{fakeRecordFields[rec.Type]: rec.Value}
+	}
+	rData := make(map[string]interface{})
+	for i, value := range strings.Fields(rec.Value) {
+		if n, err := strconv.Atoi(value); err == nil {
+			rData[fakeSRVFields[i]] = n
+		} else {
+			rData[fakeSRVFields[i]] = value
+		}
+	}
+	return rData
+}
+
+// parseForm parses the form of a request to a fake Technitium server, failing the
+// test if it is malformed or the API token was sent in the query string.
+func parseForm(t *testing.T, r *http.Request) {
+	t.Helper()
+	if err := r.ParseForm(); err != nil {
+		t.Errorf("parsing form: %v", err)
+	}
+	if r.URL.Query().Has("token") {
+		t.Errorf("expected no token in query string, got %q", r.URL.RawQuery)
+	}
+}
+
+// fakeTechnitium is an in-memory Technitium API used to exercise the reconciler end to end.
+type fakeTechnitium struct {
+	t       *testing.T
+	mu      sync.Mutex
+	records []fakeRecord
+	zones   map[string]bool // zone name to disabled
+	calls   map[string]int
+	url     string
+}
+
+// newFakeTechnitium starts a fake Technitium server seeded with records.
+func newFakeTechnitium(t *testing.T, records ...fakeRecord) (*fakeTechnitium, *technitium.Client) {
+	t.Helper()
 
+	f := &fakeTechnitium{
+		t:       t,
+		records: records,
+		zones:   make(map[string]bool),
+		calls:   make(map[string]int),
+	}
+	server := httptest.NewServer(http.HandlerFunc(f.handle))
+	t.Cleanup(server.Close)
+	f.url = server.URL
+
+	return f, technitium.NewClient(server.URL, "test-token")
+}
+
+func (f *fakeTechnitium) handle(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.calls[r.URL.Path]++
+	parseForm(f.t, r)
+	q := r.PostForm
+	zone := q.Get("zone")
+	name := q.Get("domain")
+	recordType := q.Get("type")
+	value := fakeValue(q, recordType, "")
+
+	resp := map[string]interface{}{"status": "ok"}
+
+	switch r.URL.Path {
+	case "/api/zones/list":
+		var out []map[string]interface{}
+		for name, disabled := range f.zones {
+			out = append(out, map[string]interface{}{"name": name, "type": "Primary", "disabled": disabled})
+		}
+		resp["response"] = map[string]interface{}{"zones": out}
+
+	case "/api/zones/create":
+		f.zones[zone] = false
+
+	case "/api/zones/records/get":
+		var out []map[string]interface{}
+		for _, rec := range f.records {
+			if rec.Zone != "" && rec.Zone != zone {
+				continue
+			}
+			if q.Get("listZone") != "true" && !strings.EqualFold(rec.Name, name) {
+				continue
+			}
+			out = append(out, map[string]interface{}{
+				"name":     rec.Name,
+				"type":     rec.Type,
+				"ttl":      rec.TTL,
+				"rData":    fakeRData(rec),
+				"comments": rec.Comments,
+			})
+		}
+		resp["response"] = map[string]interface{}{
+			"zone":    map[string]interface{}{"name": q.Get("zone"), "type": "Primary"},
+			"records": out,
+		}
+
+	case "/api/zones/records/add":
+		ttl, _ := strconv.Atoi(q.Get("ttl"))
+		f.records = append(f.records, fakeRecord{Zone: zone, Name: name, Type: recordType, TTL: ttl, Value: value, Comments: q.Get("comments")})
+
+	case "/api/zones/records/update":
+		ttl, _ := strconv.Atoi(q.Get("ttl"))
+		for i, rec := range f.records {
+			if (rec.Zone == "" || rec.Zone == zone) && strings.EqualFold(rec.Name, name) && rec.Type == recordType && (recordType == "CNAME" || rec.Value == value) {
+				f.records[i].TTL = ttl
+				f.records[i].Comments = q.Get("comments")
+				if newValue := fakeValue(q, recordType, "new"); newValue != "" {
+					f.records[i].Value = newValue
+				} else if recordType == "CNAME" {
+					f.records[i].Value = value
+				}
+			}
+		}
+
+	case "/api/zones/records/delete":
+		kept := f.records[:0]
+		for _, rec := range f.records {
+			// A name holds one CNAME, so deleting one does not name its target
+			if (rec.Zone == "" || rec.Zone == zone) && strings.EqualFold(rec.Name, name) && rec.Type == recordType && (value == "" || rec.Value == value) {
+				continue
+			}
+			kept = append(kept, rec)
+		}
+		f.records = kept
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
+
+// has reports whether the fake holds a record with the given name, type and value.
+func (f *fakeTechnitium) has(name, recordType, value string) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	for _, rec := range f.records {
+		if strings.EqualFold(rec.Name, name) && rec.Type == recordType && rec.Value == value {
+			return true
+		}
+	}
+	return false
+}
+
+// find returns the first record with the given name and type.
+func (f *fakeTechnitium) find(name, recordType string) (fakeRecord, bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	for _, rec := range f.records {
+		if strings.EqualFold(rec.Name, name) && rec.Type == recordType {
+			return rec, true
+		}
+	}
+	return fakeRecord{}, false
+}
+
+// hasInZone reports whether the fake holds a record in a specific zone.
+func (f *fakeTechnitium) hasInZone(zone, name, recordType, value string) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	for _, rec := range f.records {
+		if rec.Zone == zone && strings.EqualFold(rec.Name, name) && rec.Type == recordType && rec.Value == value {
+			return true
+		}
+	}
+	return false
+}
+
+// newFakeDocker starts a fake standalone Docker daemon named name that lists the
+// containers returned by list, or answers 500 when list returns nil.
+func newFakeDocker(t *testing.T, name string, list func() []map[string]interface{}) *docker.Client {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("API-Version", "1.43")
+		if !strings.HasSuffix(r.URL.Path, "/containers/json") {
+			return
+		}
+		containers := list()
+		if containers == nil {
+			http.Error(w, `{"message": "daemon unavailable"}`, http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(containers)
+	}))
+	t.Cleanup(server.Close)
+
+	client, err := docker.NewClient(context.Background(), "tcp://"+strings.TrimPrefix(server.URL, "http://"),
+		docker.WithName(name), docker.WithMode(docker.ModeStandalone))
 	if err != nil {
-		t.Errorf("unexpected error: %v", err)
+		t.Fatalf("creating docker client: %v", err)
 	}
-	if result.HostnamesFound != 0 {
-		t.Errorf("expected 0 hostnames found, got %d", result.HostnamesFound)
+	t.Cleanup(func() { client.Close() })
+	return client
+}
+
+// fakeContainer returns a container as listed by the Docker API, routed to hostname.
+func fakeContainer(name, hostname string) map[string]interface{} {
+	return map[string]interface{}{
+		"Id":     name + "-id",
+		"Names":  []string{"/" + name},
+		"Labels": map[string]string{"traefik.http.routers." + name + ".rule": "Host(`" + hostname + "`)"},
 	}
 }
 
-// TestDeleteHostnames_Empty tests deleting empty hostname list.
-func TestDeleteHostnames_Empty(t *testing.T) {
+// TestReconcile_MultipleDockerHosts verifies workloads of every Docker host are published
+// with their host's target IPs, and that a host that cannot be listed keeps its records.
+func TestReconcile_MultipleDockerHosts(t *testing.T) {
+	fake, client := newFakeTechnitium(t)
+
+	var edge2Down atomic.Bool
+	hosts := []*docker.Client{
+		newFakeDocker(t, "edge1", func() []map[string]interface{} {
+			return []map[string]interface{}{fakeContainer("app", "app.example.com")}
+		}),
+		newFakeDocker(t, "edge2", func() []map[string]interface{} {
+			if edge2Down.Load() {
+				return nil
+			}
+			return []map[string]interface{}{fakeContainer("web", "web.example.com"), fakeContainer("app", "app.example.com")}
+		}),
+	}
+
 	cfg := &config.Config{
 		TechnitiumZone: "example.com",
 		TargetIP:       "10.0.0.1",
-		DryRun:         true,
+		TTL:            300,
+		OrphanCleanup:  true,
+		OwnerID:        "default",
+		DockerEndpoints: []config.DockerEndpoint{
+			{Name: "edge1", TargetIPs: []string{"10.0.1.1"}},
+			{Name: "edge2"},
+		},
 	}
-	parser := traefik.NewParser()
-	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	rec := New(cfg, hosts, traefik.NewParser(), []*technitium.Client{client})
 
-	rec := New(cfg, nil, parser, nil, WithLogger(logger))
+	result, err := rec.Reconcile(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
 
-	deleted, err := rec.DeleteHostnames(context.Background(), "test-workload", []string{})
+	// A hostname served by both hosts resolves to both
+	for _, expected := range []struct{ name, ip string }{
+		{"app.example.com", "10.0.1.1"},
+		{"app.example.com", "10.0.0.1"},
+		{"web.example.com", "10.0.0.1"},
+	} {
+		if !fake.has(expected.name, "A", expected.ip) {
+			t.Errorf("expected %s -> %s", expected.name, expected.ip)
+		}
+	}
+	if web, _ := fake.find("web.example.com", "A"); !strings.Contains(web.Comments, "host=edge2") {
+		t.Errorf("expected provenance naming edge2, got %q", web.Comments)
+	}
+
+	if result.WorkloadsScanned != 3 || len(result.Hosts) != 2 {
+		t.Fatalf("expected 3 workloads from 2 hosts, got %d from %+v", result.WorkloadsScanned, result.Hosts)
+	}
+	if h := result.Hosts[1]; h.Host != "edge2" || h.WorkloadsScanned != 2 || h.HostnamesFound != 2 || h.Err != nil {
+		t.Errorf("unexpected edge2 result: %+v", h)
+	}
+
+	// edge2's last known workloads stand in while it is down, so orphan cleanup
+	// leaves its records alone
+	edge2Down.Store(true)
+	result, err = rec.Reconcile(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !fake.has("web.example.com", "A", "10.0.0.1") || !fake.has("app.example.com", "A", "10.0.0.1") {
+		t.Error("expected edge2's records to survive while it is down")
+	}
+	if h := result.Hosts[1]; h.Err == nil || h.WorkloadsScanned != 2 {
+		t.Errorf("expected edge2 reconciled from its last known workloads, got %+v", h)
+	}
+	if len(result.Errors) != 1 || !strings.Contains(result.Errors[0].Error(), "docker host edge2") {
+		t.Errorf("expected a single error for edge2, got %v", result.Errors)
+	}
+
+	// Without a previous listing edge2 is skipped and the run is partial: neither its
+	// orphaned hostname nor its address next to edge1's is removed
+	rec = New(cfg, hosts, traefik.NewParser(), []*technitium.Client{client})
+	result, err = rec.Reconcile(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !result.Partial {
+		t.Error("expected a partial run")
+	}
+	if !fake.has("web.example.com", "A", "10.0.0.1") || !fake.has("app.example.com", "A", "10.0.0.1") {
+		t.Error("expected edge2's records to survive a partial run")
+	}
+	if h := result.Hosts[1]; h.Err == nil || h.WorkloadsScanned != 0 {
+		t.Errorf("expected edge2 skipped, got %+v", h)
+	}
+	if len(result.Errors) != 1 || !strings.Contains(result.Errors[0].Error(), "docker host edge2") {
+		t.Errorf("expected a single error for edge2, got %v", result.Errors)
+	}
 
+	// Once it is back the run is complete again
+	edge2Down.Store(false)
+	result, err = rec.Reconcile(context.Background())
 	if err != nil {
-		t.Errorf("unexpected error: %v", err)
+		t.Fatalf("unexpected error: %v", err)
 	}
-	if deleted != 0 {
-		t.Errorf("expected 0 deleted, got %d", deleted)
+	if result.Partial || len(result.Errors) != 0 {
+		t.Errorf("expected a complete run, got partial=%v errors=%v", result.Partial, result.Errors)
 	}
 }
 
-// TestReconciler_ConcurrencySafe tests that the reconciler uses mutex properly.
-func TestReconciler_ConcurrencySafe(t *testing.T) {
+// TestReconcileWorkloads_TraefikExposure verifies only routes Traefik serves are published.
+func TestReconcileWorkloads_TraefikExposure(t *testing.T) {
+	fake, client := newFakeTechnitium(t)
+
 	cfg := &config.Config{
 		TechnitiumZone: "example.com",
 		TargetIP:       "10.0.0.1",
 		TTL:            300,
-		DryRun:         true,
+		OrphanCleanup:  true,
+		OwnerID:        "default",
 	}
-	parser := traefik.NewParser()
-	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	constraints, err := traefik.ParseConstraints("Label(`traefik.scope`, `public`)")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	parser := traefik.NewParser(traefik.WithExposedByDefault(false), traefik.WithConstraints(constraints))
+	rec := New(cfg, nil, parser, []*technitium.Client{client})
+
+	workload := func(name, enable, scope string) docker.Workload {
+		labels := map[string]string{
+			"traefik.http.routers." + name + ".rule": "Host(`" + name + ".example.com`)",
+			"traefik.scope":                          scope,
+		}
+		if enable != "" {
+			labels["traefik.enable"] = enable
+		}
+		return docker.Workload{Name: name, Labels: labels}
+	}
+	declared := workload("declared", "false", "public")
+	declared.Labels["technitium-companion.records.verify.type"] = "TXT"
+	declared.Labels["technitium-companion.records.verify.name"] = "example.com"
+	declared.Labels["technitium-companion.records.verify.value"] = "verification=abc"
+
+	workloads := []docker.Workload{
+		workload("public", "true", "public"),
+		workload("implicit", "", "public"),
+		workload("internal", "true", "internal"),
+		workload("invalid", "maybe", "public"),
+		declared,
+	}
+	result := &ReconcileResult{}
+	rec.reconcileWorkloads(context.Background(), workloads, result)
 
-	rec := New(cfg, nil, parser, nil, WithLogger(logger))
+	if !fake.has("public.example.com", "A", "10.0.0.1") {
+		t.Error("expected a record for the enabled workload matching the constraints")
+	}
+	for _, name := range []string{"implicit", "internal", "invalid", "declared"} {
+		if _, ok := fake.find(name+".example.com", "A"); ok {
+			t.Errorf("expected no record for %s", name)
+		}
+	}
+	if !fake.has("example.com", "TXT", "verification=abc") {
+		t.Error("expected the declared record of a workload traefik does not serve")
+	}
+	if len(result.Errors) != 1 || !strings.Contains(result.Errors[0].Error(), "workload invalid") {
+		t.Errorf("expected a single error for the invalid traefik.enable label, got %v", result.Errors)
+	}
 
-	// Verify the mutex exists (can't easily test locking without races)
-	// This just ensures the struct has the field
-	_ = rec
+	// Disabling the workload in Traefik removes its record as an orphan
+	workloads[0] = workload("public", "false", "public")
+	result = &ReconcileResult{}
+	rec.reconcileWorkloads(context.Background(), workloads, result)
+
+	if _, ok := fake.find("public.example.com", "A"); ok {
+		t.Error("expected the record of the disabled workload to be removed")
+	}
 }
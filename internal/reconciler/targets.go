@@ -0,0 +1,247 @@
+package reconciler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"time"
+
+	"github.com/maxfield-allison/technitium-companion/internal/config"
+	"github.com/maxfield-allison/technitium-companion/internal/docker"
+	"github.com/maxfield-allison/technitium-companion/internal/labels"
+)
+
+// derivesTargets reports whether the target IPs of a Docker host's workloads are
+// derived from where Traefik runs. Hosts with target IPs of their own keep them.
+func (r *Reconciler) derivesTargets(host *docker.Client) bool {
+	if r.cfg.TargetIPMode != config.TargetIPModeNode {
+		return false
+	}
+	e, ok := r.cfg.Endpoint(host.Name())
+	return !ok || len(e.TargetIPs) == 0
+}
+
+// deriveTargets returns the addresses of a Docker host's Traefik: those of the swarm
+// nodes running a Traefik task, or those of a standalone host.
+func (r *Reconciler) deriveTargets(ctx context.Context, host *docker.Client) ([]string, error) {
+	mode, err := host.Connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var ips []string
+	if mode == docker.ModeSwarm {
+		ips, err = host.ServiceNodeAddresses(ctx, r.cfg.TraefikService, r.cfg.TargetIPNodeLabel)
+	} else {
+		ips, err = host.HostAddresses(ctx, r.cfg.TargetIPInterface)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(ips) == 0 {
+		return nil, fmt.Errorf("no addresses found")
+	}
+	return ips, nil
+}
+
+// resolveTargets derives the target IPs of every Docker host that gets them from its
+// nodes, recording them in result. A host whose addresses cannot be derived keeps those
+// of the last reconciliation, so its records are not pruned while Traefik is being
+// rescheduled; a host without any has its workloads left out, which makes the run
+// partial. It returns the workloads that remain.
+func (r *Reconciler) resolveTargets(ctx context.Context, workloads []docker.Workload, result *ReconcileResult) []docker.Workload {
+	for _, host := range r.hosts {
+		if !r.derivesTargets(host) {
+			continue
+		}
+		if _, ok := r.lastWorkloads[host.Name()]; !ok {
+			continue // never listed, already reported
+		}
+
+		ips, err := r.deriveTargets(ctx, host)
+		if err != nil {
+			last, ok := r.nodeTargets[host.Name()]
+			if !ok {
+				r.logger.Error("failed to derive target IPs of docker host, skipping its workloads",
+					slog.String("docker_host", host.Name()),
+					slog.String("error", err.Error()),
+				)
+				result.Errors = append(result.Errors, fmt.Errorf("docker host %s: deriving target IPs: %w", host.Name(), err))
+				result.Partial = true
+				workloads = slices.DeleteFunc(workloads, func(w docker.Workload) bool {
+					return w.Host == host.Name()
+				})
+				continue
+			}
+
+			r.logger.Error("failed to derive target IPs, keeping the last known addresses",
+				slog.String("docker_host", host.Name()),
+				slog.Any("target_ips", last),
+				slog.String("error", err.Error()),
+			)
+			result.Errors = append(result.Errors, fmt.Errorf("docker host %s: deriving target IPs: %w", host.Name(), err))
+			ips = last
+		} else if last, ok := r.nodeTargets[host.Name()]; ok && !slices.Equal(last, ips) {
+			r.logger.Info("target IPs of docker host changed",
+				slog.String("docker_host", host.Name()),
+				slog.Any("old_target_ips", last),
+				slog.Any("target_ips", ips),
+			)
+		}
+
+		r.nodeTargets[host.Name()] = ips
+		if h := result.host(host.Name()); h != nil {
+			h.TargetIPs = ips
+		}
+	}
+
+	return workloads
+}
+
+// targetsFor returns the target IPs of the workloads on the named Docker host: the
+// addresses derived for it, or the configured ones.
+func (r *Reconciler) targetsFor(host string) []string {
+	if ips, ok := r.nodeTargets[host]; ok {
+		return ips
+	}
+	return r.cfg.TargetsFor(host)
+}
+
+// networkFor returns the Docker network whose address a workload's records point at:
+// that of its network label, or TargetIPNetwork in network target IP mode. It returns
+// an empty string when the target IPs apply.
+func (r *Reconciler) networkFor(overrides labels.Overrides) string {
+	if overrides.Network != "" {
+		return overrides.Network
+	}
+	if r.cfg.TargetIPMode == config.TargetIPModeNetwork {
+		return r.cfg.TargetIPNetwork
+	}
+	return ""
+}
+
+// networkAddresses returns the addresses of a workload on a Docker network. When they
+// cannot be looked up, for instance because its container is being restarted, those of
+// the previous reconciliation are returned along with the error, so its records are
+// not pruned in the meantime.
+func (r *Reconciler) networkAddresses(ctx context.Context, workload docker.Workload, network string) ([]string, error) {
+	key := networkKey(workload, network)
+
+	var ips []string
+	var err error
+	if host := r.dockerHost(workload.Host); host == nil {
+		err = fmt.Errorf("docker host %s is not configured", workload.Host)
+	} else {
+		ips, err = host.NetworkAddresses(ctx, workload, network)
+	}
+	if err != nil {
+		ips = r.lastNetworkTargets[key]
+	}
+
+	// A workload without addresses is recorded too, so WatchTargets notices its first
+	if err == nil || len(ips) > 0 {
+		r.networkTargets[key] = ips
+	}
+	return ips, err
+}
+
+// networkKey identifies the addresses of a workload on a Docker network.
+func networkKey(workload docker.Workload, network string) string {
+	return workload.Host + "/" + workload.ID + "/" + network
+}
+
+// dockerHost returns the client of the named Docker host, or nil.
+func (r *Reconciler) dockerHost(name string) *docker.Client {
+	for _, host := range r.hosts {
+		if host.Name() == name {
+			return host
+		}
+	}
+	return nil
+}
+
+// TargetsChanged derives the target IPs of every Docker host that gets them from its
+// nodes, and looks up the network addresses of the workloads published at them, and
+// reports whether any differ from those of the last reconciliation.
+func (r *Reconciler) TargetsChanged(ctx context.Context) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var errs []error
+	changed := false
+	for _, host := range r.hosts {
+		if !r.derivesTargets(host) {
+			continue
+		}
+
+		ips, err := r.deriveTargets(ctx, host)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("docker host %s: %w", host.Name(), err))
+			continue
+		}
+		if !slices.Equal(ips, r.nodeTargets[host.Name()]) {
+			changed = true
+		}
+	}
+
+	// Swarm tasks rescheduled with new addresses emit no service event
+	for _, host := range r.hosts {
+		for _, workload := range r.lastWorkloads[host.Name()] {
+			overrides, _ := labels.ParseOverrides(workload.Labels)
+			network := r.networkFor(overrides)
+			if network == "" {
+				continue
+			}
+			last, ok := r.networkTargets[networkKey(workload, network)]
+			if !ok {
+				continue // not published at a network address
+			}
+
+			ips, err := host.NetworkAddresses(ctx, workload, network)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("docker host %s: workload %s: %w", host.Name(), workload.Name, err))
+				continue
+			}
+			if !slices.Equal(ips, last) {
+				changed = true
+			}
+		}
+	}
+
+	return changed, errors.Join(errs...)
+}
+
+// WatchTargets checks the derived target IPs and network addresses every interval and
+// reconciles when they change, e.g. because a Traefik task moved to another node or a
+// task was rescheduled with a new address. It blocks until the context is cancelled.
+func (r *Reconciler) WatchTargets(ctx context.Context, interval time.Duration) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+
+		changed, err := r.TargetsChanged(ctx)
+		if err != nil {
+			r.logger.Warn("failed to check target IPs",
+				slog.String("error", err.Error()),
+			)
+		}
+		if !changed {
+			continue
+		}
+
+		r.logger.Info("target IPs changed, triggering full reconciliation")
+		if _, err := r.Reconcile(ctx); err != nil {
+			r.logger.Error("reconciliation failed",
+				slog.String("error", err.Error()),
+			)
+		}
+	}
+}
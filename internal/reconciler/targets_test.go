@@ -0,0 +1,244 @@
+package reconciler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/maxfield-allison/technitium-companion/internal/config"
+	"github.com/maxfield-allison/technitium-companion/internal/docker"
+	"github.com/maxfield-allison/technitium-companion/internal/technitium"
+	"github.com/maxfield-allison/technitium-companion/internal/traefik"
+)
+
+// fakeSwarm is a swarm manager running a whoami service and Traefik tasks on the
+// nodes in traefikNodes.
+type fakeSwarm struct {
+	mu           sync.Mutex
+	traefikNodes []string
+}
+
+func (f *fakeSwarm) moveTraefik(nodes ...string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.traefikNodes = nodes
+}
+
+func (f *fakeSwarm) handle(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	w.Header().Set("API-Version", "1.43")
+	w.Header().Set("Content-Type", "application/json")
+	switch {
+	case strings.HasSuffix(r.URL.Path, "/services"):
+		json.NewEncoder(w).Encode([]map[string]interface{}{{
+			"ID": "whoami-id",
+			"Spec": map[string]interface{}{
+				"Name":   "whoami",
+				"Labels": map[string]string{"traefik.http.routers.whoami.rule": "Host(`whoami.example.com`)"},
+			},
+		}})
+	case strings.HasSuffix(r.URL.Path, "/tasks"):
+		var tasks []map[string]interface{}
+		for _, node := range f.traefikNodes {
+			tasks = append(tasks, map[string]interface{}{"NodeID": node, "Status": map[string]interface{}{"State": "running"}})
+		}
+		json.NewEncoder(w).Encode(tasks)
+	case strings.HasSuffix(r.URL.Path, "/nodes"):
+		json.NewEncoder(w).Encode([]map[string]interface{}{
+			{"ID": "node1", "Status": map[string]interface{}{"Addr": "10.0.0.11"}},
+			{"ID": "node2", "Status": map[string]interface{}{"Addr": "10.0.0.12"}},
+		})
+	}
+}
+
+// TestReconcile_NodeTargets verifies target IPs follow the nodes Traefik runs on.
+func TestReconcile_NodeTargets(t *testing.T) {
+	fake, client := newFakeTechnitium(t)
+
+	swarm := &fakeSwarm{traefikNodes: []string{"node1"}}
+	server := httptest.NewServer(http.HandlerFunc(swarm.handle))
+	t.Cleanup(server.Close)
+
+	host, err := docker.NewClient(context.Background(), "tcp://"+strings.TrimPrefix(server.URL, "http://"),
+		docker.WithName(config.DefaultDockerHostName), docker.WithMode(docker.ModeSwarm))
+	if err != nil {
+		t.Fatalf("creating docker client: %v", err)
+	}
+	t.Cleanup(func() { host.Close() })
+
+	cfg := &config.Config{
+		TechnitiumZone: "example.com",
+		TTL:            300,
+		TargetIPMode:   config.TargetIPModeNode,
+		TraefikService: "traefik",
+	}
+	rec := New(cfg, []*docker.Client{host}, traefik.NewParser(), []*technitium.Client{client})
+
+	result, err := rec.Reconcile(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !fake.has("whoami.example.com", "A", "10.0.0.11") {
+		t.Error("expected whoami to point at node1")
+	}
+	if ips := result.Hosts[0].TargetIPs; len(ips) != 1 || ips[0] != "10.0.0.11" {
+		t.Errorf("expected derived target IPs in the result, got %v", ips)
+	}
+
+	if changed, err := rec.TargetsChanged(context.Background()); err != nil || changed {
+		t.Errorf("expected no change, got %v, %v", changed, err)
+	}
+
+	// Traefik scaled out to both nodes: one record per node
+	swarm.moveTraefik("node1", "node2")
+	if changed, err := rec.TargetsChanged(context.Background()); err != nil || !changed {
+		t.Errorf("expected a change, got %v, %v", changed, err)
+	}
+	if _, err := rec.Reconcile(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !fake.has("whoami.example.com", "A", "10.0.0.11") || !fake.has("whoami.example.com", "A", "10.0.0.12") {
+		t.Error("expected whoami to point at both nodes")
+	}
+
+	// Traefik moved to node2 only
+	swarm.moveTraefik("node2")
+	if _, err := rec.Reconcile(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if fake.has("whoami.example.com", "A", "10.0.0.11") || !fake.has("whoami.example.com", "A", "10.0.0.12") {
+		t.Error("expected whoami to follow traefik to node2")
+	}
+
+	// While no task runs, the last known addresses are kept
+	swarm.moveTraefik()
+	result, err = rec.Reconcile(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !fake.has("whoami.example.com", "A", "10.0.0.12") {
+		t.Error("expected whoami to keep pointing at node2")
+	}
+	if len(result.Errors) != 1 || !strings.Contains(result.Errors[0].Error(), "deriving target IPs") {
+		t.Errorf("expected a single target IP error, got %v", result.Errors)
+	}
+}
+
+// fakeLAN is a standalone Docker host running a Traefik-routed app and a NAS
+// container attached to the lan macvlan network at nasIP.
+type fakeLAN struct {
+	mu          sync.Mutex
+	nasIP       string
+	inspectDown bool
+}
+
+func (f *fakeLAN) set(nasIP string, inspectDown bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.nasIP, f.inspectDown = nasIP, inspectDown
+}
+
+func (f *fakeLAN) handle(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	nas := map[string]interface{}{
+		"Id":    "nas-id",
+		"Names": []string{"/nas"},
+		"Labels": map[string]string{
+			"traefik.enable":               "false",
+			"technitium-companion.hosts":   "nas.example.com",
+			"technitium-companion.network": "lan",
+		},
+		"NetworkSettings": map[string]interface{}{"Networks": map[string]interface{}{
+			"bridge": map[string]interface{}{"IPAddress": "172.17.0.5"},
+			"lan":    map[string]interface{}{"IPAddress": f.nasIP, "GlobalIPv6Address": "2001:db8::50"},
+		}},
+	}
+
+	w.Header().Set("API-Version", "1.43")
+	w.Header().Set("Content-Type", "application/json")
+	switch {
+	case strings.HasSuffix(r.URL.Path, "/containers/json"):
+		json.NewEncoder(w).Encode([]map[string]interface{}{nas, fakeContainer("app", "app.example.com")})
+	case strings.HasSuffix(r.URL.Path, "/containers/nas-id/json"):
+		if f.inspectDown {
+			http.Error(w, `{"message": "daemon unavailable"}`, http.StatusInternalServerError)
+			return
+		}
+		json.NewEncoder(w).Encode(nas)
+	}
+}
+
+// TestReconcile_NetworkAddresses verifies workloads on a Docker network are published
+// at their own address there.
+func TestReconcile_NetworkAddresses(t *testing.T) {
+	fake, client := newFakeTechnitium(t)
+
+	lan := &fakeLAN{nasIP: "192.168.1.50"}
+	server := httptest.NewServer(http.HandlerFunc(lan.handle))
+	t.Cleanup(server.Close)
+
+	host, err := docker.NewClient(context.Background(), "tcp://"+strings.TrimPrefix(server.URL, "http://"),
+		docker.WithName(config.DefaultDockerHostName), docker.WithMode(docker.ModeStandalone))
+	if err != nil {
+		t.Fatalf("creating docker client: %v", err)
+	}
+	t.Cleanup(func() { host.Close() })
+
+	cfg := &config.Config{
+		TechnitiumZone: "example.com",
+		TargetIP:       "10.0.0.1",
+		TTL:            300,
+	}
+	rec := New(cfg, []*docker.Client{host}, traefik.NewParser(), []*technitium.Client{client})
+
+	if _, err := rec.Reconcile(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !fake.has("nas.example.com", "A", "192.168.1.50") || !fake.has("nas.example.com", "AAAA", "2001:db8::50") {
+		t.Error("expected the NAS to be published at its lan addresses")
+	}
+	if fake.has("nas.example.com", "A", "10.0.0.1") || fake.has("nas.example.com", "A", "172.17.0.5") {
+		t.Error("expected the NAS not to be published at other addresses")
+	}
+	if !fake.has("app.example.com", "A", "10.0.0.1") {
+		t.Error("expected the app to be published at the target IP")
+	}
+
+	changed, err := rec.TargetsChanged(context.Background())
+	if err != nil || changed {
+		t.Errorf("expected unchanged addresses, got %v, %v", changed, err)
+	}
+
+	// The container was recreated with another address, which the refresh notices
+	lan.set("192.168.1.51", false)
+	if changed, err := rec.TargetsChanged(context.Background()); err != nil || !changed {
+		t.Errorf("expected the new address to be noticed, got %v, %v", changed, err)
+	}
+	if _, err := rec.Reconcile(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if fake.has("nas.example.com", "A", "192.168.1.50") || !fake.has("nas.example.com", "A", "192.168.1.51") {
+		t.Error("expected the NAS record to follow its new address")
+	}
+
+	// While the container cannot be inspected, its last known addresses are kept
+	lan.set("192.168.1.52", true)
+	result, err := rec.Reconcile(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !fake.has("nas.example.com", "A", "192.168.1.51") {
+		t.Error("expected the NAS to keep its last known address")
+	}
+	if len(result.Errors) != 1 || !strings.Contains(result.Errors[0].Error(), "workload nas") {
+		t.Errorf("expected a single error for the NAS, got %v", result.Errors)
+	}
+}
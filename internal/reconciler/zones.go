@@ -0,0 +1,109 @@
+package reconciler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/maxfield-allison/technitium-companion/internal/technitium"
+)
+
+// EnsureZones verifies that every configured zone, including the reverse zones when PTR
+// records are enabled, exists on each Technitium server and is enabled, so a misspelled
+// or disabled zone is reported once at startup rather than as a failure per record.
+// With ZoneAutoCreate, missing zones are created as Primary zones first. Disabled zones
+// are never enabled automatically.
+func (r *Reconciler) EnsureZones(ctx context.Context) error {
+	return r.checkZones(ctx, r.cfg.ZoneAutoCreate)
+}
+
+// CheckZones verifies the configured zones like EnsureZones but never creates any;
+// it backs the readiness probe.
+func (r *Reconciler) CheckZones(ctx context.Context) error {
+	return r.checkZones(ctx, false)
+}
+
+// checkZones checks the zones of every server, reporting the failures of each
+// under its name. A server whose zones cannot be listed is skipped and logged, as
+// its own health component reports it; the check only fails for it when no server
+// can be listed at all.
+func (r *Reconciler) checkZones(ctx context.Context, create bool) error {
+	var errs, unreachable []error
+	for _, server := range r.servers {
+		zones, err := server.ListZones(ctx)
+		if err != nil {
+			r.logger.Warn("skipping zone check of unreachable technitium server",
+				slog.String("server", server.Name()),
+				slog.String("error", err.Error()),
+			)
+			unreachable = append(unreachable, fmt.Errorf("server %s: %w", server.Name(), err))
+			continue
+		}
+		if err := r.checkServerZones(ctx, server, zones, create); err != nil {
+			errs = append(errs, fmt.Errorf("server %s: %w", server.Name(), err))
+		}
+	}
+	if len(unreachable) == len(r.servers) {
+		errs = append(errs, unreachable...)
+	}
+	return errors.Join(errs...)
+}
+
+// checkServerZones reports every configured zone that is missing or disabled among a
+// server's zones, creating missing ones first when create is set.
+func (r *Reconciler) checkServerZones(ctx context.Context, server *technitium.Client, zones []technitium.Zone, create bool) error {
+	byName := make(map[string]technitium.Zone, len(zones))
+	for _, z := range zones {
+		byName[technitium.CanonicalName(z.Name)] = z
+	}
+
+	zoneNames := r.cfg.Zones()
+	if r.cfg.PTRRecords {
+		zoneNames = append(zoneNames[:len(zoneNames):len(zoneNames)], r.cfg.ReverseZones...)
+	}
+
+	var errs []error
+	for _, zone := range zoneNames {
+		z, ok := byName[zone]
+		switch {
+		case !ok && create:
+			if r.cfg.DryRun {
+				r.logger.Info("DRY RUN: would create zone",
+					slog.String("server", server.Name()),
+					slog.String("zone", zone),
+				)
+				continue
+			}
+			if err := server.CreatePrimaryZone(ctx, zone, r.zoneOptions()); err != nil {
+				errs = append(errs, err)
+			}
+		case !ok:
+			errs = append(errs, fmt.Errorf("%w: %s", technitium.ErrZoneNotFound, zone))
+		case z.Disabled:
+			errs = append(errs, fmt.Errorf("zone %s is disabled", zone))
+		default:
+			r.logger.Debug("zone available",
+				slog.String("server", server.Name()),
+				slog.String("zone", zone),
+				slog.String("type", z.Type),
+			)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// zoneOptions returns the SOA and NS settings for zones created by EnsureZones.
+func (r *Reconciler) zoneOptions() technitium.ZoneOptions {
+	soa := r.cfg.ZoneSOA
+	return technitium.ZoneOptions{
+		PrimaryNameServer: soa.PrimaryNameServer,
+		ResponsiblePerson: soa.ResponsiblePerson,
+		Refresh:           soa.Refresh,
+		Retry:             soa.Retry,
+		Expire:            soa.Expire,
+		Minimum:           soa.Minimum,
+		NameServers:       r.cfg.ZoneNameServers,
+	}
+}
@@ -0,0 +1,119 @@
+package reconciler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/maxfield-allison/technitium-companion/internal/config"
+	"github.com/maxfield-allison/technitium-companion/internal/technitium"
+	"github.com/maxfield-allison/technitium-companion/internal/traefik"
+)
+
+// TestEnsureZones_ReportsMissingAndDisabled verifies every unusable zone is reported.
+func TestEnsureZones_ReportsMissingAndDisabled(t *testing.T) {
+	fake, client := newFakeTechnitium(t)
+	fake.zones["example.com"] = false
+	fake.zones["example.net"] = true
+
+	cfg := &config.Config{TechnitiumZones: []string{"example.com", "example.net", "example.org"}}
+	rec := New(cfg, nil, traefik.NewParser(), []*technitium.Client{client})
+
+	err := rec.EnsureZones(context.Background())
+	if err == nil {
+		t.Fatal("expected an error for the unusable zones")
+	}
+	if !strings.Contains(err.Error(), "example.net is disabled") {
+		t.Errorf("expected disabled zone in error, got %v", err)
+	}
+	if !errors.Is(err, technitium.ErrZoneNotFound) || !strings.Contains(err.Error(), "zone not found: example.org") {
+		t.Errorf("expected missing zone in error, got %v", err)
+	}
+	if strings.Contains(err.Error(), "example.com") {
+		t.Errorf("expected available zone to pass, got %v", err)
+	}
+	if fake.calls["/api/zones/create"] != 0 {
+		t.Error("expected no zones to be created without ZoneAutoCreate")
+	}
+}
+
+// TestEnsureZones_AutoCreate verifies missing zones are created but disabled ones are not touched.
+func TestEnsureZones_AutoCreate(t *testing.T) {
+	fake, client := newFakeTechnitium(t)
+	fake.zones["example.net"] = true
+
+	cfg := &config.Config{
+		TechnitiumZones: []string{"example.com", "example.net"},
+		ZoneAutoCreate:  true,
+	}
+	rec := New(cfg, nil, traefik.NewParser(), []*technitium.Client{client})
+
+	err := rec.EnsureZones(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "example.net is disabled") {
+		t.Errorf("expected disabled zone to be reported, got %v", err)
+	}
+	if disabled, ok := fake.zones["example.com"]; !ok || disabled {
+		t.Error("expected example.com to be created")
+	}
+	if fake.calls["/api/zones/create"] != 1 {
+		t.Errorf("expected 1 create call, got %d", fake.calls["/api/zones/create"])
+	}
+
+	// The zone now exists, so the readiness check passes for it
+	if err := rec.CheckZones(context.Background()); err == nil || strings.Contains(err.Error(), "example.com") {
+		t.Errorf("expected only example.net to fail, got %v", err)
+	}
+}
+
+// TestEnsureZones_DryRun verifies dry-run mode never creates zones.
+func TestEnsureZones_DryRun(t *testing.T) {
+	fake, client := newFakeTechnitium(t)
+
+	cfg := &config.Config{
+		TechnitiumZones: []string{"example.com"},
+		ZoneAutoCreate:  true,
+		DryRun:          true,
+	}
+	rec := New(cfg, nil, traefik.NewParser(), []*technitium.Client{client})
+
+	if err := rec.EnsureZones(context.Background()); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if fake.calls["/api/zones/create"] != 0 {
+		t.Error("expected no zones to be created in dry-run mode")
+	}
+}
+
+// TestEnsureZones_UnreachableServer verifies an unreachable server is skipped unless no
+// server can be reached.
+func TestEnsureZones_UnreachableServer(t *testing.T) {
+	fake, _ := newFakeTechnitium(t)
+	fake.zones["example.com"] = false
+	down := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	t.Cleanup(down.Close)
+
+	cfg := &config.Config{TechnitiumZones: []string{"example.com"}}
+	dns1 := technitium.NewClient(fake.url, "test-token", technitium.WithName("dns1"))
+	dns2 := technitium.NewClient(down.URL, "test-token", technitium.WithName("dns2"))
+
+	rec := New(cfg, nil, traefik.NewParser(), []*technitium.Client{dns1, dns2})
+	if err := rec.EnsureZones(context.Background()); err != nil {
+		t.Errorf("expected the unreachable server to be skipped, got %v", err)
+	}
+
+	// A missing zone on a reachable server still fails the check
+	delete(fake.zones, "example.com")
+	if err := rec.CheckZones(context.Background()); err == nil || !strings.Contains(err.Error(), "server dns1") {
+		t.Errorf("expected the missing zone on dns1 to be reported, got %v", err)
+	}
+
+	rec = New(cfg, nil, traefik.NewParser(), []*technitium.Client{dns2})
+	if err := rec.EnsureZones(context.Background()); err == nil || !strings.Contains(err.Error(), "server dns2") {
+		t.Errorf("expected an error when no server is reachable, got %v", err)
+	}
+}
@@ -0,0 +1,82 @@
+package technitium
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/url"
+)
+
+// loginEndpoint exchanges a username and password for a session token.
+const loginEndpoint = "/api/user/login"
+
+// loginResponse is the response from the user/login endpoint, which carries the
+// session token at the top level rather than under "response".
+type loginResponse struct {
+	Token string `json:"token"`
+}
+
+// WithCredentials authenticates with a username and password instead of a static API
+// token. The client logs in on its first request, caches the session token, and logs
+// in again when Technitium reports the session expired. An empty username is ignored.
+func WithCredentials(username, password string) ClientOption {
+	return func(c *Client) {
+		c.username = username
+		c.password = password
+	}
+}
+
+// usesCredentials reports whether the client logs in with a username and password.
+func (c *Client) usesCredentials() bool {
+	return c.username != ""
+}
+
+// sessionToken returns the token to authenticate the next request with, logging in
+// first when the client uses credentials and holds no session yet.
+func (c *Client) sessionToken(ctx context.Context) (string, error) {
+	c.authMu.Lock()
+	token := c.token
+	c.authMu.Unlock()
+
+	if token != "" || !c.usesCredentials() {
+		return token, nil
+	}
+	return c.login(ctx, "")
+}
+
+// login obtains a new session token. If another request already replaced stale
+// while this one waited, the newer token is returned without logging in again.
+func (c *Client) login(ctx context.Context, stale string) (string, error) {
+	c.authMu.Lock()
+	defer c.authMu.Unlock()
+
+	if c.token != stale {
+		return c.token, nil
+	}
+
+	params := url.Values{}
+	params.Set("user", c.username)
+	params.Set("pass", c.password)
+	params.Set("includeInfo", "false")
+
+	apiResp, err := c.doAttempt(ctx, loginEndpoint, params)
+	if err != nil {
+		return "", fmt.Errorf("logging in as %s: %w", c.username, c.redactErrorLocked(err))
+	}
+
+	var login loginResponse
+	if err := json.Unmarshal(apiResp.raw, &login); err != nil {
+		return "", fmt.Errorf("parsing login response: %w", err)
+	}
+	if login.Token == "" {
+		return "", fmt.Errorf("logging in as %s: no token in response", c.username)
+	}
+
+	c.token = login.Token
+	c.logger.Info("logged in to technitium",
+		slog.String("user", c.username),
+	)
+
+	return c.token, nil
+}
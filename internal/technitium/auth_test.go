@@ -0,0 +1,129 @@
+package technitium
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// fakeAuthServer issues session tokens on login and accepts only the latest one.
+type fakeAuthServer struct {
+	mu      sync.Mutex
+	logins  int
+	current string
+	calls   int
+}
+
+func (f *fakeAuthServer) handler(t *testing.T) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+
+		parseForm(t, r)
+		w.Header().Set("Content-Type", "application/json")
+
+		if r.URL.Path == loginEndpoint {
+			if r.PostForm.Get("user") != "admin" || r.PostForm.Get("pass") != "hunter2" {
+				json.NewEncoder(w).Encode(map[string]interface{}{"status": "error", "errorMessage": "Invalid username or password."})
+				return
+			}
+			f.logins++
+			f.current = "session-" + strings.Repeat("x", f.logins)
+			json.NewEncoder(w).Encode(map[string]interface{}{"status": "ok", "username": "admin", "token": f.current})
+			return
+		}
+
+		f.calls++
+		if r.PostForm.Get("token") != f.current {
+			json.NewEncoder(w).Encode(map[string]interface{}{"status": "invalid-token", "errorMessage": "Invalid token or session expired."})
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"status":   "ok",
+			"response": map[string]interface{}{"zone": mockZoneInfo("example.com"), "records": []interface{}{}},
+		})
+	}
+}
+
+func TestCredentials_LogsInOnFirstRequest(t *testing.T) {
+	fake := &fakeAuthServer{}
+	server := httptest.NewServer(fake.handler(t))
+	defer server.Close()
+
+	client := NewClient(server.URL, "", WithCredentials("admin", "hunter2"))
+
+	for i := 0; i < 2; i++ {
+		if _, err := client.GetRecords(context.Background(), "example.com", "app.example.com"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if fake.logins != 1 {
+		t.Errorf("expected the session token to be cached, got %d logins", fake.logins)
+	}
+}
+
+func TestCredentials_RenewsExpiredSession(t *testing.T) {
+	fake := &fakeAuthServer{}
+	server := httptest.NewServer(fake.handler(t))
+	defer server.Close()
+
+	client := NewClient(server.URL, "", WithCredentials("admin", "hunter2"))
+	if _, err := client.GetRecords(context.Background(), "example.com", "app.example.com"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Expire the session server-side
+	fake.mu.Lock()
+	fake.current = "rotated"
+	fake.mu.Unlock()
+
+	if _, err := client.GetRecords(context.Background(), "example.com", "app.example.com"); err != nil {
+		t.Fatalf("expected transparent re-login, got %v", err)
+	}
+	if fake.logins != 2 {
+		t.Errorf("expected 2 logins, got %d", fake.logins)
+	}
+	if fake.calls != 3 {
+		t.Errorf("expected the rejected request to be sent again once, got %d calls", fake.calls)
+	}
+}
+
+func TestCredentials_InvalidPassword(t *testing.T) {
+	fake := &fakeAuthServer{}
+	server := httptest.NewServer(fake.handler(t))
+	defer server.Close()
+
+	client := NewClient(server.URL, "", WithCredentials("admin", "wrong-password"))
+	_, err := client.GetRecords(context.Background(), "example.com", "app.example.com")
+	if err == nil {
+		t.Fatal("expected login error")
+	}
+	if !strings.Contains(err.Error(), "logging in as admin") {
+		t.Errorf("expected login error, got %v", err)
+	}
+	if strings.Contains(err.Error(), "wrong-password") {
+		t.Errorf("expected password to be redacted, got %v", err)
+	}
+	if fake.calls != 0 {
+		t.Errorf("expected no API calls without a session, got %d", fake.calls)
+	}
+}
+
+func TestStaticToken_InvalidTokenIsNotRetried(t *testing.T) {
+	fake := &fakeAuthServer{current: "other"}
+	server := httptest.NewServer(fake.handler(t))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test-token")
+	_, err := client.GetRecords(context.Background(), "example.com", "app.example.com")
+	if err == nil || !strings.Contains(err.Error(), "invalid token") {
+		t.Fatalf("expected invalid token error, got %v", err)
+	}
+	if fake.logins != 0 || fake.calls != 1 {
+		t.Errorf("expected a single call and no login, got %d calls, %d logins", fake.calls, fake.logins)
+	}
+}
@@ -0,0 +1,140 @@
+package technitium
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/maxfield-allison/technitium-companion/internal/metrics"
+)
+
+// BreakerState is the state of the client's circuit breaker.
+type BreakerState string
+
+const (
+	// BreakerClosed lets every request through.
+	BreakerClosed BreakerState = "closed"
+	// BreakerOpen rejects every request until the cooldown has passed.
+	BreakerOpen BreakerState = "open"
+	// BreakerHalfOpen lets a single probe request through to test whether the server recovered.
+	BreakerHalfOpen BreakerState = "half-open"
+)
+
+// ErrCircuitOpen is returned without contacting the server while the circuit breaker is open.
+var ErrCircuitOpen = errors.New("circuit breaker open")
+
+// breaker is a circuit breaker that opens after a number of consecutive transient
+// failures, so a server that is down is not sent a request per hostname.
+type breaker struct {
+	server    string
+	threshold int
+	cooldown  time.Duration
+	logger    *slog.Logger
+	now       func() time.Time
+
+	mu       sync.Mutex
+	state    BreakerState
+	failures int
+	openedAt time.Time
+	probing  bool
+}
+
+// newBreaker creates a closed circuit breaker for the named server.
+func newBreaker(server string, threshold int, cooldown time.Duration, logger *slog.Logger) *breaker {
+	b := &breaker{
+		server:    server,
+		threshold: threshold,
+		cooldown:  cooldown,
+		logger:    logger,
+		now:       time.Now,
+		state:     BreakerClosed,
+	}
+	metrics.SetCircuitBreakerState(server, string(BreakerClosed))
+	return b
+}
+
+// allow returns ErrCircuitOpen if a request may not be sent now. Once the cooldown
+// has passed, the breaker turns half-open and admits one probe at a time.
+func (b *breaker) allow() error {
+	if b == nil {
+		return nil
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case BreakerOpen:
+		if b.now().Sub(b.openedAt) < b.cooldown {
+			return ErrCircuitOpen
+		}
+		b.setState(BreakerHalfOpen)
+		b.probing = true
+	case BreakerHalfOpen:
+		if b.probing {
+			return ErrCircuitOpen
+		}
+		b.probing = true
+	}
+	return nil
+}
+
+// record updates the breaker with the outcome of an admitted request. Only transient
+// failures count against the server; an API error still proves it is reachable.
+func (b *breaker) record(err error) {
+	if b == nil {
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.probing = false
+
+	switch {
+	case errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded):
+		// The caller gave up; this says nothing about the server
+	case err == nil || !isTransient(err):
+		b.failures = 0
+		if b.state != BreakerClosed {
+			b.setState(BreakerClosed)
+		}
+	default:
+		b.failures++
+		if b.state == BreakerHalfOpen || b.failures >= b.threshold {
+			b.openedAt = b.now()
+			if b.state != BreakerOpen {
+				b.setState(BreakerOpen)
+			}
+		}
+	}
+}
+
+// current returns the breaker state, reporting an open breaker whose cooldown has
+// passed as half-open.
+func (b *breaker) current() BreakerState {
+	if b == nil {
+		return BreakerClosed
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state == BreakerOpen && b.now().Sub(b.openedAt) >= b.cooldown {
+		return BreakerHalfOpen
+	}
+	return b.state
+}
+
+// setState changes the state and publishes it. The caller must hold b.mu.
+func (b *breaker) setState(state BreakerState) {
+	b.logger.Warn("technitium circuit breaker state changed",
+		slog.String("from", string(b.state)),
+		slog.String("to", string(state)),
+		slog.Int("consecutive_failures", b.failures),
+	)
+	b.state = state
+	metrics.SetCircuitBreakerState(b.server, string(state))
+}
@@ -0,0 +1,105 @@
+package technitium
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestBreaker_OpensAndRecovers(t *testing.T) {
+	now := time.Now()
+	b := newBreaker("test", 2, time.Minute, slog.Default())
+	b.now = func() time.Time { return now }
+
+	failure := &transientError{err: errors.New("connection reset")}
+
+	for i := 0; i < 2; i++ {
+		if err := b.allow(); err != nil {
+			t.Fatalf("attempt %d: unexpected %v", i, err)
+		}
+		b.record(failure)
+	}
+	if b.current() != BreakerOpen {
+		t.Fatalf("expected open breaker, got %s", b.current())
+	}
+	if err := b.allow(); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("expected ErrCircuitOpen, got %v", err)
+	}
+
+	// After the cooldown a single probe is admitted
+	now = now.Add(time.Minute)
+	if b.current() != BreakerHalfOpen {
+		t.Fatalf("expected half-open breaker, got %s", b.current())
+	}
+	if err := b.allow(); err != nil {
+		t.Fatalf("expected probe to be admitted, got %v", err)
+	}
+	if err := b.allow(); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("expected a second concurrent probe to be rejected, got %v", err)
+	}
+
+	// A failed probe reopens the breaker
+	b.record(failure)
+	if b.current() != BreakerOpen {
+		t.Fatalf("expected reopened breaker, got %s", b.current())
+	}
+
+	now = now.Add(time.Minute)
+	if err := b.allow(); err != nil {
+		t.Fatalf("expected probe to be admitted, got %v", err)
+	}
+	b.record(nil)
+	if b.current() != BreakerClosed {
+		t.Fatalf("expected closed breaker after a successful probe, got %s", b.current())
+	}
+}
+
+func TestBreaker_IgnoresNonTransientErrors(t *testing.T) {
+	b := newBreaker("test", 1, time.Minute, slog.Default())
+
+	b.record(errors.New("API error: No such zone"))
+	b.record(context.Canceled)
+	if b.current() != BreakerClosed {
+		t.Errorf("expected closed breaker, got %s", b.current())
+	}
+}
+
+func TestClient_CircuitBreakerFailsFast(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test-token", WithCircuitBreaker(2, time.Minute))
+
+	for i := 0; i < 2; i++ {
+		if _, err := client.GetRecords(context.Background(), "example.com", "app.example.com"); err == nil {
+			t.Fatal("expected error")
+		}
+	}
+	if client.BreakerState() != BreakerOpen {
+		t.Fatalf("expected open breaker, got %s", client.BreakerState())
+	}
+
+	_, err := client.GetRecords(context.Background(), "example.com", "app.example.com")
+	if !errors.Is(err, ErrCircuitOpen) {
+		t.Errorf("expected ErrCircuitOpen, got %v", err)
+	}
+	if n := calls.Load(); n != 2 {
+		t.Errorf("expected the open breaker to stop requests, got %d", n)
+	}
+}
+
+func TestClient_NoBreakerIsClosed(t *testing.T) {
+	client := NewClient("http://localhost:5380", "test-token")
+	if client.BreakerState() != BreakerClosed {
+		t.Errorf("expected closed breaker, got %s", client.BreakerState())
+	}
+}
@@ -3,13 +3,18 @@ package technitium
 
 import (
 	"context"
+	"crypto/tls"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"log/slog"
+	"net"
 	"net/http"
 	"net/url"
 	"strconv"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/maxfield-allison/technitium-companion/internal/metrics"
@@ -17,27 +22,107 @@ import (
 
 // Record represents a DNS record from the Technitium API.
 type Record struct {
-	Name    string `json:"name"`
-	Type    string `json:"type"`
-	TTL     int    `json:"ttl"`
-	RData   RData  `json:"rData"`
-	Disabled bool  `json:"disabled"`
+	Name     string `json:"name"`
+	Type     string `json:"type"`
+	TTL      int    `json:"ttl"`
+	RData    RData  `json:"rData"`
+	Disabled bool   `json:"disabled"`
+	Comments string `json:"comments"`
+}
+
+// Value returns the type-specific value of the record, such as the address of an
+// A or AAAA record, the target of a CNAME or PTR, the text of a TXT record or the
+// data of an SRV record as formatted by SRV.String. IPv6 addresses and CNAME, PTR
+// and SRV targets are returned in canonical form so they compare equal regardless
+// of how they were written.
+func (r Record) Value() string {
+	switch r.Type {
+	case "A":
+		return r.RData.IPAddress
+	case "AAAA":
+		return CanonicalIP(r.RData.IPAddress)
+	case "CNAME":
+		return CanonicalName(r.RData.CNAME)
+	case "TXT":
+		return r.RData.Text
+	case "NS":
+		return CanonicalName(r.RData.NameServer)
+	case "PTR":
+		return CanonicalName(r.RData.PtrName)
+	case "SRV":
+		return r.RData.SRV().String()
+	default:
+		return r.RData.Value
+	}
+}
+
+// CanonicalIP returns the canonical text form of an IP address, or the input
+// unchanged if it does not parse.
+func CanonicalIP(ip string) string {
+	parsed := net.ParseIP(ip)
+	if parsed == nil {
+		return ip
+	}
+	return parsed.String()
+}
+
+// CanonicalName returns a DNS name lowercased and without its trailing dot.
+func CanonicalName(name string) string {
+	return strings.TrimSuffix(strings.ToLower(strings.TrimSpace(name)), ".")
 }
 
 // RData contains the record-specific data.
 type RData struct {
-	IPAddress string `json:"ipAddress,omitempty"` // For A records
-	Value     string `json:"value,omitempty"`     // Generic value field
+	IPAddress  string `json:"ipAddress,omitempty"`  // For A and AAAA records
+	CNAME      string `json:"cname,omitempty"`      // For CNAME records
+	Text       string `json:"text,omitempty"`       // For TXT records
+	NameServer string `json:"nameServer,omitempty"` // For NS records
+	PtrName    string `json:"ptrName,omitempty"`    // For PTR records
+	Value      string `json:"value,omitempty"`      // Generic value field
+
+	// For SRV records
+	Priority int    `json:"priority,omitempty"`
+	Weight   int    `json:"weight,omitempty"`
+	Port     int    `json:"port,omitempty"`
+	Target   string `json:"target,omitempty"`
+
+	// For SOA records
+	PrimaryNameServer string `json:"primaryNameServer,omitempty"`
+	ResponsiblePerson string `json:"responsiblePerson,omitempty"`
+	Serial            uint32 `json:"serial,omitempty"`
+	Refresh           int    `json:"refresh,omitempty"`
+	Retry             int    `json:"retry,omitempty"`
+	Expire            int    `json:"expire,omitempty"`
+	Minimum           int    `json:"minimum,omitempty"`
 }
 
 // Client is a Technitium DNS Server API client.
 type Client struct {
+	name       string
 	baseURL    string
-	token      string
 	httpClient *http.Client
+	tlsConfig  *tls.Config
 	logger     *slog.Logger
+	retry      RetryPolicy
+	breaker    *breaker
+	limiter    *limiter
+
+	breakerThreshold int
+	breakerCooldown  time.Duration
+	rateLimit        float64
+	rateBurst        int
+	maxInFlight      int
+
+	// With credentials, token is the current session token and is replaced on re-login
+	username string
+	password string
+	authMu   sync.Mutex
+	token    string
 }
 
+// DefaultServerName is the name of a client created without WithName.
+const DefaultServerName = "default"
+
 // ClientOption is a functional option for configuring the Client.
 type ClientOption func(*Client)
 
@@ -55,9 +140,37 @@ func WithLogger(logger *slog.Logger) ClientOption {
 	}
 }
 
-// NewClient creates a new Technitium API client.
+// WithName sets the server name reported in metrics labels, for telling several
+// Technitium servers apart. Defaults to DefaultServerName.
+func WithName(name string) ClientOption {
+	return func(c *Client) {
+		c.name = name
+	}
+}
+
+// WithRetryPolicy retries requests that fail with a transient error, such as a
+// timeout, a connection reset or a 5xx response. By default requests are not retried.
+func WithRetryPolicy(policy RetryPolicy) ClientOption {
+	return func(c *Client) {
+		c.retry = policy
+	}
+}
+
+// WithCircuitBreaker stops sending requests for cooldown once threshold consecutive
+// attempts have failed with a transient error. A threshold below 1 disables the breaker,
+// which is the default.
+func WithCircuitBreaker(threshold int, cooldown time.Duration) ClientOption {
+	return func(c *Client) {
+		c.breakerThreshold = threshold
+		c.breakerCooldown = cooldown
+	}
+}
+
+// NewClient creates a new Technitium API client. token may be empty when the client
+// logs in with WithCredentials instead.
 func NewClient(baseURL, token string, opts ...ClientOption) *Client {
 	c := &Client{
+		name:    DefaultServerName,
 		baseURL: baseURL,
 		token:   token,
 		httpClient: &http.Client{
@@ -70,18 +183,39 @@ func NewClient(baseURL, token string, opts ...ClientOption) *Client {
 		opt(c)
 	}
 
+	c.applyTLSConfig()
+
+	if c.breakerThreshold > 0 {
+		c.breaker = newBreaker(c.name, c.breakerThreshold, c.breakerCooldown, c.logger)
+	}
+	c.limiter = newLimiter(c.name, c.rateLimit, c.rateBurst, c.maxInFlight)
+
 	return c
 }
 
+// Name returns the server name set with WithName.
+func (c *Client) Name() string {
+	return c.name
+}
+
+// BreakerState returns the state of the circuit breaker. A client without a
+// breaker is always closed.
+func (c *Client) BreakerState() BreakerState {
+	return c.breaker.current()
+}
+
 // apiResponse is the standard Technitium API response wrapper.
 type apiResponse struct {
 	Status       string          `json:"status"`
 	ErrorMessage string          `json:"errorMessage,omitempty"`
 	Response     json.RawMessage `json:"response,omitempty"`
+
+	// raw is the whole response body, for endpoints that answer outside "response"
+	raw []byte
 }
 
-// zoneInfo contains zone metadata from the API response.
-type zoneInfo struct {
+// Zone contains zone metadata from the API response.
+type Zone struct {
 	Name     string `json:"name"`
 	Type     string `json:"type"`
 	Disabled bool   `json:"disabled"`
@@ -89,79 +223,251 @@ type zoneInfo struct {
 
 // recordsResponse is the response from the records/get endpoint.
 type recordsResponse struct {
-	Zone    zoneInfo `json:"zone"`
+	Zone    Zone     `json:"zone"`
 	Name    string   `json:"name"`
 	Records []Record `json:"records"`
 }
 
-// doRequest performs an HTTP request to the Technitium API.
-func (c *Client) doRequest(ctx context.Context, endpoint string, params url.Values) (*apiResponse, error) {
-	start := time.Now()
+// nonIdempotentEndpoints are endpoints whose requests must not be repeated once the
+// server may have received them: adding a record twice can fail or duplicate it, and
+// repeating an update or delete that went through fails because the record it names
+// is gone. The next reconciliation retries them against the zone's actual state.
+var nonIdempotentEndpoints = map[string]struct{}{
+	"/api/zones/records/add":    {},
+	"/api/zones/records/update": {},
+	"/api/zones/records/delete": {},
+	"/api/zones/create":         {},
+}
 
-	// Add token to params
+// doRequest performs an HTTP request to the Technitium API, retrying transient
+// failures according to the retry policy while the circuit breaker allows it.
+// A rejected session token is renewed once per request when the client logs in with
+// credentials. Returned errors never contain the API token or password.
+func (c *Client) doRequest(ctx context.Context, endpoint string, params url.Values) (*apiResponse, error) {
 	if params == nil {
 		params = url.Values{}
 	}
-	params.Set("token", c.token)
 
-	reqURL := fmt.Sprintf("%s%s?%s", c.baseURL, endpoint, params.Encode())
+	_, nonIdempotent := nonIdempotentEndpoints[endpoint]
+
+	var lastErr error
+	reauthenticated := false
+	for attempt := 1; ; attempt++ {
+		if err := c.breaker.allow(); err != nil {
+			if lastErr != nil {
+				return nil, fmt.Errorf("%w (last error: %w)", err, lastErr)
+			}
+			return nil, err
+		}
+
+		token, err := c.sessionToken(ctx)
+		if err != nil {
+			c.breaker.record(err)
+			return nil, err
+		}
+		params.Set("token", token)
+
+		apiResp, err := c.doAttempt(ctx, endpoint, params)
+		err = c.redactError(err, token)
+		c.breaker.record(err)
+		if err == nil {
+			return apiResp, nil
+		}
+		lastErr = err
+
+		var apiErr *APIError
+		if errors.As(err, &apiErr) && apiErr.sessionExpired() && c.usesCredentials() && !reauthenticated {
+			reauthenticated = true
+			c.logger.Info("technitium session expired, logging in again",
+				slog.String("endpoint", endpoint),
+			)
+			if _, err := c.login(ctx, token); err != nil {
+				return nil, err
+			}
+			// Renewing the session does not use up a retry
+			attempt--
+			continue
+		}
+
+		if attempt >= c.retry.MaxAttempts || !retryable(err, !nonIdempotent) || ctx.Err() != nil {
+			return nil, err
+		}
+
+		delay := c.retry.backoff(attempt)
+		c.logger.Warn("technitium request failed, retrying",
+			slog.String("endpoint", endpoint),
+			slog.Int("attempt", attempt),
+			slog.Duration("delay", delay),
+			slog.String("error", err.Error()),
+		)
+		metrics.RecordAPIRetry(c.name, endpoint)
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, err
+		case <-timer.C:
+		}
+	}
+}
+
+// doAttempt sends a single request once the limiter allows it. Error responses are
+// returned as an *APIError, and failures that may succeed on retry are wrapped in a
+// transientError.
+func (c *Client) doAttempt(ctx context.Context, endpoint string, params url.Values) (*apiResponse, error) {
+	release, err := c.limiter.acquire(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("waiting to send request: %w", err)
+	}
+	defer release()
+
+	start := time.Now()
+
+	reqURL := c.baseURL + endpoint
 
 	c.logger.Debug("making API request",
 		slog.String("endpoint", endpoint),
-		slog.String("url", c.baseURL+endpoint),
+		slog.String("url", reqURL),
 	)
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, reqURL, nil)
+	// Parameters, including the token, travel in a POST form body so they never
+	// appear in the URL, where access logs, proxies and *url.Error would capture them
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, reqURL, strings.NewReader(params.Encode()))
 	if err != nil {
-		metrics.RecordAPIRequest(endpoint, "error", time.Since(start).Seconds())
+		metrics.RecordAPIRequest(c.name, endpoint, "error", time.Since(start).Seconds())
 		return nil, fmt.Errorf("creating request: %w", err)
 	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
-		metrics.RecordAPIRequest(endpoint, "error", time.Since(start).Seconds())
-		return nil, fmt.Errorf("executing request: %w", err)
+		metrics.RecordAPIRequest(c.name, endpoint, "error", time.Since(start).Seconds())
+		err = fmt.Errorf("executing request: %w", err)
+		if ctx.Err() != nil {
+			return nil, err
+		}
+		return nil, &transientError{err: err}
 	}
 	defer resp.Body.Close()
 
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		metrics.RecordAPIRequest(endpoint, "error", time.Since(start).Seconds())
-		return nil, fmt.Errorf("reading response body: %w", err)
+		metrics.RecordAPIRequest(c.name, endpoint, "error", time.Since(start).Seconds())
+		return nil, &transientError{err: fmt.Errorf("reading response body: %w", err)}
 	}
 
 	if resp.StatusCode != http.StatusOK {
-		metrics.RecordAPIRequest(endpoint, "error", time.Since(start).Seconds())
-		return nil, fmt.Errorf("unexpected status code %d: %s", resp.StatusCode, string(body))
+		metrics.RecordAPIRequest(c.name, endpoint, "error", time.Since(start).Seconds())
+		err := newStatusError(endpoint, resp.StatusCode, string(body))
+		if transientStatus(resp.StatusCode) {
+			return nil, &transientError{err: err}
+		}
+		return nil, err
 	}
 
 	var apiResp apiResponse
 	if err := json.Unmarshal(body, &apiResp); err != nil {
-		metrics.RecordAPIRequest(endpoint, "error", time.Since(start).Seconds())
+		metrics.RecordAPIRequest(c.name, endpoint, "error", time.Since(start).Seconds())
 		return nil, fmt.Errorf("parsing response JSON: %w", err)
 	}
 
-	if apiResp.Status == "error" {
-		metrics.RecordAPIRequest(endpoint, "error", time.Since(start).Seconds())
-		return nil, fmt.Errorf("API error: %s", apiResp.ErrorMessage)
+	apiResp.raw = body
+
+	switch apiResp.Status {
+	case "error", "invalid-token", "2fa-required":
+		metrics.RecordAPIRequest(c.name, endpoint, "error", time.Since(start).Seconds())
+		return nil, newResponseError(endpoint, apiResp.Status, apiResp.ErrorMessage)
 	}
 
-	metrics.RecordAPIRequest(endpoint, "success", time.Since(start).Seconds())
+	metrics.RecordAPIRequest(c.name, endpoint, "success", time.Since(start).Seconds())
 	return &apiResp, nil
 }
 
-// AddARecord creates an A record in the specified zone.
-func (c *Client) AddARecord(ctx context.Context, zone, hostname, ip string, ttl int) error {
+// transientStatus reports whether an HTTP status code indicates a failure that may
+// succeed when retried.
+func transientStatus(code int) bool {
+	return code >= 500 || code == http.StatusTooManyRequests || code == http.StatusRequestTimeout
+}
+
+// addRecord creates a record of any type. data holds the type-specific parameters.
+func (c *Client) addRecord(ctx context.Context, zone, hostname, recordType string, ttl int, data url.Values, opts ...RecordOption) error {
+	params := url.Values{}
+	params.Set("zone", zone)
+	params.Set("domain", hostname)
+	params.Set("type", recordType)
+	params.Set("ttl", strconv.Itoa(ttl))
+	for k, v := range data {
+		params[k] = v
+	}
+	for _, opt := range opts {
+		opt(params)
+	}
+
+	if _, err := c.doRequest(ctx, "/api/zones/records/add", params); err != nil {
+		return fmt.Errorf("adding %s record for %s: %w", recordType, hostname, err)
+	}
+	return nil
+}
+
+// deleteRecord removes a record of any type. data holds the type-specific parameters
+// identifying which record at the name to delete.
+func (c *Client) deleteRecord(ctx context.Context, zone, hostname, recordType string, data url.Values) error {
+	params := url.Values{}
+	params.Set("zone", zone)
+	params.Set("domain", hostname)
+	params.Set("type", recordType)
+	for k, v := range data {
+		params[k] = v
+	}
+
+	if _, err := c.doRequest(ctx, "/api/zones/records/delete", params); err != nil {
+		return fmt.Errorf("deleting %s record for %s: %w", recordType, hostname, err)
+	}
+	return nil
+}
+
+// updateRecord changes a record of any type in place. data holds both the parameters
+// identifying the current record and the new* parameters replacing them.
+func (c *Client) updateRecord(ctx context.Context, zone, hostname, recordType string, ttl int, data url.Values, opts ...RecordOption) error {
 	params := url.Values{}
 	params.Set("zone", zone)
 	params.Set("domain", hostname)
-	params.Set("type", "A")
-	params.Set("ipAddress", ip)
+	params.Set("type", recordType)
 	params.Set("ttl", strconv.Itoa(ttl))
+	for k, v := range data {
+		params[k] = v
+	}
+	for _, opt := range opts {
+		opt(params)
+	}
+
+	if _, err := c.doRequest(ctx, "/api/zones/records/update", params); err != nil {
+		return fmt.Errorf("updating %s record for %s: %w", recordType, hostname, err)
+	}
+	return nil
+}
 
-	_, err := c.doRequest(ctx, "/api/zones/records/add", params)
+// hasRecord checks if a record of the given type and value exists at hostname.
+func (c *Client) hasRecord(ctx context.Context, zone, hostname, recordType, value string) (bool, error) {
+	records, err := c.GetRecords(ctx, zone, hostname)
 	if err != nil {
-		return fmt.Errorf("adding A record for %s: %w", hostname, err)
+		return false, err
+	}
+
+	for _, r := range records {
+		if r.Type == recordType && r.Value() == value {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// AddARecord creates an A record in the specified zone.
+func (c *Client) AddARecord(ctx context.Context, zone, hostname, ip string, ttl int, opts ...RecordOption) error {
+	if err := c.addRecord(ctx, zone, hostname, "A", ttl, url.Values{"ipAddress": {ip}}, opts...); err != nil {
+		return err
 	}
 
 	c.logger.Info("added A record",
@@ -176,15 +482,8 @@ func (c *Client) AddARecord(ctx context.Context, zone, hostname, ip string, ttl
 
 // DeleteARecord removes an A record from the specified zone.
 func (c *Client) DeleteARecord(ctx context.Context, zone, hostname, ip string) error {
-	params := url.Values{}
-	params.Set("zone", zone)
-	params.Set("domain", hostname)
-	params.Set("type", "A")
-	params.Set("ipAddress", ip)
-
-	_, err := c.doRequest(ctx, "/api/zones/records/delete", params)
-	if err != nil {
-		return fmt.Errorf("deleting A record for %s: %w", hostname, err)
+	if err := c.deleteRecord(ctx, zone, hostname, "A", url.Values{"ipAddress": {ip}}); err != nil {
+		return err
 	}
 
 	c.logger.Info("deleted A record",
@@ -196,6 +495,75 @@ func (c *Client) DeleteARecord(ctx context.Context, zone, hostname, ip string) e
 	return nil
 }
 
+// UpdateARecord replaces an existing A record's address and TTL in a single call.
+// Pass the same value for ip and newIP to change only the TTL.
+func (c *Client) UpdateARecord(ctx context.Context, zone, hostname, ip, newIP string, ttl int, opts ...RecordOption) error {
+	data := url.Values{"ipAddress": {ip}, "newIpAddress": {newIP}}
+	if err := c.updateRecord(ctx, zone, hostname, "A", ttl, data, opts...); err != nil {
+		return err
+	}
+
+	c.logger.Info("updated A record",
+		slog.String("hostname", hostname),
+		slog.String("ip", ip),
+		slog.String("new_ip", newIP),
+		slog.String("zone", zone),
+		slog.Int("ttl", ttl),
+	)
+
+	return nil
+}
+
+// AddAAAARecord creates an AAAA record in the specified zone.
+func (c *Client) AddAAAARecord(ctx context.Context, zone, hostname, ip string, ttl int, opts ...RecordOption) error {
+	if err := c.addRecord(ctx, zone, hostname, "AAAA", ttl, url.Values{"ipAddress": {ip}}, opts...); err != nil {
+		return err
+	}
+
+	c.logger.Info("added AAAA record",
+		slog.String("hostname", hostname),
+		slog.String("ip", ip),
+		slog.String("zone", zone),
+		slog.Int("ttl", ttl),
+	)
+
+	return nil
+}
+
+// DeleteAAAARecord removes an AAAA record from the specified zone.
+func (c *Client) DeleteAAAARecord(ctx context.Context, zone, hostname, ip string) error {
+	if err := c.deleteRecord(ctx, zone, hostname, "AAAA", url.Values{"ipAddress": {ip}}); err != nil {
+		return err
+	}
+
+	c.logger.Info("deleted AAAA record",
+		slog.String("hostname", hostname),
+		slog.String("ip", ip),
+		slog.String("zone", zone),
+	)
+
+	return nil
+}
+
+// UpdateAAAARecord replaces an existing AAAA record's address and TTL in a single call.
+// Pass the same value for ip and newIP to change only the TTL.
+func (c *Client) UpdateAAAARecord(ctx context.Context, zone, hostname, ip, newIP string, ttl int, opts ...RecordOption) error {
+	data := url.Values{"ipAddress": {ip}, "newIpAddress": {newIP}}
+	if err := c.updateRecord(ctx, zone, hostname, "AAAA", ttl, data, opts...); err != nil {
+		return err
+	}
+
+	c.logger.Info("updated AAAA record",
+		slog.String("hostname", hostname),
+		slog.String("ip", ip),
+		slog.String("new_ip", newIP),
+		slog.String("zone", zone),
+		slog.Int("ttl", ttl),
+	)
+
+	return nil
+}
+
 // GetRecords retrieves all records for a given hostname in the specified zone.
 func (c *Client) GetRecords(ctx context.Context, zone, hostname string) ([]Record, error) {
 	params := url.Values{}
@@ -223,18 +591,12 @@ func (c *Client) GetRecords(ctx context.Context, zone, hostname string) ([]Recor
 
 // HasARecord checks if a specific A record exists.
 func (c *Client) HasARecord(ctx context.Context, zone, hostname, ip string) (bool, error) {
-	records, err := c.GetRecords(ctx, zone, hostname)
-	if err != nil {
-		return false, err
-	}
-
-	for _, r := range records {
-		if r.Type == "A" && r.RData.IPAddress == ip {
-			return true, nil
-		}
-	}
+	return c.hasRecord(ctx, zone, hostname, "A", ip)
+}
 
-	return false, nil
+// HasAAAARecord checks if a specific AAAA record exists.
+func (c *Client) HasAAAARecord(ctx context.Context, zone, hostname, ip string) (bool, error) {
+	return c.hasRecord(ctx, zone, hostname, "AAAA", ip)
 }
 
 // EnsureARecord creates an A record if it doesn't already exist.
@@ -259,3 +621,235 @@ func (c *Client) EnsureARecord(ctx context.Context, zone, hostname, ip string, t
 
 	return true, nil
 }
+
+// EnsureAAAARecord creates an AAAA record if it doesn't already exist.
+// Returns true if a record was created, false if it already existed.
+func (c *Client) EnsureAAAARecord(ctx context.Context, zone, hostname, ip string, ttl int) (bool, error) {
+	exists, err := c.HasAAAARecord(ctx, zone, hostname, ip)
+	if err != nil {
+		return false, err
+	}
+
+	if exists {
+		c.logger.Debug("AAAA record already exists",
+			slog.String("hostname", hostname),
+			slog.String("ip", ip),
+		)
+		return false, nil
+	}
+
+	if err := c.AddAAAARecord(ctx, zone, hostname, ip, ttl); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// AddCNAMERecord creates a CNAME record pointing hostname at target.
+// A name holding a CNAME cannot hold any other record, so Technitium rejects the
+// call if other records already exist at hostname.
+func (c *Client) AddCNAMERecord(ctx context.Context, zone, hostname, target string, ttl int, opts ...RecordOption) error {
+	if err := c.addRecord(ctx, zone, hostname, "CNAME", ttl, url.Values{"cname": {target}}, opts...); err != nil {
+		return err
+	}
+
+	c.logger.Info("added CNAME record",
+		slog.String("hostname", hostname),
+		slog.String("target", target),
+		slog.String("zone", zone),
+		slog.Int("ttl", ttl),
+	)
+
+	return nil
+}
+
+// DeleteCNAMERecord removes the CNAME record at hostname.
+func (c *Client) DeleteCNAMERecord(ctx context.Context, zone, hostname string) error {
+	if err := c.deleteRecord(ctx, zone, hostname, "CNAME", nil); err != nil {
+		return err
+	}
+
+	c.logger.Info("deleted CNAME record",
+		slog.String("hostname", hostname),
+		slog.String("zone", zone),
+	)
+
+	return nil
+}
+
+// UpdateCNAMERecord repoints the CNAME record at hostname and sets its TTL.
+// A name holds at most one CNAME, so the current target is not needed.
+func (c *Client) UpdateCNAMERecord(ctx context.Context, zone, hostname, target string, ttl int, opts ...RecordOption) error {
+	if err := c.updateRecord(ctx, zone, hostname, "CNAME", ttl, url.Values{"cname": {target}}, opts...); err != nil {
+		return err
+	}
+
+	c.logger.Info("updated CNAME record",
+		slog.String("hostname", hostname),
+		slog.String("target", target),
+		slog.String("zone", zone),
+		slog.Int("ttl", ttl),
+	)
+
+	return nil
+}
+
+// GetCNAMERecord returns the canonical target of the CNAME record at hostname.
+// Returns false if hostname has no CNAME record.
+func (c *Client) GetCNAMERecord(ctx context.Context, zone, hostname string) (string, bool, error) {
+	records, err := c.GetRecords(ctx, zone, hostname)
+	if err != nil {
+		return "", false, err
+	}
+
+	for _, r := range records {
+		if r.Type == "CNAME" {
+			return r.Value(), true, nil
+		}
+	}
+
+	return "", false, nil
+}
+
+// AddTXTRecord creates a TXT record in the specified zone.
+func (c *Client) AddTXTRecord(ctx context.Context, zone, hostname, text string, ttl int, opts ...RecordOption) error {
+	if err := c.addRecord(ctx, zone, hostname, "TXT", ttl, url.Values{"text": {text}}, opts...); err != nil {
+		return err
+	}
+
+	c.logger.Debug("added TXT record",
+		slog.String("hostname", hostname),
+		slog.String("zone", zone),
+	)
+
+	return nil
+}
+
+// DeleteTXTRecord removes a TXT record from the specified zone.
+func (c *Client) DeleteTXTRecord(ctx context.Context, zone, hostname, text string) error {
+	if err := c.deleteRecord(ctx, zone, hostname, "TXT", url.Values{"text": {text}}); err != nil {
+		return err
+	}
+
+	c.logger.Debug("deleted TXT record",
+		slog.String("hostname", hostname),
+		slog.String("zone", zone),
+	)
+
+	return nil
+}
+
+// UpdateTXTRecord replaces the text and TTL of an existing TXT record in a single
+// call. Pass the same value for text and newText to change only the TTL.
+func (c *Client) UpdateTXTRecord(ctx context.Context, zone, hostname, text, newText string, ttl int, opts ...RecordOption) error {
+	data := url.Values{"text": {text}, "newText": {newText}}
+	if err := c.updateRecord(ctx, zone, hostname, "TXT", ttl, data, opts...); err != nil {
+		return err
+	}
+
+	c.logger.Debug("updated TXT record",
+		slog.String("hostname", hostname),
+		slog.String("zone", zone),
+		slog.Int("ttl", ttl),
+	)
+
+	return nil
+}
+
+// GetTXTRecords returns the text of every TXT record at hostname.
+func (c *Client) GetTXTRecords(ctx context.Context, zone, hostname string) ([]string, error) {
+	records, err := c.GetRecords(ctx, zone, hostname)
+	if err != nil {
+		return nil, err
+	}
+
+	var texts []string
+	for _, r := range records {
+		if r.Type == "TXT" {
+			texts = append(texts, r.Value())
+		}
+	}
+
+	return texts, nil
+}
+
+// AddPTRRecord creates a PTR record mapping a reverse name such as
+// 1.0.0.10.in-addr.arpa to hostname.
+func (c *Client) AddPTRRecord(ctx context.Context, zone, name, hostname string, ttl int, opts ...RecordOption) error {
+	if err := c.addRecord(ctx, zone, name, "PTR", ttl, url.Values{"ptrName": {hostname}}, opts...); err != nil {
+		return err
+	}
+
+	c.logger.Info("added PTR record",
+		slog.String("name", name),
+		slog.String("hostname", hostname),
+		slog.String("zone", zone),
+		slog.Int("ttl", ttl),
+	)
+
+	return nil
+}
+
+// DeletePTRRecord removes the PTR record mapping name to hostname.
+func (c *Client) DeletePTRRecord(ctx context.Context, zone, name, hostname string) error {
+	if err := c.deleteRecord(ctx, zone, name, "PTR", url.Values{"ptrName": {hostname}}); err != nil {
+		return err
+	}
+
+	c.logger.Info("deleted PTR record",
+		slog.String("name", name),
+		slog.String("hostname", hostname),
+		slog.String("zone", zone),
+	)
+
+	return nil
+}
+
+// UpdatePTRRecord repoints an existing PTR record from hostname to newHostname and
+// sets its TTL. Pass the same value for both to change only the TTL.
+func (c *Client) UpdatePTRRecord(ctx context.Context, zone, name, hostname, newHostname string, ttl int, opts ...RecordOption) error {
+	data := url.Values{"ptrName": {hostname}, "newPtrName": {newHostname}}
+	if err := c.updateRecord(ctx, zone, name, "PTR", ttl, data, opts...); err != nil {
+		return err
+	}
+
+	c.logger.Info("updated PTR record",
+		slog.String("name", name),
+		slog.String("hostname", hostname),
+		slog.String("new_hostname", newHostname),
+		slog.String("zone", zone),
+		slog.Int("ttl", ttl),
+	)
+
+	return nil
+}
+
+// HasPTRRecord checks if a PTR record maps name to hostname.
+func (c *Client) HasPTRRecord(ctx context.Context, zone, name, hostname string) (bool, error) {
+	return c.hasRecord(ctx, zone, name, "PTR", CanonicalName(hostname))
+}
+
+// ListZoneRecords retrieves every record in the specified zone.
+func (c *Client) ListZoneRecords(ctx context.Context, zone string) ([]Record, error) {
+	params := url.Values{}
+	params.Set("zone", zone)
+	params.Set("domain", zone)
+	params.Set("listZone", "true")
+
+	apiResp, err := c.doRequest(ctx, "/api/zones/records/get", params)
+	if err != nil {
+		return nil, fmt.Errorf("listing records for zone %s: %w", zone, err)
+	}
+
+	var recordsResp recordsResponse
+	if err := json.Unmarshal(apiResp.Response, &recordsResp); err != nil {
+		return nil, fmt.Errorf("parsing records response: %w", err)
+	}
+
+	c.logger.Debug("listed zone records",
+		slog.String("zone", zone),
+		slog.Int("count", len(recordsResp.Records)),
+	)
+
+	return recordsResp.Records, nil
+}
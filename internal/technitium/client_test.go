@@ -32,6 +32,13 @@ func TestNewClient(t *testing.T) {
 	if client.logger == nil {
 		t.Error("expected logger to be initialized")
 	}
+	if client.Name() != DefaultServerName {
+		t.Errorf("expected default name %s, got %s", DefaultServerName, client.Name())
+	}
+
+	if named := NewClient("http://localhost:5380", "test-token", WithName("dns1")); named.Name() != "dns1" {
+		t.Errorf("expected name dns1, got %s", named.Name())
+	}
 }
 
 func TestAddARecord_Success(t *testing.T) {
@@ -41,7 +48,8 @@ func TestAddARecord_Success(t *testing.T) {
 			t.Errorf("unexpected path: %s", r.URL.Path)
 		}
 
-		query := r.URL.Query()
+		parseForm(t, r)
+		query := r.PostForm
 		if query.Get("token") != "test-token" {
 			t.Errorf("unexpected token: %s", query.Get("token"))
 		}
@@ -106,7 +114,8 @@ func TestDeleteARecord_Success(t *testing.T) {
 			t.Errorf("unexpected path: %s", r.URL.Path)
 		}
 
-		query := r.URL.Query()
+		parseForm(t, r)
+		query := r.PostForm
 		if query.Get("zone") != "example.com" {
 			t.Errorf("unexpected zone: %s", query.Get("zone"))
 		}
@@ -197,7 +206,7 @@ func TestGetRecords_NoRecords(t *testing.T) {
 		json.NewEncoder(w).Encode(map[string]interface{}{
 			"status": "ok",
 			"response": map[string]interface{}{
-				"zone": mockZoneInfo("example.com"),
+				"zone":    mockZoneInfo("example.com"),
 				"name":    "nonexistent.example.com",
 				"records": []map[string]interface{}{},
 			},
@@ -257,7 +266,7 @@ func TestHasARecord_NotExists(t *testing.T) {
 		json.NewEncoder(w).Encode(map[string]interface{}{
 			"status": "ok",
 			"response": map[string]interface{}{
-				"zone": mockZoneInfo("example.com"),
+				"zone":    mockZoneInfo("example.com"),
 				"name":    "test.example.com",
 				"records": []map[string]interface{}{},
 			},
@@ -365,7 +374,7 @@ func TestEnsureARecord_Creates(t *testing.T) {
 			json.NewEncoder(w).Encode(map[string]interface{}{
 				"status": "ok",
 				"response": map[string]interface{}{
-					"zone": mockZoneInfo("example.com"),
+					"zone":    mockZoneInfo("example.com"),
 					"name":    "test.example.com",
 					"records": []map[string]interface{}{},
 				},
@@ -421,3 +430,493 @@ func TestInvalidJSON(t *testing.T) {
 		t.Error("expected error for invalid JSON")
 	}
 }
+
+func TestAddTXTRecord_Success(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/zones/records/add" {
+			t.Errorf("unexpected path: %s", r.URL.Path)
+		}
+
+		parseForm(t, r)
+		query := r.PostForm
+		if query.Get("type") != "TXT" {
+			t.Errorf("unexpected type: %s", query.Get("type"))
+		}
+		if query.Get("domain") != "_owner.test.example.com" {
+			t.Errorf("unexpected domain: %s", query.Get("domain"))
+		}
+		if query.Get("text") != "heritage=test" {
+			t.Errorf("unexpected text: %s", query.Get("text"))
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"status": "ok",
+		})
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test-token")
+	err := client.AddTXTRecord(context.Background(), "example.com", "_owner.test.example.com", "heritage=test", 300)
+
+	if err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestDeleteTXTRecord_Success(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/zones/records/delete" {
+			t.Errorf("unexpected path: %s", r.URL.Path)
+		}
+
+		parseForm(t, r)
+		query := r.PostForm
+		if query.Get("type") != "TXT" {
+			t.Errorf("unexpected type: %s", query.Get("type"))
+		}
+		if query.Get("text") != "heritage=test" {
+			t.Errorf("unexpected text: %s", query.Get("text"))
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"status": "ok",
+		})
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test-token")
+	err := client.DeleteTXTRecord(context.Background(), "example.com", "_owner.test.example.com", "heritage=test")
+
+	if err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestUpdateTXTRecord_Success(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/zones/records/update" {
+			t.Errorf("unexpected path: %s", r.URL.Path)
+		}
+
+		parseForm(t, r)
+		query := r.PostForm
+		if query.Get("text") != "verification=old" {
+			t.Errorf("unexpected text: %s", query.Get("text"))
+		}
+		if query.Get("newText") != "verification=new" {
+			t.Errorf("unexpected newText: %s", query.Get("newText"))
+		}
+		if query.Get("comments") == "" {
+			t.Error("expected provenance in comments")
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"status": "ok",
+		})
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test-token")
+	err := client.UpdateTXTRecord(context.Background(), "example.com", "example.com", "verification=old", "verification=new", 300,
+		WithProvenance(Provenance{Workload: "app"}))
+
+	if err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestGetTXTRecords(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"status": "ok",
+			"response": map[string]interface{}{
+				"zone": mockZoneInfo("example.com"),
+				"records": []map[string]interface{}{
+					{"name": "example.com", "type": "TXT", "ttl": 300, "rData": map[string]interface{}{"text": "v=spf1 -all"}},
+					{"name": "example.com", "type": "A", "ttl": 300, "rData": map[string]interface{}{"ipAddress": "10.0.0.1"}},
+					{"name": "example.com", "type": "TXT", "ttl": 300, "rData": map[string]interface{}{"text": "verification=abc"}},
+				},
+			},
+		})
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test-token")
+	texts, err := client.GetTXTRecords(context.Background(), "example.com", "example.com")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(texts) != 2 || texts[0] != "v=spf1 -all" || texts[1] != "verification=abc" {
+		t.Errorf("unexpected TXT records: %v", texts)
+	}
+}
+
+func TestAddPTRRecord_Success(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/zones/records/add" {
+			t.Errorf("unexpected path: %s", r.URL.Path)
+		}
+
+		parseForm(t, r)
+		query := r.PostForm
+		if query.Get("type") != "PTR" {
+			t.Errorf("unexpected type: %s", query.Get("type"))
+		}
+		if query.Get("zone") != "0.10.in-addr.arpa" {
+			t.Errorf("unexpected zone: %s", query.Get("zone"))
+		}
+		if query.Get("domain") != "1.0.0.10.in-addr.arpa" {
+			t.Errorf("unexpected domain: %s", query.Get("domain"))
+		}
+		if query.Get("ptrName") != "app.example.com" {
+			t.Errorf("unexpected ptrName: %s", query.Get("ptrName"))
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"status": "ok",
+		})
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test-token")
+	err := client.AddPTRRecord(context.Background(), "0.10.in-addr.arpa", "1.0.0.10.in-addr.arpa", "app.example.com", 300)
+
+	if err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestUpdatePTRRecord_Success(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/zones/records/update" {
+			t.Errorf("unexpected path: %s", r.URL.Path)
+		}
+
+		parseForm(t, r)
+		query := r.PostForm
+		if query.Get("type") != "PTR" {
+			t.Errorf("unexpected type: %s", query.Get("type"))
+		}
+		if query.Get("ptrName") != "old.example.com" {
+			t.Errorf("unexpected ptrName: %s", query.Get("ptrName"))
+		}
+		if query.Get("newPtrName") != "app.example.com" {
+			t.Errorf("unexpected newPtrName: %s", query.Get("newPtrName"))
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"status": "ok",
+		})
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test-token")
+	err := client.UpdatePTRRecord(context.Background(), "0.10.in-addr.arpa", "1.0.0.10.in-addr.arpa", "old.example.com", "app.example.com", 300)
+
+	if err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestHasPTRRecord(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"status": "ok",
+			"response": map[string]interface{}{
+				"zone": mockZoneInfo("0.10.in-addr.arpa"),
+				"records": []map[string]interface{}{
+					{
+						"name":  "1.0.0.10.in-addr.arpa",
+						"type":  "PTR",
+						"ttl":   300,
+						"rData": map[string]interface{}{"ptrName": "App.Example.com."},
+					},
+				},
+			},
+		})
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test-token")
+
+	exists, err := client.HasPTRRecord(context.Background(), "0.10.in-addr.arpa", "1.0.0.10.in-addr.arpa", "app.example.com")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !exists {
+		t.Error("expected PTR record to match regardless of case and trailing dot")
+	}
+
+	exists, err = client.HasPTRRecord(context.Background(), "0.10.in-addr.arpa", "1.0.0.10.in-addr.arpa", "web.example.com")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if exists {
+		t.Error("expected no PTR record for web.example.com")
+	}
+}
+
+func TestListZoneRecords_Success(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		parseForm(t, r)
+		query := r.PostForm
+		if query.Get("listZone") != "true" {
+			t.Errorf("expected listZone=true, got %s", query.Get("listZone"))
+		}
+		if query.Get("domain") != "example.com" {
+			t.Errorf("unexpected domain: %s", query.Get("domain"))
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"status": "ok",
+			"response": map[string]interface{}{
+				"zone": mockZoneInfo("example.com"),
+				"records": []map[string]interface{}{
+					{
+						"name": "app.example.com",
+						"type": "A",
+						"ttl":  300,
+						"rData": map[string]interface{}{
+							"ipAddress": "10.0.0.1",
+						},
+					},
+					{
+						"name": "_owner.app.example.com",
+						"type": "TXT",
+						"ttl":  300,
+						"rData": map[string]interface{}{
+							"text": "heritage=test",
+						},
+					},
+				},
+			},
+		})
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test-token")
+	records, err := client.ListZoneRecords(context.Background(), "example.com")
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(records) != 2 {
+		t.Fatalf("expected 2 records, got %d", len(records))
+	}
+	if records[1].RData.Text != "heritage=test" {
+		t.Errorf("expected TXT text heritage=test, got %s", records[1].RData.Text)
+	}
+}
+
+func TestUpdateARecord_Success(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/zones/records/update" {
+			t.Errorf("unexpected path: %s", r.URL.Path)
+		}
+
+		parseForm(t, r)
+		query := r.PostForm
+		if query.Get("ipAddress") != "10.0.0.1" {
+			t.Errorf("unexpected ipAddress: %s", query.Get("ipAddress"))
+		}
+		if query.Get("newIpAddress") != "10.0.0.2" {
+			t.Errorf("unexpected newIpAddress: %s", query.Get("newIpAddress"))
+		}
+		if query.Get("ttl") != "600" {
+			t.Errorf("unexpected ttl: %s", query.Get("ttl"))
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"status": "ok",
+		})
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test-token")
+	err := client.UpdateARecord(context.Background(), "example.com", "test.example.com", "10.0.0.1", "10.0.0.2", 600)
+
+	if err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestAddAAAARecord_Success(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/zones/records/add" {
+			t.Errorf("unexpected path: %s", r.URL.Path)
+		}
+
+		parseForm(t, r)
+		query := r.PostForm
+		if query.Get("type") != "AAAA" {
+			t.Errorf("unexpected type: %s", query.Get("type"))
+		}
+		if query.Get("ipAddress") != "2001:db8::1" {
+			t.Errorf("unexpected ipAddress: %s", query.Get("ipAddress"))
+		}
+		if query.Get("ttl") != "300" {
+			t.Errorf("unexpected ttl: %s", query.Get("ttl"))
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"status": "ok",
+		})
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test-token")
+	err := client.AddAAAARecord(context.Background(), "example.com", "test.example.com", "2001:db8::1", 300)
+
+	if err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestDeleteAAAARecord_Success(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/zones/records/delete" {
+			t.Errorf("unexpected path: %s", r.URL.Path)
+		}
+
+		parseForm(t, r)
+		query := r.PostForm
+		if query.Get("type") != "AAAA" {
+			t.Errorf("unexpected type: %s", query.Get("type"))
+		}
+		if query.Get("ipAddress") != "2001:db8::1" {
+			t.Errorf("unexpected ipAddress: %s", query.Get("ipAddress"))
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"status": "ok",
+		})
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test-token")
+	err := client.DeleteAAAARecord(context.Background(), "example.com", "test.example.com", "2001:db8::1")
+
+	if err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestHasAAAARecord_NonCanonicalAddress(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"status": "ok",
+			"response": map[string]interface{}{
+				"zone": mockZoneInfo("example.com"),
+				"name": "test.example.com",
+				"records": []map[string]interface{}{
+					{
+						"name": "test.example.com",
+						"type": "A",
+						"ttl":  300,
+						"rData": map[string]interface{}{
+							"ipAddress": "10.0.0.1",
+						},
+					},
+					{
+						"name": "test.example.com",
+						"type": "AAAA",
+						"ttl":  300,
+						"rData": map[string]interface{}{
+							"ipAddress": "2001:0DB8:0000::0001",
+						},
+					},
+				},
+			},
+		})
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test-token")
+	exists, err := client.HasAAAARecord(context.Background(), "example.com", "test.example.com", "2001:db8::1")
+
+	if err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if !exists {
+		t.Error("expected AAAA record to match regardless of address formatting")
+	}
+}
+
+func TestAddCNAMERecord_Success(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/zones/records/add" {
+			t.Errorf("unexpected path: %s", r.URL.Path)
+		}
+
+		parseForm(t, r)
+		query := r.PostForm
+		if query.Get("type") != "CNAME" {
+			t.Errorf("unexpected type: %s", query.Get("type"))
+		}
+		if query.Get("cname") != "traefik.example.com" {
+			t.Errorf("unexpected cname: %s", query.Get("cname"))
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"status": "ok",
+		})
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test-token")
+	err := client.AddCNAMERecord(context.Background(), "example.com", "app.example.com", "traefik.example.com", 300)
+
+	if err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestGetCNAMERecord_Exists(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"status": "ok",
+			"response": map[string]interface{}{
+				"zone": mockZoneInfo("example.com"),
+				"name": "app.example.com",
+				"records": []map[string]interface{}{
+					{
+						"name": "app.example.com",
+						"type": "CNAME",
+						"ttl":  300,
+						"rData": map[string]interface{}{
+							"cname": "Traefik.Example.com.",
+						},
+					},
+				},
+			},
+		})
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test-token")
+	target, exists, err := client.GetCNAMERecord(context.Background(), "example.com", "app.example.com")
+
+	if err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if !exists {
+		t.Fatal("expected CNAME record to exist")
+	}
+	if target != "traefik.example.com" {
+		t.Errorf("expected canonical target traefik.example.com, got %s", target)
+	}
+}
@@ -0,0 +1,112 @@
+package technitium
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// Errors returned by the client are classified under these sentinels so callers can
+// react to the kind of failure with errors.Is rather than by matching messages.
+var (
+	// ErrUnauthorized is returned when Technitium rejects the token or credentials,
+	// or the user lacks permission for the request.
+	ErrUnauthorized = errors.New("unauthorized")
+	// ErrZoneNotFound is returned when the zone of a request does not exist.
+	ErrZoneNotFound = errors.New("zone not found")
+	// ErrRecordConflict is returned when a record cannot be added because it already
+	// exists or clashes with another record at the name, such as a CNAME.
+	ErrRecordConflict = errors.New("record conflict")
+	// ErrRateLimited is returned when the server or a proxy in front of it answers
+	// 429 Too Many Requests. It is also transient.
+	ErrRateLimited = errors.New("rate limited")
+	// ErrTransient is returned for failures that may succeed when retried, such as
+	// network errors, timeouts and 5xx responses, once the retries are used up.
+	ErrTransient = errors.New("transient failure")
+)
+
+// APIError is a request the server answered with an error, either through the status
+// of its JSON response or with an HTTP status other than 200 OK.
+type APIError struct {
+	// Endpoint is the API path of the request.
+	Endpoint string
+	// StatusCode is the HTTP status code of the response.
+	StatusCode int
+	// Status is the status field of the JSON response, e.g. "error" or
+	// "invalid-token". It is empty when the HTTP status code reports the failure.
+	Status string
+	// Message is the error message of the response, or its body when the HTTP status
+	// code reports the failure.
+	Message string
+
+	// kind is the sentinel the error is classified under, if any.
+	kind error
+}
+
+func (e *APIError) Error() string {
+	switch e.Status {
+	case "":
+		return fmt.Sprintf("unexpected status code %d: %s", e.StatusCode, e.Message)
+	case "invalid-token":
+		return fmt.Sprintf("invalid token or session expired: %s", e.Message)
+	default:
+		return fmt.Sprintf("API error: %s", e.Message)
+	}
+}
+
+// Unwrap returns the sentinel the error is classified under, so errors.Is matches
+// ErrUnauthorized, ErrZoneNotFound, ErrRecordConflict or ErrRateLimited.
+func (e *APIError) Unwrap() error { return e.kind }
+
+// sessionExpired reports whether Technitium rejected the token, e.g. because the
+// session it belongs to expired.
+func (e *APIError) sessionExpired() bool {
+	return e.Status == "invalid-token"
+}
+
+// newStatusError returns the error for a response with a status code other than 200 OK.
+func newStatusError(endpoint string, code int, body string) *APIError {
+	e := &APIError{Endpoint: endpoint, StatusCode: code, Message: body}
+	switch code {
+	case http.StatusUnauthorized, http.StatusForbidden:
+		e.kind = ErrUnauthorized
+	case http.StatusTooManyRequests:
+		e.kind = ErrRateLimited
+	}
+	return e
+}
+
+// newResponseError returns the error for a JSON response whose status is not "ok".
+func newResponseError(endpoint, status, message string) *APIError {
+	e := &APIError{Endpoint: endpoint, StatusCode: http.StatusOK, Status: status, Message: message}
+	switch status {
+	case "invalid-token":
+		e.kind = ErrUnauthorized
+	case "2fa-required":
+		e.Message = "two-factor authentication is required; use an API token instead"
+		e.kind = ErrUnauthorized
+	default:
+		e.kind = classifyMessage(message)
+	}
+	return e
+}
+
+// classifyMessage maps the error messages of Technitium's API, which reports every
+// failure with the status "error", to a sentinel.
+func classifyMessage(message string) error {
+	msg := strings.ToLower(message)
+	switch {
+	case strings.Contains(msg, "invalid username or password"),
+		strings.Contains(msg, "access was denied"):
+		return ErrUnauthorized
+	case strings.Contains(msg, "no such zone"),
+		strings.Contains(msg, "zone does not exist"),
+		strings.Contains(msg, "zone was not found"):
+		return ErrZoneNotFound
+	case strings.Contains(msg, "already exists"):
+		return ErrRecordConflict
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,71 @@
+package technitium
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestErrorClassification(t *testing.T) {
+	sentinels := []error{ErrUnauthorized, ErrZoneNotFound, ErrRecordConflict, ErrRateLimited, ErrTransient}
+
+	tests := []struct {
+		name     string
+		code     int
+		status   string
+		message  string
+		expected []error
+	}{
+		{"invalid token", http.StatusOK, "invalid-token", "Invalid token or session expired.", []error{ErrUnauthorized}},
+		{"two-factor", http.StatusOK, "2fa-required", "", []error{ErrUnauthorized}},
+		{"access denied", http.StatusOK, "error", "Access was denied.", []error{ErrUnauthorized}},
+		{"forbidden", http.StatusForbidden, "", "forbidden", []error{ErrUnauthorized}},
+		{"no such zone", http.StatusOK, "error", "No such zone was found: example.com", []error{ErrZoneNotFound}},
+		{"zone does not exist", http.StatusOK, "error", "Zone does not exist", []error{ErrZoneNotFound}},
+		{"record exists", http.StatusOK, "error", "Cannot add record: record already exists.", []error{ErrRecordConflict}},
+		{"rate limited", http.StatusTooManyRequests, "", "slow down", []error{ErrRateLimited, ErrTransient}},
+		{"server error", http.StatusBadGateway, "", "bad gateway", []error{ErrTransient}},
+		{"unclassified", http.StatusOK, "error", "Invalid IP address.", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if tt.status == "" {
+					http.Error(w, tt.message, tt.code)
+					return
+				}
+				w.Header().Set("Content-Type", "application/json")
+				json.NewEncoder(w).Encode(map[string]interface{}{"status": tt.status, "errorMessage": tt.message})
+			}))
+			defer server.Close()
+
+			client := NewClient(server.URL, "test-token", WithRetryPolicy(fastRetry))
+			_, err := client.GetRecords(context.Background(), "example.com", "app.example.com")
+			if err == nil {
+				t.Fatal("expected error")
+			}
+
+			for _, sentinel := range sentinels {
+				want := false
+				for _, e := range tt.expected {
+					want = want || e == sentinel
+				}
+				if got := errors.Is(err, sentinel); got != want {
+					t.Errorf("errors.Is(%v, %v) = %v, want %v", err, sentinel, got, want)
+				}
+			}
+
+			var apiErr *APIError
+			if !errors.As(err, &apiErr) {
+				t.Fatalf("expected an *APIError, got %T", err)
+			}
+			if apiErr.Endpoint != "/api/zones/records/get" || apiErr.StatusCode != tt.code || apiErr.Status != tt.status {
+				t.Errorf("unexpected API error fields: %+v", apiErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,94 @@
+package technitium
+
+import (
+	"context"
+	"time"
+
+	"golang.org/x/time/rate"
+
+	"github.com/maxfield-allison/technitium-companion/internal/metrics"
+)
+
+// WithRateLimit limits requests to perSecond on average with bursts of up to burst
+// requests, using a token bucket. A rate of 0 or less disables the limit, which is
+// the default.
+func WithRateLimit(perSecond float64, burst int) ClientOption {
+	return func(c *Client) {
+		c.rateLimit = perSecond
+		c.rateBurst = burst
+	}
+}
+
+// WithMaxInFlight caps the number of requests awaiting a response at n; further
+// requests wait for one to finish. A value of 0 or less disables the cap, which is
+// the default.
+func WithMaxInFlight(n int) ClientOption {
+	return func(c *Client) {
+		c.maxInFlight = n
+	}
+}
+
+// limiter throttles the requests sent to a server, so reconciling many workloads at
+// once does not overwhelm a small Technitium instance. Time spent waiting and the
+// number of queued and in-flight requests are published as metrics.
+type limiter struct {
+	server string
+	rate   *rate.Limiter // nil without a rate limit
+	slots  chan struct{} // nil without a cap on requests in flight
+}
+
+// newLimiter creates a limiter for the named server.
+func newLimiter(server string, perSecond float64, burst, maxInFlight int) *limiter {
+	l := &limiter{server: server}
+	if perSecond > 0 {
+		l.rate = rate.NewLimiter(rate.Limit(perSecond), max(burst, 1))
+	}
+	if maxInFlight > 0 {
+		l.slots = make(chan struct{}, maxInFlight)
+	}
+	return l
+}
+
+// acquire waits until a request may be sent and returns a function to call once its
+// response has been read. It fails if ctx ends first, or if the rate limit would
+// delay the request past the context deadline.
+func (l *limiter) acquire(ctx context.Context) (func(), error) {
+	start := time.Now()
+	metrics.AddAPIRequestsQueued(l.server, 1)
+	err := l.wait(ctx)
+	metrics.AddAPIRequestsQueued(l.server, -1)
+	metrics.RecordAPIWait(l.server, time.Since(start).Seconds())
+	if err != nil {
+		return nil, err
+	}
+
+	metrics.AddAPIRequestsInFlight(l.server, 1)
+	return func() {
+		metrics.AddAPIRequestsInFlight(l.server, -1)
+		if l.slots != nil {
+			<-l.slots
+		}
+	}, nil
+}
+
+// wait takes an in-flight slot, then a rate limit token.
+func (l *limiter) wait(ctx context.Context) error {
+	if l.slots != nil {
+		select {
+		case l.slots <- struct{}{}:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	if l.rate != nil {
+		if err := l.rate.Wait(ctx); err != nil {
+			if l.slots != nil {
+				<-l.slots
+			}
+			return err
+		}
+	}
+
+	return nil
+}
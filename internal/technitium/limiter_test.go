@@ -0,0 +1,124 @@
+package technitium
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// newRecordsServer starts a fake Technitium server answering every request with an
+// empty record list, after running handle if it is set.
+func newRecordsServer(t *testing.T, handle func()) *httptest.Server {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if handle != nil {
+			handle()
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"status":   "ok",
+			"response": map[string]interface{}{"zone": mockZoneInfo("example.com"), "records": []interface{}{}},
+		})
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestWithRateLimit(t *testing.T) {
+	server := newRecordsServer(t, nil)
+
+	// A burst of 2 at 20 requests per second: the last 3 of 5 requests wait 50ms each
+	client := NewClient(server.URL, "test-token", WithRateLimit(20, 2))
+
+	start := time.Now()
+	for i := 0; i < 5; i++ {
+		if _, err := client.GetRecords(context.Background(), "example.com", "app.example.com"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed < 140*time.Millisecond {
+		t.Errorf("expected requests to be spread out by the rate limit, took %v", elapsed)
+	}
+}
+
+func TestWithRateLimit_ContextDeadline(t *testing.T) {
+	server := newRecordsServer(t, nil)
+	client := NewClient(server.URL, "test-token", WithRateLimit(0.1, 1))
+
+	if _, err := client.GetRecords(context.Background(), "example.com", "app.example.com"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// The next token is 10s away, past the deadline, so the request fails without waiting
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	start := time.Now()
+	if _, err := client.GetRecords(ctx, "example.com", "app.example.com"); err == nil {
+		t.Error("expected error when the rate limit exceeds the deadline")
+	}
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("expected request to fail immediately, took %v", elapsed)
+	}
+}
+
+func TestWithMaxInFlight(t *testing.T) {
+	var inFlight, peak atomic.Int32
+	server := newRecordsServer(t, func() {
+		n := inFlight.Add(1)
+		for {
+			p := peak.Load()
+			if n <= p || peak.CompareAndSwap(p, n) {
+				break
+			}
+		}
+		time.Sleep(20 * time.Millisecond)
+		inFlight.Add(-1)
+	})
+
+	client := NewClient(server.URL, "test-token", WithMaxInFlight(2))
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := client.GetRecords(context.Background(), "example.com", "app.example.com"); err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if p := peak.Load(); p > 2 {
+		t.Errorf("expected at most 2 requests in flight, saw %d", p)
+	}
+}
+
+func TestWithMaxInFlight_CanceledWhileQueued(t *testing.T) {
+	l := newLimiter("test", 0, 0, 1)
+
+	release, err := l.acquire(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := l.acquire(ctx); err == nil {
+		t.Error("expected a queued request to fail once its context is canceled")
+	}
+
+	// Releasing the slot lets the next request through
+	release()
+	release, err = l.acquire(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	release()
+}
@@ -0,0 +1,104 @@
+package technitium
+
+import (
+	"net/url"
+	"strings"
+	"time"
+)
+
+// provenanceHeritage marks record comments and TXT records written by technitium-companion.
+const provenanceHeritage = "heritage=technitium-companion"
+
+// Provenance describes which workload a record was written for. It is stored in the
+// record's comments, and optionally in a TXT record, as comma-separated key=value
+// pairs so it stays readable in the Technitium UI.
+type Provenance struct {
+	Owner        string
+	Workload     string
+	WorkloadType string
+	Stack        string
+	Router       string
+	Host         string // Docker host the workload runs on, when there are several
+	Created      time.Time
+}
+
+// String formats the provenance as it is written to Technitium, omitting empty fields.
+func (p Provenance) String() string {
+	parts := []string{provenanceHeritage}
+	for _, field := range []struct{ key, value string }{
+		{"owner", p.Owner},
+		{"workload", p.Workload},
+		{"type", p.WorkloadType},
+		{"stack", p.Stack},
+		{"router", p.Router},
+		{"host", p.Host},
+	} {
+		if field.value != "" {
+			parts = append(parts, field.key+"="+field.value)
+		}
+	}
+	if !p.Created.IsZero() {
+		parts = append(parts, "created="+p.Created.UTC().Format(time.RFC3339))
+	}
+	return strings.Join(parts, ",")
+}
+
+// ParseProvenance reads provenance written by String. Returns false if s was not
+// written by technitium-companion or names no workload, which also rejects the
+// bare heritage of ownership records.
+func ParseProvenance(s string) (Provenance, bool) {
+	rest, ok := strings.CutPrefix(strings.TrimSpace(s), provenanceHeritage)
+	if !ok || (rest != "" && rest[0] != ',') {
+		return Provenance{}, false
+	}
+
+	var p Provenance
+	for _, part := range strings.Split(rest, ",") {
+		key, value, _ := strings.Cut(part, "=")
+		switch key {
+		case "owner":
+			p.Owner = value
+		case "workload":
+			p.Workload = value
+		case "type":
+			p.WorkloadType = value
+		case "stack":
+			p.Stack = value
+		case "router":
+			p.Router = value
+		case "host":
+			p.Host = value
+		case "created":
+			if t, err := time.Parse(time.RFC3339, value); err == nil {
+				p.Created = t
+			}
+		}
+	}
+
+	if p.Workload == "" {
+		return Provenance{}, false
+	}
+	return p, true
+}
+
+// Provenance returns the provenance stored in the record's comments.
+// Returns false if the record was not written by technitium-companion.
+func (r Record) Provenance() (Provenance, bool) {
+	return ParseProvenance(r.Comments)
+}
+
+// RecordOption sets optional parameters on a record write.
+type RecordOption func(url.Values)
+
+// WithProvenance stores p in the comments of the record being added or updated.
+func WithProvenance(p Provenance) RecordOption {
+	return WithComments(p.String())
+}
+
+// WithComments sets the comments of the record being added or updated, e.g. to keep
+// those of a record that was not written by technitium-companion.
+func WithComments(comments string) RecordOption {
+	return func(params url.Values) {
+		params.Set("comments", comments)
+	}
+}
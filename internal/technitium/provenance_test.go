@@ -0,0 +1,104 @@
+package technitium
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestProvenance_RoundTrip(t *testing.T) {
+	p := Provenance{
+		Owner:        "default",
+		Workload:     "media_jellyfin",
+		WorkloadType: "service",
+		Stack:        "media",
+		Router:       "jellyfin",
+		Host:         "edge1",
+		Created:      time.Date(2026, 1, 2, 3, 4, 5, 0, time.FixedZone("CET", 3600)),
+	}
+
+	s := p.String()
+	expected := "heritage=technitium-companion,owner=default,workload=media_jellyfin,type=service,stack=media,router=jellyfin,host=edge1,created=2026-01-02T02:04:05Z"
+	if s != expected {
+		t.Errorf("String() = %q, want %q", s, expected)
+	}
+
+	parsed, ok := ParseProvenance(s)
+	if !ok {
+		t.Fatalf("expected %q to parse", s)
+	}
+	if parsed.Owner != p.Owner || parsed.Workload != p.Workload || parsed.WorkloadType != p.WorkloadType ||
+		parsed.Stack != p.Stack || parsed.Router != p.Router || parsed.Host != p.Host || !parsed.Created.Equal(p.Created) {
+		t.Errorf("round trip = %+v, want %+v", parsed, p)
+	}
+}
+
+func TestProvenance_OmitsEmptyFields(t *testing.T) {
+	s := Provenance{Workload: "app"}.String()
+	if s != "heritage=technitium-companion,workload=app" {
+		t.Errorf("unexpected provenance: %q", s)
+	}
+}
+
+func TestParseProvenance_Rejects(t *testing.T) {
+	for _, s := range []string{
+		"",
+		"added by hand",
+		"heritage=technitium-companion,owner=default",
+		"heritage=technitium-companion-fork,workload=app",
+		"heritage=other,workload=app",
+	} {
+		if p, ok := ParseProvenance(s); ok {
+			t.Errorf("expected %q to be rejected, got %+v", s, p)
+		}
+	}
+}
+
+func TestRecordProvenance(t *testing.T) {
+	rec := Record{Name: "app.example.com", Type: "A", Comments: "heritage=technitium-companion,owner=default,workload=app"}
+
+	p, ok := rec.Provenance()
+	if !ok || p.Workload != "app" || p.Owner != "default" {
+		t.Errorf("unexpected provenance: %+v, %v", p, ok)
+	}
+}
+
+func TestWithProvenance(t *testing.T) {
+	var comments []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		parseForm(t, r)
+		comments = append(comments, r.PostForm.Get("comments"))
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"status": "ok",
+		})
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test-token")
+	p := Provenance{Workload: "app", Router: "app"}
+
+	if err := client.AddARecord(context.Background(), "example.com", "app.example.com", "10.0.0.1", 300, WithProvenance(p)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := client.UpdateCNAMERecord(context.Background(), "example.com", "www.example.com", "app.example.com", 300, WithProvenance(p)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := client.AddARecord(context.Background(), "example.com", "plain.example.com", "10.0.0.1", 300); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := []string{p.String(), p.String(), ""}
+	if len(comments) != len(expected) {
+		t.Fatalf("expected %d requests, got %d", len(expected), len(comments))
+	}
+	for i := range expected {
+		if comments[i] != expected[i] {
+			t.Errorf("request %d comments = %q, want %q", i, comments[i], expected[i])
+		}
+	}
+}
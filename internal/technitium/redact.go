@@ -0,0 +1,56 @@
+package technitium
+
+import (
+	"net/url"
+	"strings"
+)
+
+// redactedPlaceholder replaces secrets in error messages.
+const redactedPlaceholder = "[REDACTED]"
+
+// redactedError hides secrets in the message of the error it wraps. errors.Is and
+// errors.As still reach the original error.
+type redactedError struct {
+	err     error
+	secrets []string
+}
+
+func (e *redactedError) Error() string { return redact(e.err.Error(), e.secrets) }
+func (e *redactedError) Unwrap() error { return e.err }
+
+// redactError returns err with the API token, the password and any extra secrets
+// removed from its message, in both raw and URL-encoded form, so it can be safely
+// logged or shown in health output.
+func (c *Client) redactError(err error, extra ...string) error {
+	c.authMu.Lock()
+	defer c.authMu.Unlock()
+	return c.redactErrorLocked(err, extra...)
+}
+
+// redactErrorLocked is redactError for callers already holding c.authMu.
+func (c *Client) redactErrorLocked(err error, extra ...string) error {
+	if err == nil {
+		return nil
+	}
+	var secrets []string
+	for _, secret := range append([]string{c.token, c.password}, extra...) {
+		if secret != "" {
+			secrets = append(secrets, secret, url.QueryEscape(secret))
+		}
+	}
+	msg := err.Error()
+	if redact(msg, secrets) == msg {
+		return err
+	}
+	return &redactedError{err: err, secrets: secrets}
+}
+
+// redact replaces every occurrence of the secrets in s.
+func redact(s string, secrets []string) string {
+	for _, secret := range secrets {
+		if secret != "" {
+			s = strings.ReplaceAll(s, secret, redactedPlaceholder)
+		}
+	}
+	return s
+}
@@ -0,0 +1,92 @@
+package technitium
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+const secretToken = "s3cret+token/value"
+
+// parseForm parses the form of a request to a test server, failing the test if it is
+// malformed or a secret was sent in the query string, where proxies would log it.
+func parseForm(t *testing.T, r *http.Request) {
+	t.Helper()
+	if err := r.ParseForm(); err != nil {
+		t.Errorf("parsing form: %v", err)
+	}
+	query := r.URL.Query()
+	for _, secret := range []string{"token", "pass"} {
+		if query.Has(secret) {
+			t.Errorf("expected no %s in query string, got %q", secret, r.URL.RawQuery)
+		}
+	}
+}
+
+func TestDoRequest_SendsTokenInPOSTBody(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			t.Errorf("expected POST, got %s", r.Method)
+		}
+		if r.URL.RawQuery != "" {
+			t.Errorf("expected no query string, got %q", r.URL.RawQuery)
+		}
+		if err := r.ParseForm(); err != nil {
+			t.Errorf("parsing form: %v", err)
+		}
+		if r.PostForm.Get("token") != secretToken {
+			t.Errorf("expected token in form body, got %q", r.PostForm.Get("token"))
+		}
+		w.Write([]byte(`{"status":"ok"}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, secretToken)
+	if err := client.AddARecord(context.Background(), "example.com", "app.example.com", "10.0.0.1", 300); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestDoRequest_RedactsTokenFromErrors(t *testing.T) {
+	// A misbehaving proxy echoing the request body back in its error page
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte("bad request: " + string(body) + " raw=" + secretToken))
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, secretToken)
+	_, err := client.GetRecords(context.Background(), "example.com", "app.example.com")
+	if err == nil {
+		t.Fatal("expected error")
+	}
+	if strings.Contains(err.Error(), "s3cret") {
+		t.Errorf("expected token to be redacted, got %v", err)
+	}
+	if !strings.Contains(err.Error(), redactedPlaceholder) {
+		t.Errorf("expected redaction placeholder in %v", err)
+	}
+}
+
+func TestRedactError_PreservesChain(t *testing.T) {
+	client := NewClient("http://localhost:5380", secretToken)
+	cause := &transientError{err: errors.New("timeout contacting ?token=" + secretToken)}
+
+	err := client.redactError(cause)
+	if strings.Contains(err.Error(), secretToken) {
+		t.Errorf("expected token to be redacted, got %v", err)
+	}
+	if !isTransient(err) {
+		t.Error("expected redacted error to remain transient")
+	}
+
+	plain := errors.New("no secrets here")
+	if client.redactError(plain) != plain {
+		t.Error("expected error without the token to be returned unchanged")
+	}
+}
@@ -0,0 +1,69 @@
+package technitium
+
+import (
+	"errors"
+	"math/rand/v2"
+	"net"
+	"time"
+)
+
+// RetryPolicy controls how requests that fail with a transient error are retried.
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts per request, including the first.
+	// Values below 2 disable retries.
+	MaxAttempts int
+	// BaseDelay is the delay before the first retry; it doubles with every further attempt.
+	BaseDelay time.Duration
+	// MaxDelay caps the delay between attempts.
+	MaxDelay time.Duration
+}
+
+// backoff returns the delay before the given retry, counting from 1. The exponential
+// delay is jittered to between half and all of its value so that retries from several
+// callers do not arrive in lockstep.
+func (p RetryPolicy) backoff(retry int) time.Duration {
+	delay := p.BaseDelay
+	for i := 1; i < retry && (p.MaxDelay <= 0 || delay < p.MaxDelay); i++ {
+		delay *= 2
+	}
+	if p.MaxDelay > 0 && delay > p.MaxDelay {
+		delay = p.MaxDelay
+	}
+	if delay <= 0 {
+		return 0
+	}
+	half := delay / 2
+	return half + rand.N(delay-half+1)
+}
+
+// transientError marks a failure that may succeed when retried, such as a network
+// error, a timeout or a 5xx response.
+type transientError struct {
+	err error
+}
+
+func (e *transientError) Error() string { return e.err.Error() }
+func (e *transientError) Unwrap() error { return e.err }
+
+// Is classifies the error as ErrTransient.
+func (e *transientError) Is(target error) bool { return target == ErrTransient }
+
+// isTransient reports whether err is a failure worth retrying.
+func isTransient(err error) bool {
+	var t *transientError
+	return errors.As(err, &t)
+}
+
+// retryable reports whether a failed request may be sent again. Requests that are not
+// idempotent are only retried when the connection could not be established, since
+// the server cannot have acted on them.
+func retryable(err error, idempotent bool) bool {
+	if !isTransient(err) {
+		return false
+	}
+	if idempotent {
+		return true
+	}
+	var opErr *net.OpError
+	return errors.As(err, &opErr) && opErr.Op == "dial"
+}
@@ -0,0 +1,147 @@
+package technitium
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// fastRetry retries without noticeable delay so tests stay quick.
+var fastRetry = RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond, MaxDelay: 2 * time.Millisecond}
+
+func TestRetryPolicy_Backoff(t *testing.T) {
+	p := RetryPolicy{BaseDelay: 100 * time.Millisecond, MaxDelay: time.Second}
+
+	tests := []struct {
+		retry    int
+		min, max time.Duration
+	}{
+		{1, 50 * time.Millisecond, 100 * time.Millisecond},
+		{2, 100 * time.Millisecond, 200 * time.Millisecond},
+		{3, 200 * time.Millisecond, 400 * time.Millisecond},
+		{10, 500 * time.Millisecond, time.Second},
+	}
+
+	for _, tt := range tests {
+		for i := 0; i < 20; i++ {
+			if d := p.backoff(tt.retry); d < tt.min || d > tt.max {
+				t.Fatalf("backoff(%d) = %v, want between %v and %v", tt.retry, d, tt.min, tt.max)
+			}
+		}
+	}
+
+	if d := (RetryPolicy{}).backoff(1); d != 0 {
+		t.Errorf("expected no delay without a base delay, got %v", d)
+	}
+}
+
+func TestDoRequest_RetriesTransientFailures(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"status":   "ok",
+			"response": map[string]interface{}{"zone": mockZoneInfo("example.com"), "records": []interface{}{}},
+		})
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test-token", WithRetryPolicy(fastRetry))
+	if _, err := client.GetRecords(context.Background(), "example.com", "app.example.com"); err != nil {
+		t.Fatalf("expected success after retries, got %v", err)
+	}
+	if n := calls.Load(); n != 3 {
+		t.Errorf("expected 3 attempts, got %d", n)
+	}
+}
+
+func TestDoRequest_GivesUpAfterMaxAttempts(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test-token", WithRetryPolicy(fastRetry))
+	if _, err := client.GetRecords(context.Background(), "example.com", "app.example.com"); err == nil {
+		t.Fatal("expected error")
+	}
+	if n := calls.Load(); n != 3 {
+		t.Errorf("expected 3 attempts, got %d", n)
+	}
+}
+
+func TestDoRequest_DoesNotRetryAPIErrors(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		json.NewEncoder(w).Encode(map[string]interface{}{"status": "error", "errorMessage": "No such zone"})
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test-token", WithRetryPolicy(fastRetry))
+	if _, err := client.GetRecords(context.Background(), "example.com", "app.example.com"); err == nil {
+		t.Fatal("expected error")
+	}
+	if n := calls.Load(); n != 1 {
+		t.Errorf("expected 1 attempt, got %d", n)
+	}
+}
+
+func TestDoRequest_DoesNotRetryWritesAfterSending(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test-token", WithRetryPolicy(fastRetry))
+	writes := map[string]func() error{
+		"add": func() error {
+			return client.AddARecord(context.Background(), "example.com", "app.example.com", "10.0.0.1", 300)
+		},
+		"update": func() error {
+			return client.UpdateARecord(context.Background(), "example.com", "app.example.com", "10.0.0.1", "10.0.0.2", 300)
+		},
+		"delete": func() error {
+			return client.DeleteARecord(context.Background(), "example.com", "app.example.com", "10.0.0.1")
+		},
+	}
+	for name, write := range writes {
+		calls.Store(0)
+		if err := write(); err == nil {
+			t.Fatalf("%s: expected error", name)
+		}
+		if n := calls.Load(); n != 1 {
+			t.Errorf("expected %s to be sent once, got %d attempts", name, n)
+		}
+	}
+}
+
+func TestRetryable(t *testing.T) {
+	dialErr := &transientError{err: &net.OpError{Op: "dial", Err: &net.DNSError{Err: "refused"}}}
+	readErr := &transientError{err: &net.OpError{Op: "read", Err: &net.DNSError{Err: "reset"}}}
+
+	if !retryable(dialErr, false) {
+		t.Error("expected a dial failure to be retryable for non-idempotent requests")
+	}
+	if retryable(readErr, false) {
+		t.Error("expected a read failure not to be retryable for non-idempotent requests")
+	}
+	if !retryable(readErr, true) {
+		t.Error("expected a read failure to be retryable for idempotent requests")
+	}
+	if retryable(context.Canceled, true) {
+		t.Error("expected a non-transient error not to be retryable")
+	}
+}
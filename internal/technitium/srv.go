@@ -0,0 +1,141 @@
+package technitium
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// SRV is the data of an SRV record.
+type SRV struct {
+	Priority uint16
+	Weight   uint16
+	Port     uint16
+	Target   string
+}
+
+// String formats the record data as in a zone file, "priority weight port target",
+// with the target in canonical form.
+func (s SRV) String() string {
+	return fmt.Sprintf("%d %d %d %s", s.Priority, s.Weight, s.Port, CanonicalName(s.Target))
+}
+
+// ParseSRV parses SRV record data in the form written by String.
+func ParseSRV(s string) (SRV, error) {
+	fields := strings.Fields(s)
+	if len(fields) != 4 {
+		return SRV{}, fmt.Errorf("SRV data must be \"priority weight port target\": %q", s)
+	}
+
+	var nums [3]uint16
+	for i, name := range []string{"priority", "weight", "port"} {
+		n, err := strconv.ParseUint(fields[i], 10, 16)
+		if err != nil {
+			return SRV{}, fmt.Errorf("SRV %s must be between 0 and 65535: %s", name, fields[i])
+		}
+		nums[i] = uint16(n)
+	}
+
+	target := CanonicalName(fields[3])
+	if target == "" {
+		return SRV{}, fmt.Errorf("SRV target is empty: %q", s)
+	}
+
+	return SRV{Priority: nums[0], Weight: nums[1], Port: nums[2], Target: target}, nil
+}
+
+// srvData returns the API parameters identifying srv, plus the new* parameters
+// replacing it with newSRV for an update.
+func srvData(srv SRV, newSRV *SRV) url.Values {
+	data := url.Values{}
+	data.Set("priority", strconv.Itoa(int(srv.Priority)))
+	data.Set("weight", strconv.Itoa(int(srv.Weight)))
+	data.Set("port", strconv.Itoa(int(srv.Port)))
+	data.Set("target", srv.Target)
+	if newSRV != nil {
+		data.Set("newPriority", strconv.Itoa(int(newSRV.Priority)))
+		data.Set("newWeight", strconv.Itoa(int(newSRV.Weight)))
+		data.Set("newPort", strconv.Itoa(int(newSRV.Port)))
+		data.Set("newTarget", newSRV.Target)
+	}
+	return data
+}
+
+// AddSRVRecord creates an SRV record at a service name such as _minecraft._tcp.example.com.
+func (c *Client) AddSRVRecord(ctx context.Context, zone, name string, srv SRV, ttl int, opts ...RecordOption) error {
+	if err := c.addRecord(ctx, zone, name, "SRV", ttl, srvData(srv, nil), opts...); err != nil {
+		return err
+	}
+
+	c.logger.Info("added SRV record",
+		slog.String("name", name),
+		slog.String("srv", srv.String()),
+		slog.String("zone", zone),
+		slog.Int("ttl", ttl),
+	)
+
+	return nil
+}
+
+// DeleteSRVRecord removes the SRV record with the given data from name.
+func (c *Client) DeleteSRVRecord(ctx context.Context, zone, name string, srv SRV) error {
+	if err := c.deleteRecord(ctx, zone, name, "SRV", srvData(srv, nil)); err != nil {
+		return err
+	}
+
+	c.logger.Info("deleted SRV record",
+		slog.String("name", name),
+		slog.String("srv", srv.String()),
+		slog.String("zone", zone),
+	)
+
+	return nil
+}
+
+// UpdateSRVRecord replaces an existing SRV record's data and TTL in a single call.
+// Pass the same value for srv and newSRV to change only the TTL.
+func (c *Client) UpdateSRVRecord(ctx context.Context, zone, name string, srv, newSRV SRV, ttl int, opts ...RecordOption) error {
+	if err := c.updateRecord(ctx, zone, name, "SRV", ttl, srvData(srv, &newSRV), opts...); err != nil {
+		return err
+	}
+
+	c.logger.Info("updated SRV record",
+		slog.String("name", name),
+		slog.String("srv", srv.String()),
+		slog.String("new_srv", newSRV.String()),
+		slog.String("zone", zone),
+		slog.Int("ttl", ttl),
+	)
+
+	return nil
+}
+
+// GetSRVRecords returns the data of every SRV record at name.
+func (c *Client) GetSRVRecords(ctx context.Context, zone, name string) ([]SRV, error) {
+	records, err := c.GetRecords(ctx, zone, name)
+	if err != nil {
+		return nil, err
+	}
+
+	var out []SRV
+	for _, r := range records {
+		if r.Type == "SRV" {
+			out = append(out, r.RData.SRV())
+		}
+	}
+
+	return out, nil
+}
+
+// SRV returns the data of an SRV record.
+func (d RData) SRV() SRV {
+	return SRV{
+		Priority: uint16(d.Priority),
+		Weight:   uint16(d.Weight),
+		Port:     uint16(d.Port),
+		Target:   CanonicalName(d.Target),
+	}
+}
@@ -0,0 +1,148 @@
+package technitium
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestParseSRV(t *testing.T) {
+	srv, err := ParseSRV(" 0  5 25565 MC.Example.com. ")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if srv != (SRV{Priority: 0, Weight: 5, Port: 25565, Target: "mc.example.com"}) {
+		t.Errorf("unexpected SRV: %+v", srv)
+	}
+	if s := srv.String(); s != "0 5 25565 mc.example.com" {
+		t.Errorf("String() = %q", s)
+	}
+
+	for _, s := range []string{
+		"",
+		"0 5 25565",
+		"0 5 65536 mc.example.com",
+		"-1 5 25565 mc.example.com",
+		"0 five 25565 mc.example.com",
+		"0 5 25565 mc.example.com extra",
+	} {
+		if _, err := ParseSRV(s); err == nil {
+			t.Errorf("expected error for %q", s)
+		}
+	}
+}
+
+func TestAddSRVRecord_Success(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/zones/records/add" {
+			t.Errorf("unexpected path: %s", r.URL.Path)
+		}
+
+		parseForm(t, r)
+		query := r.PostForm
+		if query.Get("type") != "SRV" {
+			t.Errorf("unexpected type: %s", query.Get("type"))
+		}
+		if query.Get("domain") != "_minecraft._tcp.example.com" {
+			t.Errorf("unexpected domain: %s", query.Get("domain"))
+		}
+		for param, expected := range map[string]string{"priority": "10", "weight": "5", "port": "25565", "target": "mc.example.com"} {
+			if query.Get(param) != expected {
+				t.Errorf("unexpected %s: %s", param, query.Get(param))
+			}
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"status": "ok",
+		})
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test-token")
+	srv := SRV{Priority: 10, Weight: 5, Port: 25565, Target: "mc.example.com"}
+	if err := client.AddSRVRecord(context.Background(), "example.com", "_minecraft._tcp.example.com", srv, 300); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestUpdateSRVRecord_Success(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/zones/records/update" {
+			t.Errorf("unexpected path: %s", r.URL.Path)
+		}
+
+		parseForm(t, r)
+		query := r.PostForm
+		for param, expected := range map[string]string{
+			"port":      "25565",
+			"target":    "mc.example.com",
+			"newPort":   "25566",
+			"newTarget": "mc2.example.com",
+		} {
+			if query.Get(param) != expected {
+				t.Errorf("unexpected %s: %s", param, query.Get(param))
+			}
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"status": "ok",
+		})
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test-token")
+	srv := SRV{Port: 25565, Target: "mc.example.com"}
+	newSRV := SRV{Port: 25566, Target: "mc2.example.com"}
+	if err := client.UpdateSRVRecord(context.Background(), "example.com", "_minecraft._tcp.example.com", srv, newSRV, 300); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestGetSRVRecords(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"status": "ok",
+			"response": map[string]interface{}{
+				"zone": mockZoneInfo("example.com"),
+				"records": []map[string]interface{}{
+					{
+						"name":  "_minecraft._tcp.example.com",
+						"type":  "SRV",
+						"ttl":   300,
+						"rData": map[string]interface{}{"priority": 0, "weight": 5, "port": 25565, "target": "MC.example.com."},
+					},
+					{
+						"name":  "_minecraft._tcp.example.com",
+						"type":  "TXT",
+						"ttl":   300,
+						"rData": map[string]interface{}{"text": "hello"},
+					},
+				},
+			},
+		})
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test-token")
+
+	records, err := client.GetSRVRecords(context.Background(), "example.com", "_minecraft._tcp.example.com")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(records) != 1 || records[0] != (SRV{Weight: 5, Port: 25565, Target: "mc.example.com"}) {
+		t.Errorf("unexpected SRV records: %+v", records)
+	}
+
+	all, err := client.GetRecords(context.Background(), "example.com", "_minecraft._tcp.example.com")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if v := all[0].Value(); v != "0 5 25565 mc.example.com" {
+		t.Errorf("expected canonical SRV value, got %q", v)
+	}
+}
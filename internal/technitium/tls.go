@@ -0,0 +1,78 @@
+package technitium
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// TLSOptions configures TLS for connections to a Technitium server. Certificates
+// and keys are PEM-encoded.
+type TLSOptions struct {
+	// CACert replaces the system roots with the certificates of this bundle.
+	CACert []byte
+	// ClientCert and ClientKey are presented to servers that require mutual TLS.
+	ClientCert []byte
+	ClientKey  []byte
+	// ServerName overrides the name the server certificate is verified against.
+	ServerName string
+	// InsecureSkipVerify accepts any server certificate. For testing only.
+	InsecureSkipVerify bool
+}
+
+// NewTLSConfig builds a TLS client configuration from opts.
+func NewTLSConfig(opts TLSOptions) (*tls.Config, error) {
+	cfg := &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		ServerName:         opts.ServerName,
+		InsecureSkipVerify: opts.InsecureSkipVerify,
+	}
+
+	if len(opts.CACert) > 0 {
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(opts.CACert) {
+			return nil, errors.New("CA bundle contains no PEM certificates")
+		}
+		cfg.RootCAs = pool
+	}
+
+	if len(opts.ClientCert) > 0 || len(opts.ClientKey) > 0 {
+		cert, err := tls.X509KeyPair(opts.ClientCert, opts.ClientKey)
+		if err != nil {
+			return nil, fmt.Errorf("loading client certificate: %w", err)
+		}
+		cfg.Certificates = []tls.Certificate{cert}
+	}
+
+	return cfg, nil
+}
+
+// WithTLSConfig uses cfg for HTTPS connections to the server. It applies to the
+// default HTTP client as well as one set with WithHTTPClient, whose transport is
+// copied rather than modified.
+func WithTLSConfig(cfg *tls.Config) ClientOption {
+	return func(c *Client) {
+		c.tlsConfig = cfg
+	}
+}
+
+// applyTLSConfig installs the client's TLS configuration on a copy of its HTTP
+// client and transport.
+func (c *Client) applyTLSConfig() {
+	if c.tlsConfig == nil {
+		return
+	}
+
+	transport, ok := c.httpClient.Transport.(*http.Transport)
+	if !ok || transport == nil {
+		transport = http.DefaultTransport.(*http.Transport)
+	}
+	transport = transport.Clone()
+	transport.TLSClientConfig = c.tlsConfig
+
+	httpClient := *c.httpClient
+	httpClient.Transport = transport
+	c.httpClient = &httpClient
+}
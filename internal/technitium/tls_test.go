@@ -0,0 +1,115 @@
+package technitium
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// newTLSServer starts a fake Technitium server over HTTPS and returns it with its
+// certificate and key in PEM form.
+func newTLSServer(t *testing.T) (*httptest.Server, []byte, []byte) {
+	t.Helper()
+
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"status":   "ok",
+			"response": map[string]interface{}{"zone": mockZoneInfo("example.com"), "records": []interface{}{}},
+		})
+	}))
+	t.Cleanup(server.Close)
+
+	cert := server.TLS.Certificates[0]
+	key, err := x509.MarshalPKCS8PrivateKey(cert.PrivateKey)
+	if err != nil {
+		t.Fatalf("marshaling key: %v", err)
+	}
+
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.Certificate[0]})
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: key})
+	return server, certPEM, keyPEM
+}
+
+func TestWithTLSConfig_CACert(t *testing.T) {
+	server, certPEM, _ := newTLSServer(t)
+
+	// The test certificate is issued for example.com and 127.0.0.1
+	tlsConfig, err := NewTLSConfig(TLSOptions{CACert: certPEM, ServerName: "example.com"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	client := NewClient(server.URL, "test-token", WithTLSConfig(tlsConfig))
+	if _, err := client.GetRecords(context.Background(), "example.com", "app.example.com"); err != nil {
+		t.Errorf("expected request trusting the CA to succeed, got %v", err)
+	}
+
+	untrusted := NewClient(server.URL, "test-token")
+	if _, err := untrusted.GetRecords(context.Background(), "example.com", "app.example.com"); err == nil {
+		t.Error("expected request without the CA to fail verification")
+	}
+
+	mismatched, err := NewTLSConfig(TLSOptions{CACert: certPEM, ServerName: "dns.example.net"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	client = NewClient(server.URL, "test-token", WithTLSConfig(mismatched))
+	if _, err := client.GetRecords(context.Background(), "example.com", "app.example.com"); err == nil {
+		t.Error("expected request verifying another server name to fail")
+	}
+}
+
+func TestWithTLSConfig_InsecureSkipVerify(t *testing.T) {
+	server, _, _ := newTLSServer(t)
+
+	tlsConfig, err := NewTLSConfig(TLSOptions{InsecureSkipVerify: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	client := NewClient(server.URL, "test-token", WithTLSConfig(tlsConfig))
+	if _, err := client.GetRecords(context.Background(), "example.com", "app.example.com"); err != nil {
+		t.Errorf("expected request to succeed, got %v", err)
+	}
+}
+
+func TestWithTLSConfig_KeepsHTTPClient(t *testing.T) {
+	httpClient := &http.Client{}
+	client := NewClient("https://dns.example.com", "test-token",
+		WithHTTPClient(httpClient),
+		WithTLSConfig(&tls.Config{ServerName: "dns.example.com"}),
+	)
+
+	if httpClient.Transport != nil {
+		t.Error("expected the caller's HTTP client not to be modified")
+	}
+	transport, ok := client.httpClient.Transport.(*http.Transport)
+	if !ok || transport.TLSClientConfig == nil || transport.TLSClientConfig.ServerName != "dns.example.com" {
+		t.Errorf("expected TLS configuration on the client transport, got %+v", client.httpClient.Transport)
+	}
+}
+
+func TestNewTLSConfig_ClientCertificate(t *testing.T) {
+	_, certPEM, keyPEM := newTLSServer(t)
+
+	tlsConfig, err := NewTLSConfig(TLSOptions{ClientCert: certPEM, ClientKey: keyPEM})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(tlsConfig.Certificates) != 1 {
+		t.Errorf("expected 1 client certificate, got %d", len(tlsConfig.Certificates))
+	}
+
+	if _, err := NewTLSConfig(TLSOptions{ClientCert: certPEM}); err == nil {
+		t.Error("expected error for a certificate without key")
+	}
+	if _, err := NewTLSConfig(TLSOptions{CACert: []byte("not a certificate")}); err == nil {
+		t.Error("expected error for a CA bundle without certificates")
+	}
+}
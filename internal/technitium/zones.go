@@ -0,0 +1,181 @@
+package technitium
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"strconv"
+)
+
+// nameServerTTL is the TTL of NS records written by CreatePrimaryZone.
+const nameServerTTL = 3600
+
+// ZoneOptions configures a zone created by CreatePrimaryZone. Zero values keep the
+// defaults Technitium assigns to a new zone.
+type ZoneOptions struct {
+	// SOA settings
+	PrimaryNameServer string
+	ResponsiblePerson string
+	Refresh           int
+	Retry             int
+	Expire            int
+	Minimum           int
+
+	// NameServers replace the zone's default NS records when set.
+	NameServers []string
+}
+
+// zonesResponse is the response from the zones/list endpoint.
+type zonesResponse struct {
+	Zones []Zone `json:"zones"`
+}
+
+// ListZones retrieves every zone on the server.
+func (c *Client) ListZones(ctx context.Context) ([]Zone, error) {
+	apiResp, err := c.doRequest(ctx, "/api/zones/list", nil)
+	if err != nil {
+		return nil, fmt.Errorf("listing zones: %w", err)
+	}
+
+	var zonesResp zonesResponse
+	if err := json.Unmarshal(apiResp.Response, &zonesResp); err != nil {
+		return nil, fmt.Errorf("parsing zones response: %w", err)
+	}
+
+	return zonesResp.Zones, nil
+}
+
+// CreatePrimaryZone creates a Primary zone and applies the SOA and NS settings in opts.
+func (c *Client) CreatePrimaryZone(ctx context.Context, zone string, opts ZoneOptions) error {
+	params := url.Values{}
+	params.Set("zone", zone)
+	params.Set("type", "Primary")
+
+	if _, err := c.doRequest(ctx, "/api/zones/create", params); err != nil {
+		return fmt.Errorf("creating zone %s: %w", zone, err)
+	}
+
+	c.logger.Info("created zone",
+		slog.String("zone", zone),
+	)
+
+	if err := c.updateSOA(ctx, zone, opts); err != nil {
+		return err
+	}
+	return c.replaceNameServers(ctx, zone, opts.NameServers)
+}
+
+// updateSOA merges the SOA settings in opts into the zone's SOA record.
+func (c *Client) updateSOA(ctx context.Context, zone string, opts ZoneOptions) error {
+	if opts.PrimaryNameServer == "" && opts.ResponsiblePerson == "" &&
+		opts.Refresh == 0 && opts.Retry == 0 && opts.Expire == 0 && opts.Minimum == 0 {
+		return nil
+	}
+
+	records, err := c.GetRecords(ctx, zone, zone)
+	if err != nil {
+		return fmt.Errorf("reading SOA of zone %s: %w", zone, err)
+	}
+
+	var soa *Record
+	for i := range records {
+		if records[i].Type == "SOA" {
+			soa = &records[i]
+			break
+		}
+	}
+	if soa == nil {
+		return fmt.Errorf("zone %s has no SOA record", zone)
+	}
+
+	rdata := soa.RData
+	if opts.PrimaryNameServer != "" {
+		rdata.PrimaryNameServer = opts.PrimaryNameServer
+	}
+	if opts.ResponsiblePerson != "" {
+		rdata.ResponsiblePerson = opts.ResponsiblePerson
+	}
+	for _, field := range []struct {
+		value  int
+		target *int
+	}{
+		{opts.Refresh, &rdata.Refresh},
+		{opts.Retry, &rdata.Retry},
+		{opts.Expire, &rdata.Expire},
+		{opts.Minimum, &rdata.Minimum},
+	} {
+		if field.value != 0 {
+			*field.target = field.value
+		}
+	}
+
+	data := url.Values{}
+	data.Set("primaryNameServer", rdata.PrimaryNameServer)
+	data.Set("responsiblePerson", rdata.ResponsiblePerson)
+	data.Set("serial", strconv.FormatUint(uint64(rdata.Serial), 10))
+	data.Set("refresh", strconv.Itoa(rdata.Refresh))
+	data.Set("retry", strconv.Itoa(rdata.Retry))
+	data.Set("expire", strconv.Itoa(rdata.Expire))
+	data.Set("minimum", strconv.Itoa(rdata.Minimum))
+
+	if err := c.updateRecord(ctx, zone, zone, "SOA", soa.TTL, data); err != nil {
+		return err
+	}
+
+	c.logger.Debug("updated SOA record",
+		slog.String("zone", zone),
+		slog.String("primary_name_server", rdata.PrimaryNameServer),
+	)
+
+	return nil
+}
+
+// replaceNameServers makes nameServers the zone's only NS records. New records are
+// added before old ones are removed so the zone always has a name server.
+func (c *Client) replaceNameServers(ctx context.Context, zone string, nameServers []string) error {
+	if len(nameServers) == 0 {
+		return nil
+	}
+
+	records, err := c.GetRecords(ctx, zone, zone)
+	if err != nil {
+		return fmt.Errorf("reading NS records of zone %s: %w", zone, err)
+	}
+
+	existing := make(map[string]struct{})
+	for _, rec := range records {
+		if rec.Type == "NS" {
+			existing[rec.Value()] = struct{}{}
+		}
+	}
+
+	wanted := make(map[string]struct{})
+	for _, ns := range nameServers {
+		ns = CanonicalName(ns)
+		wanted[ns] = struct{}{}
+		if _, ok := existing[ns]; ok {
+			continue
+		}
+		if err := c.addRecord(ctx, zone, zone, "NS", nameServerTTL, url.Values{"nameServer": {ns}}); err != nil {
+			return err
+		}
+	}
+
+	for ns := range existing {
+		if _, ok := wanted[ns]; ok {
+			continue
+		}
+		if err := c.deleteRecord(ctx, zone, zone, "NS", url.Values{"nameServer": {ns}}); err != nil {
+			return err
+		}
+	}
+
+	c.logger.Debug("set zone name servers",
+		slog.String("zone", zone),
+		slog.Any("name_servers", nameServers),
+	)
+
+	return nil
+}
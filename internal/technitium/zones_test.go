@@ -0,0 +1,169 @@
+package technitium
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+)
+
+func TestListZones(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/zones/list" {
+			t.Errorf("unexpected path: %s", r.URL.Path)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"status": "ok",
+			"response": map[string]interface{}{
+				"zones": []map[string]interface{}{
+					mockZoneInfo("example.com"),
+					{"name": "example.net", "type": "Secondary", "disabled": true},
+				},
+			},
+		})
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test-token")
+	zones, err := client.ListZones(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(zones) != 2 {
+		t.Fatalf("expected 2 zones, got %d", len(zones))
+	}
+	if zones[0].Name != "example.com" || zones[0].Type != "Primary" || zones[0].Disabled {
+		t.Errorf("unexpected zone: %+v", zones[0])
+	}
+	if zones[1].Name != "example.net" || !zones[1].Disabled {
+		t.Errorf("unexpected zone: %+v", zones[1])
+	}
+}
+
+func TestCreatePrimaryZone(t *testing.T) {
+	var mu sync.Mutex
+	var soaUpdate, nsAdded, nsDeleted []string
+	created := false
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		parseForm(t, r)
+		query := r.PostForm
+		resp := map[string]interface{}{"status": "ok"}
+
+		switch r.URL.Path {
+		case "/api/zones/create":
+			if query.Get("zone") != "example.com" || query.Get("type") != "Primary" {
+				t.Errorf("unexpected create parameters: %v", query)
+			}
+			created = true
+		case "/api/zones/records/get":
+			resp["response"] = map[string]interface{}{
+				"zone": mockZoneInfo("example.com"),
+				"records": []map[string]interface{}{
+					{
+						"name": "example.com",
+						"type": "SOA",
+						"ttl":  900,
+						"rData": map[string]interface{}{
+							"primaryNameServer": "dns.local",
+							"responsiblePerson": "hostadmin.local",
+							"serial":            1,
+							"refresh":           900,
+							"retry":             300,
+							"expire":            604800,
+							"minimum":           900,
+						},
+					},
+					{
+						"name":  "example.com",
+						"type":  "NS",
+						"ttl":   3600,
+						"rData": map[string]interface{}{"nameServer": "dns.local"},
+					},
+				},
+			}
+		case "/api/zones/records/update":
+			if query.Get("type") != "SOA" {
+				t.Errorf("unexpected update type: %s", query.Get("type"))
+			}
+			soaUpdate = []string{
+				query.Get("primaryNameServer"),
+				query.Get("responsiblePerson"),
+				query.Get("serial"),
+				query.Get("refresh"),
+				query.Get("minimum"),
+			}
+		case "/api/zones/records/add":
+			nsAdded = append(nsAdded, query.Get("nameServer"))
+		case "/api/zones/records/delete":
+			nsDeleted = append(nsDeleted, query.Get("nameServer"))
+		default:
+			t.Errorf("unexpected path: %s", r.URL.Path)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(resp)
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test-token")
+	err := client.CreatePrimaryZone(context.Background(), "example.com", ZoneOptions{
+		PrimaryNameServer: "ns1.example.com",
+		Refresh:           3600,
+		NameServers:       []string{"ns1.example.com", "NS2.example.com."},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !created {
+		t.Error("expected zone to be created")
+	}
+
+	// Unset fields keep the values of the zone's current SOA
+	expectedSOA := []string{"ns1.example.com", "hostadmin.local", "1", "3600", "900"}
+	if len(soaUpdate) != len(expectedSOA) {
+		t.Fatalf("expected SOA update, got %v", soaUpdate)
+	}
+	for i := range expectedSOA {
+		if soaUpdate[i] != expectedSOA[i] {
+			t.Errorf("SOA update = %v, want %v", soaUpdate, expectedSOA)
+			break
+		}
+	}
+
+	if len(nsAdded) != 2 || nsAdded[0] != "ns1.example.com" || nsAdded[1] != "ns2.example.com" {
+		t.Errorf("expected both name servers added, got %v", nsAdded)
+	}
+	if len(nsDeleted) != 1 || nsDeleted[0] != "dns.local" {
+		t.Errorf("expected default name server deleted, got %v", nsDeleted)
+	}
+}
+
+func TestCreatePrimaryZone_Defaults(t *testing.T) {
+	var paths []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{"status": "ok"})
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test-token")
+	if err := client.CreatePrimaryZone(context.Background(), "example.com", ZoneOptions{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Without SOA or NS settings only the zone itself is created
+	if len(paths) != 1 || paths[0] != "/api/zones/create" {
+		t.Errorf("expected a single create request, got %v", paths)
+	}
+}
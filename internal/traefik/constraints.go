@@ -0,0 +1,114 @@
+package traefik
+
+import (
+	"fmt"
+	"regexp"
+)
+
+// Constraints is a Docker provider constraints expression such as
+// Label(`traefik.scope`, `public`) && !LabelRegex(`env`, `dev-.*`). Traefik only
+// serves the routers of workloads whose labels match it.
+type Constraints struct {
+	expr    string
+	tree    ruleNode
+	regexps map[string]*regexp.Regexp
+}
+
+// ParseConstraints parses a constraints expression written with the Label and
+// LabelRegex matchers, combined with &&, || and !. An empty expression returns nil,
+// which matches every workload.
+func ParseConstraints(expr string) (*Constraints, error) {
+	tokens, err := tokenize(expr)
+	if err != nil {
+		return nil, err
+	}
+	if len(tokens) == 0 {
+		return nil, nil
+	}
+
+	rp := &ruleParser{tokens: tokens}
+	tree, err := rp.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if tok := rp.peek(); tok.kind != tokenEOF {
+		return nil, fmt.Errorf("unexpected %s at offset %d", tok, tok.pos)
+	}
+
+	c := &Constraints{expr: expr, tree: tree, regexps: make(map[string]*regexp.Regexp)}
+	if err := c.compile(tree); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// compile checks every matcher of the expression and compiles its regular expressions.
+func (c *Constraints) compile(n ruleNode) error {
+	switch n := n.(type) {
+	case *notNode:
+		return c.compile(n.expr)
+	case *binaryNode:
+		if err := c.compile(n.left); err != nil {
+			return err
+		}
+		return c.compile(n.right)
+	case *matcherNode:
+		switch n.name {
+		case "Label":
+		case "LabelRegex":
+			if len(n.args) == 2 {
+				re, err := regexp.Compile(n.args[1])
+				if err != nil {
+					return fmt.Errorf("LabelRegex(%s): %w", n.args[0], err)
+				}
+				c.regexps[n.args[1]] = re
+			}
+		default:
+			return fmt.Errorf("unsupported constraint %s; only Label and LabelRegex are allowed", n.name)
+		}
+		if len(n.args) != 2 {
+			return fmt.Errorf("%s requires a label name and a value", n.name)
+		}
+	}
+	return nil
+}
+
+// String returns the expression the constraints were parsed from.
+func (c *Constraints) String() string {
+	if c == nil {
+		return ""
+	}
+	return c.expr
+}
+
+// Match reports whether a workload's labels satisfy the constraints. Label matches a
+// label with exactly the given value, LabelRegex one whose value matches the regular
+// expression anywhere. Nil constraints match every workload.
+func (c *Constraints) Match(labels map[string]string) bool {
+	if c == nil {
+		return true
+	}
+	return c.eval(c.tree, labels)
+}
+
+func (c *Constraints) eval(n ruleNode, labels map[string]string) bool {
+	switch n := n.(type) {
+	case *notNode:
+		return !c.eval(n.expr, labels)
+	case *binaryNode:
+		if n.op == tokenAnd {
+			return c.eval(n.left, labels) && c.eval(n.right, labels)
+		}
+		return c.eval(n.left, labels) || c.eval(n.right, labels)
+	case *matcherNode:
+		value, ok := labels[n.args[0]]
+		if !ok {
+			return false
+		}
+		if n.name == "LabelRegex" {
+			return c.regexps[n.args[1]].MatchString(value)
+		}
+		return value == n.args[1]
+	}
+	return false
+}
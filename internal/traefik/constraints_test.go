@@ -0,0 +1,59 @@
+package traefik
+
+import "testing"
+
+func TestParseConstraints(t *testing.T) {
+	c, err := ParseConstraints("")
+	if err != nil || c != nil {
+		t.Fatalf("expected nil constraints for an empty expression, got %v, %v", c, err)
+	}
+	if !c.Match(map[string]string{}) {
+		t.Error("expected nil constraints to match every workload")
+	}
+
+	for _, expr := range []string{
+		"Label(`a`)",
+		"Label(`a`, `b`, `c`)",
+		"Host(`example.com`)",
+		"LabelRegex(`a`, `(`)",
+		"Label(`a`, `b`) &&",
+		"Label(`a`, `b`",
+	} {
+		if _, err := ParseConstraints(expr); err == nil {
+			t.Errorf("expected error for %q", expr)
+		}
+	}
+}
+
+func TestConstraints_Match(t *testing.T) {
+	tests := []struct {
+		expr   string
+		labels map[string]string
+		want   bool
+	}{
+		{"Label(`traefik.scope`, `public`)", map[string]string{"traefik.scope": "public"}, true},
+		{"Label(`traefik.scope`, `public`)", map[string]string{"traefik.scope": "internal"}, false},
+		{"Label(`traefik.scope`, `public`)", map[string]string{}, false},
+		{"!Label(`traefik.scope`, `internal`)", map[string]string{}, true},
+		{"LabelRegex(`env`, `^prod-`)", map[string]string{"env": "prod-eu"}, true},
+		{"LabelRegex(`env`, `prod`)", map[string]string{"env": "preprod"}, true},
+		{"LabelRegex(`env`, `^prod`)", map[string]string{"env": "preprod"}, false},
+		{"Label(`a`, `1`) && Label(`b`, `2`)", map[string]string{"a": "1"}, false},
+		{"Label(`a`, `1`) || Label(`b`, `2`)", map[string]string{"b": "2"}, true},
+		{"Label(`a`, `1`) && (Label(`b`, `2`) || !LabelRegex(`c`, `.`))", map[string]string{"a": "1"}, true},
+		{`Label("a", "1")`, map[string]string{"a": "1"}, true},
+	}
+
+	for _, tt := range tests {
+		c, err := ParseConstraints(tt.expr)
+		if err != nil {
+			t.Fatalf("ParseConstraints(%q): %v", tt.expr, err)
+		}
+		if got := c.Match(tt.labels); got != tt.want {
+			t.Errorf("%q.Match(%v) = %v, want %v", tt.expr, tt.labels, got, tt.want)
+		}
+		if c.String() != tt.expr {
+			t.Errorf("String() = %q, want %q", c.String(), tt.expr)
+		}
+	}
+}
@@ -2,21 +2,58 @@
 package traefik
 
 import (
+	"fmt"
 	"log/slog"
-	"regexp"
+	"sort"
+	"strconv"
 	"strings"
 )
 
-// hostRegex matches Host(`hostname`) patterns in Traefik router rules.
-// Captures the hostname inside the backticks.
-var hostRegex = regexp.MustCompile("Host\\(`([^`]+)`\\)")
+// enableLabel is the label exposing a workload to Traefik, or hiding it.
+const enableLabel = "traefik.enable"
 
 // routerRuleSuffix is the label suffix for Traefik router rules.
 const routerRuleSuffix = ".rule"
 
+// routerSyntaxSuffix is the label suffix selecting a router's rule syntax.
+const routerSyntaxSuffix = ".ruleSyntax"
+
+// hostsLabelPrefix and hostsLabelSuffix frame the companion label listing the
+// hostnames of a router whose rule names none, such as a UDP router or an
+// entrypoint-only TCP router: technitium-companion.udp.routers.NAME.hosts
+const (
+	hostsLabelPrefix = "technitium-companion."
+	hostsLabelSuffix = ".hosts"
+)
+
+// Protocol is the Traefik router protocol a hostname was found on.
+type Protocol string
+
+const (
+	ProtocolHTTP Protocol = "http"
+	ProtocolTCP  Protocol = "tcp"
+	ProtocolUDP  Protocol = "udp"
+)
+
+// ruleProtocols are the router protocols whose rules name hosts. UDP routers have no rule.
+var ruleProtocols = map[Protocol]struct{}{
+	ProtocolHTTP: {},
+	ProtocolTCP:  {},
+}
+
+// Route is a hostname together with the router it was extracted from.
+type Route struct {
+	Hostname string
+	Protocol Protocol
+	Router   string
+}
+
 // Parser extracts hostnames from Traefik labels.
 type Parser struct {
-	logger *slog.Logger
+	logger           *slog.Logger
+	syntax           Syntax
+	exposedByDefault bool
+	constraints      *Constraints
 }
 
 // ParserOption is a functional option for configuring the Parser.
@@ -29,10 +66,36 @@ func WithLogger(logger *slog.Logger) ParserOption {
 	}
 }
 
+// WithSyntax sets the rule syntax used for routers without a ruleSyntax label.
+// The default accepts both v2 and v3 rules.
+func WithSyntax(syntax Syntax) ParserOption {
+	return func(p *Parser) {
+		p.syntax = syntax
+	}
+}
+
+// WithExposedByDefault mirrors the exposedByDefault setting of Traefik's Docker
+// provider: when false, only workloads labelled traefik.enable=true are served.
+// The default, like Traefik's, is true.
+func WithExposedByDefault(exposed bool) ParserOption {
+	return func(p *Parser) {
+		p.exposedByDefault = exposed
+	}
+}
+
+// WithConstraints mirrors the constraints of Traefik's Docker provider: only
+// workloads whose labels match them are served.
+func WithConstraints(constraints *Constraints) ParserOption {
+	return func(p *Parser) {
+		p.constraints = constraints
+	}
+}
+
 // NewParser creates a new Traefik label parser.
 func NewParser(opts ...ParserOption) *Parser {
 	p := &Parser{
-		logger: slog.Default(),
+		logger:           slog.Default(),
+		exposedByDefault: true,
 	}
 
 	for _, opt := range opts {
@@ -42,96 +105,201 @@ func NewParser(opts ...ParserOption) *Parser {
 	return p
 }
 
-// ExtractHosts extracts all hostnames from Traefik labels.
-// It looks for traefik.http.routers.*.rule labels and extracts Host() values.
-// Returns a deduplicated slice of hostnames.
-func (p *Parser) ExtractHosts(labels map[string]string) []string {
+// Diagnostic describes part of a router rule that produced no hostname.
+type Diagnostic struct {
+	// Router is the name of the router the rule belongs to.
+	Router string
+	// Message explains why the rule, or part of it, was not published.
+	Message string
+}
+
+// Result is the outcome of parsing a workload's Traefik labels.
+type Result struct {
+	// Hosts are the deduplicated hostnames of all routers.
+	Hosts []string
+	// Routes record the protocol and router of every extracted hostname. A hostname
+	// served by several routers appears once per router.
+	Routes []Route
+	// Diagnostics report invalid rules and matchers that cannot be published.
+	Diagnostics []Diagnostic
+}
+
+// Parse extracts all hostnames from the traefik.http.routers.*.rule and
+// traefik.tcp.routers.*.rule labels of a workload, plus any hostnames mapped to
+// a router with a technitium-companion.<protocol>.routers.<name>.hosts label.
+// Labels are processed in key order so results are stable.
+func (p *Parser) Parse(labels map[string]string) Result {
+	var keys []string
+	for key := range labels {
+		if isRouterRuleLabel(key) || isRouterHostsLabel(key) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	var result Result
 	seen := make(map[string]struct{})
-	var hosts []string
+	seenRoutes := make(map[Route]struct{})
+
+	add := func(route Route) {
+		if _, ok := seenRoutes[route]; !ok {
+			seenRoutes[route] = struct{}{}
+			result.Routes = append(result.Routes, route)
+		}
+		// Deduplicate
+		if _, exists := seen[route.Hostname]; !exists {
+			seen[route.Hostname] = struct{}{}
+			result.Hosts = append(result.Hosts, route.Hostname)
+			p.logger.Debug("extracted hostname",
+				slog.String("hostname", route.Hostname),
+				slog.String("protocol", string(route.Protocol)),
+				slog.String("router", route.Router),
+			)
+		}
+	}
+
+	for _, key := range keys {
+		value := labels[key]
 
-	for key, value := range labels {
-		// Only process traefik router rule labels
-		if !isRouterRuleLabel(key) {
+		if isRouterHostsLabel(key) {
+			protocol, router := routerOf(key)
+			for _, hostname := range strings.Split(value, ",") {
+				hostname = strings.TrimSuffix(strings.ToLower(strings.TrimSpace(hostname)), ".")
+				if hostname != "" {
+					add(Route{Hostname: hostname, Protocol: protocol, Router: router})
+				}
+			}
 			continue
 		}
 
+		protocol, router := routerOf(key)
+
 		p.logger.Debug("parsing traefik rule",
 			slog.String("label", key),
 			slog.String("rule", value),
 		)
 
-		// Extract all Host() patterns from the rule
-		matches := hostRegex.FindAllStringSubmatch(value, -1)
-		for _, match := range matches {
-			if len(match) < 2 {
-				continue
-			}
-			hostname := strings.TrimSpace(match[1])
-			if hostname == "" {
-				continue
+		syntax := p.syntax
+		if v, ok := labels[strings.TrimSuffix(key, routerRuleSuffix)+routerSyntaxSuffix]; ok {
+			s, err := ParseSyntax(v)
+			if err != nil {
+				result.Diagnostics = append(result.Diagnostics, Diagnostic{Router: router, Message: err.Error()})
+			} else if s != SyntaxAuto {
+				syntax = s
 			}
+		}
 
-			// Deduplicate
-			if _, exists := seen[hostname]; !exists {
-				seen[hostname] = struct{}{}
-				hosts = append(hosts, hostname)
-				p.logger.Debug("extracted hostname",
-					slog.String("hostname", hostname),
-				)
-			}
+		rule, err := ParseRule(value, syntax)
+		if err != nil {
+			result.Diagnostics = append(result.Diagnostics, Diagnostic{
+				Router:  router,
+				Message: fmt.Sprintf("invalid rule: %v", err),
+			})
+			continue
+		}
+
+		for _, message := range rule.Diagnostics {
+			result.Diagnostics = append(result.Diagnostics, Diagnostic{Router: router, Message: message})
+		}
+
+		for _, hostname := range rule.Hosts {
+			add(Route{Hostname: hostname, Protocol: protocol, Router: router})
 		}
 	}
 
 	p.logger.Debug("extracted hosts from labels",
-		slog.Int("count", len(hosts)),
+		slog.Int("count", len(result.Hosts)),
+		slog.Int("diagnostics", len(result.Diagnostics)),
 	)
 
-	return hosts
+	return result
+}
+
+// Exposed reports whether Traefik's Docker provider serves the routers of a workload
+// with the given labels: its traefik.enable label, or exposedByDefault when it has
+// none, must enable it, and its labels must match the provider's constraints. An
+// invalid traefik.enable value returns an error; Traefik skips such workloads too.
+func (p *Parser) Exposed(labels map[string]string) (bool, error) {
+	enabled := p.exposedByDefault
+	if v, ok := labels[enableLabel]; ok {
+		b, err := strconv.ParseBool(strings.TrimSpace(v))
+		if err != nil {
+			return false, fmt.Errorf("%s must be true or false: %q", enableLabel, v)
+		}
+		enabled = b
+	}
+	if !enabled {
+		return false, nil
+	}
+
+	return p.constraints.Match(labels), nil
+}
+
+// ExtractHosts extracts all hostnames from Traefik labels.
+// It looks for traefik.http.routers.*.rule labels and extracts Host() values.
+// Returns a deduplicated slice of hostnames; see Parse for diagnostics.
+func (p *Parser) ExtractHosts(labels map[string]string) []string {
+	return p.Parse(labels).Hosts
+}
+
+// routerOf returns the protocol and router name of a <namespace>.<protocol>.routers.<name>.<option> label.
+func routerOf(key string) (Protocol, string) {
+	parts := strings.SplitN(key, ".", 4)
+	if len(parts) < 4 {
+		return "", ""
+	}
+	name, _, _ := strings.Cut(parts[3], ".")
+	return Protocol(parts[1]), name
 }
 
-// isRouterRuleLabel checks if a label key is a Traefik HTTP router rule.
+// isRouterRuleLabel checks if a label key is a Traefik HTTP or TCP router rule.
 // Matches patterns like: traefik.http.routers.myrouter.rule
 func isRouterRuleLabel(key string) bool {
-	// Must start with traefik.http.routers. and end with .rule
-	if !strings.HasPrefix(key, "traefik.http.routers.") {
-		return false
-	}
+	// Must end with .rule
 	if !strings.HasSuffix(key, routerRuleSuffix) {
 		return false
 	}
 
 	// Ensure there's a router name between routers. and .rule
-	// traefik.http.routers.NAME.rule
+	// traefik.PROTOCOL.routers.NAME.rule
 	parts := strings.Split(key, ".")
-	// Expected: [traefik, http, routers, NAME, rule]
-	if len(parts) < 5 {
+	// Expected: [traefik, PROTOCOL, routers, NAME, rule]
+	if len(parts) < 5 || parts[0] != "traefik" || parts[2] != "routers" {
 		return false
 	}
 
-	return true
+	_, ok := ruleProtocols[Protocol(parts[1])]
+	return ok
 }
 
-// ExtractHostsFromRule extracts all hostnames from a single Traefik rule string.
-// Useful for parsing rules directly without the full label map.
-func ExtractHostsFromRule(rule string) []string {
-	seen := make(map[string]struct{})
-	var hosts []string
+// isRouterHostsLabel checks if a label key maps hostnames to a router:
+// technitium-companion.PROTOCOL.routers.NAME.hosts
+func isRouterHostsLabel(key string) bool {
+	if !strings.HasPrefix(key, hostsLabelPrefix) || !strings.HasSuffix(key, hostsLabelSuffix) {
+		return false
+	}
 
-	matches := hostRegex.FindAllStringSubmatch(rule, -1)
-	for _, match := range matches {
-		if len(match) < 2 {
-			continue
-		}
-		hostname := strings.TrimSpace(match[1])
-		if hostname == "" {
-			continue
-		}
+	parts := strings.Split(strings.TrimPrefix(key, hostsLabelPrefix), ".")
+	// Expected: [PROTOCOL, routers, NAME, hosts]
+	if len(parts) != 4 || parts[1] != "routers" || parts[2] == "" {
+		return false
+	}
 
-		if _, exists := seen[hostname]; !exists {
-			seen[hostname] = struct{}{}
-			hosts = append(hosts, hostname)
-		}
+	switch Protocol(parts[0]) {
+	case ProtocolHTTP, ProtocolTCP, ProtocolUDP:
+		return true
+	default:
+		return false
 	}
+}
 
-	return hosts
+// ExtractHostsFromRule extracts all hostnames from a single Traefik rule string.
+// Useful for parsing rules directly without the full label map. Invalid rules
+// yield no hostnames.
+func ExtractHostsFromRule(rule string) []string {
+	result, err := ParseRule(rule, SyntaxAuto)
+	if err != nil {
+		return nil
+	}
+	return result.Hosts
 }
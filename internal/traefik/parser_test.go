@@ -225,6 +225,8 @@ func TestIsRouterRuleLabel(t *testing.T) {
 		{"traefik.http.routers.my_app_123.rule", true},
 		{"traefik.http.routers.a.rule", true},
 		{"traefik.http.routers..rule", true}, // Edge case: empty router name (still matches pattern)
+		{"traefik.tcp.routers.mydb.rule", true},
+		{"traefik.udp.routers.mydns.rule", false},
 		{"traefik.http.routers.myapp.tls", false},
 		{"traefik.http.routers.myapp.entrypoints", false},
 		{"traefik.http.services.myapp.loadbalancer.server.port", false},
@@ -294,21 +296,92 @@ func TestExtractHostsFromRule(t *testing.T) {
 	}
 }
 
-func TestExtractHosts_TCPRoutersIgnored(t *testing.T) {
+func TestParse_TCPRouters(t *testing.T) {
 	parser := NewParser()
 
 	labels := map[string]string{
-		"traefik.tcp.routers.mytcp.rule": "HostSNI(`tcp.example.com`)",
+		"traefik.tcp.routers.mytcp.rule":   "HostSNI(`tcp.example.com`)",
 		"traefik.http.routers.myhttp.rule": "Host(`http.example.com`)",
+		"traefik.udp.routers.myudp.rule":   "HostSNI(`udp.example.com`)",
 	}
 
-	hosts := parser.ExtractHosts(labels)
+	result := parser.Parse(labels)
 
-	// Should only get the HTTP host
-	if len(hosts) != 1 {
-		t.Fatalf("expected 1 host, got %d", len(hosts))
+	expected := []Route{
+		{Hostname: "http.example.com", Protocol: ProtocolHTTP, Router: "myhttp"},
+		{Hostname: "tcp.example.com", Protocol: ProtocolTCP, Router: "mytcp"},
+	}
+	if !reflect.DeepEqual(result.Routes, expected) {
+		t.Errorf("expected routes %+v, got %+v", expected, result.Routes)
+	}
+	if !reflect.DeepEqual(result.Hosts, []string{"http.example.com", "tcp.example.com"}) {
+		t.Errorf("unexpected hosts %v", result.Hosts)
+	}
+}
+
+func TestParse_HostsLabel(t *testing.T) {
+	parser := NewParser()
+
+	labels := map[string]string{
+		"technitium-companion.udp.routers.dns.hosts":   "DNS.example.com, ntp.example.com.",
+		"technitium-companion.tcp.routers.mqtt.hosts":  "mqtt.example.com",
+		"technitium-companion.sctp.routers.x.hosts":    "ignored.example.com",
+		"traefik.http.routers.web.rule":                "Host(`mqtt.example.com`)",
+		"technitium-companion.udp.routers.empty.hosts": " , ",
+	}
+
+	result := parser.Parse(labels)
+
+	expectedHosts := []string{"mqtt.example.com", "dns.example.com", "ntp.example.com"}
+	if !reflect.DeepEqual(result.Hosts, expectedHosts) {
+		t.Errorf("expected hosts %v, got %v", expectedHosts, result.Hosts)
+	}
+
+	expectedRoutes := []Route{
+		{Hostname: "mqtt.example.com", Protocol: ProtocolTCP, Router: "mqtt"},
+		{Hostname: "dns.example.com", Protocol: ProtocolUDP, Router: "dns"},
+		{Hostname: "ntp.example.com", Protocol: ProtocolUDP, Router: "dns"},
+		{Hostname: "mqtt.example.com", Protocol: ProtocolHTTP, Router: "web"},
 	}
-	if hosts[0] != "http.example.com" {
-		t.Errorf("expected http.example.com, got %s", hosts[0])
+	if !reflect.DeepEqual(result.Routes, expectedRoutes) {
+		t.Errorf("expected routes %+v, got %+v", expectedRoutes, result.Routes)
+	}
+}
+
+func TestExposed(t *testing.T) {
+	constraints, err := ParseConstraints("Label(`traefik.scope`, `public`)")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		parser *Parser
+		labels map[string]string
+		want   bool
+	}{
+		{"exposed by default", NewParser(), map[string]string{}, true},
+		{"disabled", NewParser(), map[string]string{"traefik.enable": "false"}, false},
+		{"not exposed by default", NewParser(WithExposedByDefault(false)), map[string]string{}, false},
+		{"enabled", NewParser(WithExposedByDefault(false)), map[string]string{"traefik.enable": "true"}, true},
+		{"matching constraints", NewParser(WithConstraints(constraints)), map[string]string{"traefik.scope": "public"}, true},
+		{"unmatched constraints", NewParser(WithConstraints(constraints)), map[string]string{"traefik.scope": "internal"}, false},
+		{"enabled with unmatched constraints", NewParser(WithConstraints(constraints)), map[string]string{"traefik.enable": "true"}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.parser.Exposed(tt.labels)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Exposed() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	if exposed, err := NewParser().Exposed(map[string]string{"traefik.enable": "maybe"}); err == nil || exposed {
+		t.Errorf("expected an invalid traefik.enable label to hide the workload with an error, got %v, %v", exposed, err)
 	}
 }
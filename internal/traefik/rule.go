@@ -0,0 +1,477 @@
+package traefik
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Syntax selects the Traefik rule syntax a rule is parsed with.
+type Syntax string
+
+const (
+	// SyntaxAuto accepts both v2 and v3 rules.
+	SyntaxAuto Syntax = ""
+	// SyntaxV2 is the Traefik v2 rule syntax, where Host and HostSNI accept several domains.
+	SyntaxV2 Syntax = "v2"
+	// SyntaxV3 is the Traefik v3 rule syntax, where every host matcher takes a single domain.
+	SyntaxV3 Syntax = "v3"
+)
+
+// ParseSyntax parses a ruleSyntax label value.
+func ParseSyntax(s string) (Syntax, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "", "auto":
+		return SyntaxAuto, nil
+	case "v2":
+		return SyntaxV2, nil
+	case "v3":
+		return SyntaxV3, nil
+	default:
+		return SyntaxAuto, fmt.Errorf("unknown rule syntax %q", s)
+	}
+}
+
+// IsWildcard reports whether a hostname is a wildcard such as *.apps.example.com.
+func IsWildcard(hostname string) bool {
+	return strings.HasPrefix(hostname, "*.")
+}
+
+// RuleHosts is the outcome of parsing a single router rule.
+type RuleHosts struct {
+	// Hosts are the hostnames the rule matches, lowercased and deduplicated.
+	// Wildcards such as *.apps.example.com are included; see IsWildcard.
+	Hosts []string
+	// Diagnostics describe matchers that cannot be published as DNS records,
+	// such as arbitrary HostRegexp patterns or negated Host matchers.
+	Diagnostics []string
+}
+
+// ParseRule parses a Traefik router rule and extracts every hostname from its Host,
+// HostHeader and HostSNI matchers, plus the wildcard equivalent of HostRegexp patterns
+// that match any single subdomain. A rule Traefik would reject returns an error and no
+// hostnames, since Traefik serves no route for it.
+func ParseRule(rule string, syntax Syntax) (RuleHosts, error) {
+	tokens, err := tokenize(rule)
+	if err != nil {
+		return RuleHosts{}, err
+	}
+	if len(tokens) == 0 {
+		return RuleHosts{}, nil
+	}
+
+	rp := &ruleParser{tokens: tokens}
+	tree, err := rp.parseOr()
+	if err != nil {
+		return RuleHosts{}, err
+	}
+	if tok := rp.peek(); tok.kind != tokenEOF {
+		return RuleHosts{}, fmt.Errorf("unexpected %s at offset %d", tok, tok.pos)
+	}
+
+	var result RuleHosts
+	seen := make(map[string]struct{})
+	if err := collectHosts(tree, false, syntax, seen, &result); err != nil {
+		return RuleHosts{}, err
+	}
+	return result, nil
+}
+
+// collectHosts walks a rule tree and records hostnames and diagnostics.
+// Matchers under a negation never contribute hostnames.
+func collectHosts(n ruleNode, negated bool, syntax Syntax, seen map[string]struct{}, result *RuleHosts) error {
+	switch n := n.(type) {
+	case *notNode:
+		return collectHosts(n.expr, !negated, syntax, seen, result)
+	case *binaryNode:
+		if err := collectHosts(n.left, negated, syntax, seen, result); err != nil {
+			return err
+		}
+		return collectHosts(n.right, negated, syntax, seen, result)
+	case *matcherNode:
+		return collectMatcher(n, negated, syntax, seen, result)
+	}
+	return nil
+}
+
+// collectMatcher records the hostnames of a single matcher call.
+func collectMatcher(m *matcherNode, negated bool, syntax Syntax, seen map[string]struct{}, result *RuleHosts) error {
+	isPattern := false
+	switch m.name {
+	case "Host", "HostSNI":
+	case "HostHeader":
+		// HostHeader was removed in v3
+		if syntax == SyntaxV3 {
+			return fmt.Errorf("HostHeader is not supported in v3 rule syntax")
+		}
+	case "HostRegexp", "HostSNIRegexp":
+		isPattern = true
+	default:
+		// Path, Header, Method and other matchers do not name hosts
+		return nil
+	}
+
+	if len(m.args) == 0 {
+		return fmt.Errorf("%s requires at least one domain", m.name)
+	}
+	if len(m.args) > 1 && syntax == SyntaxV3 {
+		return fmt.Errorf("%s takes a single domain in v3 rule syntax; set ruleSyntax=v2 to list several", m.name)
+	}
+
+	if negated {
+		result.Diagnostics = append(result.Diagnostics,
+			fmt.Sprintf("negated %s(%s) excludes hosts and is not published", m.name, strings.Join(m.args, ", ")))
+		return nil
+	}
+
+	for _, arg := range m.args {
+		hostname := strings.TrimSuffix(strings.ToLower(strings.TrimSpace(arg)), ".")
+		if hostname == "" {
+			continue
+		}
+		if isPattern {
+			wildcard, ok := wildcardFromPattern(strings.TrimSpace(arg))
+			if !ok {
+				result.Diagnostics = append(result.Diagnostics,
+					fmt.Sprintf("%s(%s) matches a pattern, not a hostname, and cannot be published", m.name, arg))
+				continue
+			}
+			hostname = wildcard
+		}
+		if m.name == "HostSNI" && hostname == "*" {
+			result.Diagnostics = append(result.Diagnostics, "HostSNI(`*`) matches every host and cannot be published")
+			continue
+		}
+		if strings.Contains(hostname, "*") && !(IsWildcard(hostname) && isDomain(hostname[2:])) {
+			result.Diagnostics = append(result.Diagnostics,
+				fmt.Sprintf("%s(%s) is not a valid wildcard; only a leading *. label can be published", m.name, arg))
+			continue
+		}
+		if _, ok := seen[hostname]; ok {
+			continue
+		}
+		seen[hostname] = struct{}{}
+		result.Hosts = append(result.Hosts, hostname)
+	}
+	return nil
+}
+
+// subdomainPattern matches regular expressions that accept any single subdomain label,
+// such as [a-z0-9-]+, \w+ or .+, optionally wrapped in a capture group.
+var subdomainPattern = regexp.MustCompile(`^(` + labelPattern + `|\((\?P<\w+>|\?:)?` + labelPattern + `\))$`)
+
+// labelPattern matches a character class, \w or . repeated with + or *.
+const labelPattern = `(\.|\\w|\[[^\]|()]+\])[+*]`
+
+// wildcardFromPattern returns the wildcard hostname equivalent to a HostRegexp pattern
+// whose first label matches any subdomain and whose remaining labels are literal, e.g.
+// {sub:[a-z]+}.apps.example.com (v2) or ^[a-z0-9-]+\.apps\.example\.com$ (v3).
+// Any other pattern, such as an alternation of names, returns false.
+func wildcardFromPattern(pattern string) (string, bool) {
+	var first, rest string
+	if strings.HasPrefix(pattern, "{") {
+		// v2: a {name} or {name:regexp} variable, then literal text
+		depth := 0
+		end := -1
+		for i, c := range pattern {
+			if c == '{' {
+				depth++
+			} else if c == '}' {
+				depth--
+				if depth == 0 {
+					end = i
+					break
+				}
+			}
+		}
+		if end < 0 || !strings.HasPrefix(pattern[end+1:], ".") {
+			return "", false
+		}
+		_, first, _ = strings.Cut(pattern[1:end], ":")
+		if first == "" {
+			first = "[^.]+"
+		}
+		rest = pattern[end+2:]
+	} else {
+		// v3: a Go regular expression with an escaped literal suffix
+		pattern = strings.TrimSuffix(strings.TrimPrefix(pattern, "^"), "$")
+		var suffix string
+		var ok bool
+		first, suffix, ok = strings.Cut(pattern, `\.`)
+		if !ok {
+			return "", false
+		}
+		rest = strings.ReplaceAll(suffix, `\.`, ".")
+	}
+
+	if !subdomainPattern.MatchString(first) {
+		return "", false
+	}
+	rest = strings.TrimSuffix(strings.ToLower(rest), ".")
+	if !isDomain(rest) {
+		return "", false
+	}
+	return "*." + rest, true
+}
+
+// isDomain reports whether s is a literal domain name of at least one label.
+func isDomain(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, label := range strings.Split(s, ".") {
+		if label == "" {
+			return false
+		}
+		for _, c := range label {
+			if !(c >= 'a' && c <= 'z' || c >= '0' && c <= '9' || c == '-' || c == '_') {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// tokenKind identifies the kind of a rule token.
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenIdent
+	tokenString
+	tokenLParen
+	tokenRParen
+	tokenComma
+	tokenAnd
+	tokenOr
+	tokenNot
+)
+
+// token is a lexical element of a rule.
+type token struct {
+	kind  tokenKind
+	value string
+	pos   int
+}
+
+func (t token) String() string {
+	switch t.kind {
+	case tokenEOF:
+		return "end of rule"
+	case tokenString:
+		return strconv.Quote(t.value)
+	default:
+		return "'" + t.value + "'"
+	}
+}
+
+// tokenize splits a rule into tokens. Strings may be quoted with backticks or
+// with double quotes, the latter supporting Go escape sequences.
+func tokenize(rule string) ([]token, error) {
+	var tokens []token
+	i := 0
+	for i < len(rule) {
+		c := rule[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case c == '(':
+			tokens = append(tokens, token{kind: tokenLParen, value: "(", pos: i})
+			i++
+		case c == ')':
+			tokens = append(tokens, token{kind: tokenRParen, value: ")", pos: i})
+			i++
+		case c == ',':
+			tokens = append(tokens, token{kind: tokenComma, value: ",", pos: i})
+			i++
+		case c == '!':
+			tokens = append(tokens, token{kind: tokenNot, value: "!", pos: i})
+			i++
+		case strings.HasPrefix(rule[i:], "&&"):
+			tokens = append(tokens, token{kind: tokenAnd, value: "&&", pos: i})
+			i += 2
+		case strings.HasPrefix(rule[i:], "||"):
+			tokens = append(tokens, token{kind: tokenOr, value: "||", pos: i})
+			i += 2
+		case c == '`':
+			end := strings.IndexByte(rule[i+1:], '`')
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated string at offset %d", i)
+			}
+			tokens = append(tokens, token{kind: tokenString, value: rule[i+1 : i+1+end], pos: i})
+			i += end + 2
+		case c == '"':
+			end := i + 1
+			for end < len(rule) && rule[end] != '"' {
+				if rule[end] == '\\' {
+					end++
+				}
+				end++
+			}
+			if end >= len(rule) {
+				return nil, fmt.Errorf("unterminated string at offset %d", i)
+			}
+			value, err := strconv.Unquote(rule[i : end+1])
+			if err != nil {
+				return nil, fmt.Errorf("invalid string at offset %d: %w", i, err)
+			}
+			tokens = append(tokens, token{kind: tokenString, value: value, pos: i})
+			i = end + 1
+		case isIdentByte(c):
+			start := i
+			for i < len(rule) && isIdentByte(rule[i]) {
+				i++
+			}
+			tokens = append(tokens, token{kind: tokenIdent, value: rule[start:i], pos: start})
+		default:
+			return nil, fmt.Errorf("unexpected character %q at offset %d", c, i)
+		}
+	}
+	return tokens, nil
+}
+
+// isIdentByte reports whether c may appear in a matcher name.
+func isIdentByte(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '_'
+}
+
+// ruleNode is a node of a parsed rule expression.
+type ruleNode interface{}
+
+// matcherNode is a matcher call such as Host(`example.com`).
+type matcherNode struct {
+	name string
+	args []string
+}
+
+// notNode negates its expression.
+type notNode struct {
+	expr ruleNode
+}
+
+// binaryNode combines two expressions with && or ||.
+type binaryNode struct {
+	op          tokenKind
+	left, right ruleNode
+}
+
+// ruleParser is a recursive-descent parser over rule tokens. && binds tighter than ||.
+type ruleParser struct {
+	tokens []token
+	pos    int
+}
+
+func (p *ruleParser) peek() token {
+	if p.pos >= len(p.tokens) {
+		end := 0
+		if len(p.tokens) > 0 {
+			last := p.tokens[len(p.tokens)-1]
+			end = last.pos + len(last.value)
+		}
+		return token{kind: tokenEOF, pos: end}
+	}
+	return p.tokens[p.pos]
+}
+
+func (p *ruleParser) next() token {
+	tok := p.peek()
+	if tok.kind != tokenEOF {
+		p.pos++
+	}
+	return tok
+}
+
+func (p *ruleParser) expect(kind tokenKind, what string) (token, error) {
+	tok := p.next()
+	if tok.kind != kind {
+		return tok, fmt.Errorf("expected %s at offset %d, got %s", what, tok.pos, tok)
+	}
+	return tok, nil
+}
+
+func (p *ruleParser) parseOr() (ruleNode, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek().kind == tokenOr {
+		p.next()
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = &binaryNode{op: tokenOr, left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *ruleParser) parseAnd() (ruleNode, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek().kind == tokenAnd {
+		p.next()
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = &binaryNode{op: tokenAnd, left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *ruleParser) parseUnary() (ruleNode, error) {
+	switch tok := p.peek(); tok.kind {
+	case tokenNot:
+		p.next()
+		expr, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &notNode{expr: expr}, nil
+	case tokenLParen:
+		p.next()
+		expr, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if _, err := p.expect(tokenRParen, "')'"); err != nil {
+			return nil, err
+		}
+		return expr, nil
+	case tokenIdent:
+		return p.parseMatcher()
+	default:
+		return nil, fmt.Errorf("expected matcher at offset %d, got %s", tok.pos, tok)
+	}
+}
+
+func (p *ruleParser) parseMatcher() (ruleNode, error) {
+	name := p.next()
+	if _, err := p.expect(tokenLParen, "'(' after "+name.value); err != nil {
+		return nil, err
+	}
+
+	m := &matcherNode{name: name.value}
+	if p.peek().kind == tokenRParen {
+		p.next()
+		return m, nil
+	}
+	for {
+		arg, err := p.expect(tokenString, "quoted argument")
+		if err != nil {
+			return nil, err
+		}
+		m.args = append(m.args, arg.value)
+
+		tok := p.next()
+		if tok.kind == tokenRParen {
+			return m, nil
+		}
+		if tok.kind != tokenComma {
+			return nil, fmt.Errorf("expected ',' or ')' at offset %d, got %s", tok.pos, tok)
+		}
+	}
+}
@@ -0,0 +1,183 @@
+package traefik
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseRule(t *testing.T) {
+	tests := []struct {
+		name        string
+		rule        string
+		syntax      Syntax
+		hosts       []string
+		diagnostics int
+	}{
+		{
+			name:  "v2 multi-argument host",
+			rule:  "Host(`a.example.com`, `b.example.com`)",
+			hosts: []string{"a.example.com", "b.example.com"},
+		},
+		{
+			name:  "double quoted strings and extra spaces",
+			rule:  `  Host( "app.example.com" )  ||Host("www.example.com")`,
+			hosts: []string{"app.example.com", "www.example.com"},
+		},
+		{
+			name:  "mixed case and trailing dot",
+			rule:  "Host(`App.Example.COM.`)",
+			hosts: []string{"app.example.com"},
+		},
+		{
+			name:  "nested and/or",
+			rule:  "(Host(`a.example.com`) && (PathPrefix(`/api`) || Method(`GET`))) || Host(`b.example.com`)",
+			hosts: []string{"a.example.com", "b.example.com"},
+		},
+		{
+			name:  "HostSNI",
+			rule:  "HostSNI(`db.example.com`)",
+			hosts: []string{"db.example.com"},
+		},
+		{
+			name:        "HostSNI catch-all",
+			rule:        "HostSNI(`*`)",
+			diagnostics: 1,
+		},
+		{
+			name:  "v2 HostHeader",
+			rule:  "HostHeader(`legacy.example.com`)",
+			hosts: []string{"legacy.example.com"},
+		},
+		{
+			name:        "HostRegexp is reported",
+			rule:        "HostRegexp(`^(app|web)\\.example\\.com$`) || Host(`app.example.com`)",
+			hosts:       []string{"app.example.com"},
+			diagnostics: 1,
+		},
+		{
+			name:  "wildcard host",
+			rule:  "Host(`*.Apps.example.com`)",
+			hosts: []string{"*.apps.example.com"},
+		},
+		{
+			name:        "wildcard in inner label is reported",
+			rule:        "Host(`app.*.example.com`) || Host(`app*.example.com`)",
+			diagnostics: 2,
+		},
+		{
+			name:  "v2 HostRegexp subdomain variable",
+			rule:  "HostRegexp(`{sub:[a-z]+}.apps.example.com`, `{name}.web.example.com`)",
+			hosts: []string{"*.apps.example.com", "*.web.example.com"},
+		},
+		{
+			name:   "v3 HostRegexp subdomain pattern",
+			rule:   "HostRegexp(`^[a-z0-9-]+\\.apps\\.example\\.com$`) || HostRegexp(`^(?P<sub>.+)\\.web\\.example\\.com$`)",
+			syntax: SyntaxV3,
+			hosts:  []string{"*.apps.example.com", "*.web.example.com"},
+		},
+		{
+			name:        "HostRegexp with pattern suffix is reported",
+			rule:        "HostRegexp(`{sub:[a-z]+}.{domain:[a-z]+}.com`)",
+			diagnostics: 1,
+		},
+		{
+			name:  "wildcard and regexp deduplicated",
+			rule:  "Host(`*.apps.example.com`) || HostRegexp(`{sub}.apps.example.com`)",
+			hosts: []string{"*.apps.example.com"},
+		},
+		{
+			name:        "negated host is not published",
+			rule:        "PathPrefix(`/`) && !Host(`internal.example.com`)",
+			diagnostics: 1,
+		},
+		{
+			name:  "double negation publishes",
+			rule:  "!!Host(`app.example.com`)",
+			hosts: []string{"app.example.com"},
+		},
+		{
+			name:  "duplicates removed",
+			rule:  "Host(`app.example.com`) || Host(`APP.example.com`)",
+			hosts: []string{"app.example.com"},
+		},
+		{
+			name:   "v3 single host",
+			rule:   "Host(`app.example.com`)",
+			syntax: SyntaxV3,
+			hosts:  []string{"app.example.com"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := ParseRule(tt.rule, tt.syntax)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(result.Hosts, tt.hosts) {
+				t.Errorf("hosts = %v, want %v", result.Hosts, tt.hosts)
+			}
+			if len(result.Diagnostics) != tt.diagnostics {
+				t.Errorf("expected %d diagnostics, got %v", tt.diagnostics, result.Diagnostics)
+			}
+		})
+	}
+}
+
+func TestParseRule_Invalid(t *testing.T) {
+	tests := []struct {
+		name   string
+		rule   string
+		syntax Syntax
+		errMsg string
+	}{
+		{"unbalanced parenthesis", "(Host(`a.example.com`)", SyntaxAuto, "expected ')'"},
+		{"unterminated string", "Host(`a.example.com)", SyntaxAuto, "unterminated string"},
+		{"missing operator", "Host(`a.example.com`) Host(`b.example.com`)", SyntaxAuto, "unexpected"},
+		{"dangling operator", "Host(`a.example.com`) &&", SyntaxAuto, "expected matcher"},
+		{"unquoted argument", "Host(example)", SyntaxAuto, "quoted argument"},
+		{"v3 multi-argument host", "Host(`a.example.com`, `b.example.com`)", SyntaxV3, "single domain"},
+		{"v3 HostHeader", "HostHeader(`a.example.com`)", SyntaxV3, "not supported"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := ParseRule(tt.rule, tt.syntax)
+			if err == nil {
+				t.Fatalf("expected error, got %+v", result)
+			}
+			if !strings.Contains(err.Error(), tt.errMsg) {
+				t.Errorf("expected error containing %q, got %v", tt.errMsg, err)
+			}
+		})
+	}
+}
+
+func TestParse_RuleSyntaxLabel(t *testing.T) {
+	parser := NewParser()
+
+	labels := map[string]string{
+		"traefik.http.routers.old.rule":       "Host(`a.example.com`, `b.example.com`)",
+		"traefik.http.routers.old.ruleSyntax": "v2",
+		"traefik.http.routers.new.rule":       "Host(`c.example.com`, `d.example.com`)",
+		"traefik.http.routers.new.ruleSyntax": "v3",
+		"traefik.http.routers.re.rule":        "HostRegexp(`^(a|b)\\.example\\.com$`)",
+	}
+
+	result := parser.Parse(labels)
+
+	expected := []string{"a.example.com", "b.example.com"}
+	if !reflect.DeepEqual(result.Hosts, expected) {
+		t.Errorf("expected %v, got %v", expected, result.Hosts)
+	}
+	if len(result.Diagnostics) != 2 {
+		t.Fatalf("expected 2 diagnostics, got %+v", result.Diagnostics)
+	}
+	if result.Diagnostics[0].Router != "new" || !strings.Contains(result.Diagnostics[0].Message, "invalid rule") {
+		t.Errorf("expected invalid rule diagnostic for router new, got %+v", result.Diagnostics[0])
+	}
+	if result.Diagnostics[1].Router != "re" {
+		t.Errorf("expected HostRegexp diagnostic for router re, got %+v", result.Diagnostics[1])
+	}
+}
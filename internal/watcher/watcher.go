@@ -5,6 +5,7 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
+	"sync"
 	"time"
 
 	"github.com/docker/docker/api/types/events"
@@ -26,12 +27,22 @@ type Watcher struct {
 	cfg        *config.Config
 	docker     *client.Client
 	dockerMode docker.Mode
+	host       string
 	parser     *traefik.Parser
 	reconciler *reconciler.Reconciler
 	logger     *slog.Logger
 
 	// Debounce settings to avoid reconciling too frequently
 	debounceInterval time.Duration
+	mu               sync.Mutex
+	pendingReconcile bool
+
+	// connect detects the Docker mode of a host that was unreachable at startup
+	connect func(ctx context.Context) (docker.Mode, error)
+
+	// Backoff between attempts to re-subscribe to a failed event stream
+	reconnectDelay    time.Duration
+	maxReconnectDelay time.Duration
 }
 
 // Option is a functional option for configuring the Watcher.
@@ -44,6 +55,14 @@ func WithLogger(logger *slog.Logger) Option {
 	}
 }
 
+// WithHost sets the name of the Docker host the watcher subscribes to, under which
+// its events are counted.
+func WithHost(name string) Option {
+	return func(w *Watcher) {
+		w.host = name
+	}
+}
+
 // WithDebounceInterval sets the debounce interval for event processing.
 // Events occurring within this interval will trigger a single reconciliation.
 func WithDebounceInterval(d time.Duration) Option {
@@ -52,6 +71,23 @@ func WithDebounceInterval(d time.Duration) Option {
 	}
 }
 
+// WithConnect sets the function used to (re)detect the Docker mode before
+// subscribing, so a host that was unreachable at startup is watched once it is back.
+func WithConnect(connect func(ctx context.Context) (docker.Mode, error)) Option {
+	return func(w *Watcher) {
+		w.connect = connect
+	}
+}
+
+// WithReconnectDelay sets the initial and maximum delay between attempts to
+// re-subscribe after the event stream fails. The delay doubles after each failure.
+func WithReconnectDelay(initial, max time.Duration) Option {
+	return func(w *Watcher) {
+		w.reconnectDelay = initial
+		w.maxReconnectDelay = max
+	}
+}
+
 // New creates a new Watcher.
 func New(
 	cfg *config.Config,
@@ -62,13 +98,16 @@ func New(
 	opts ...Option,
 ) *Watcher {
 	w := &Watcher{
-		cfg:              cfg,
-		docker:           dockerClient,
-		dockerMode:       dockerMode,
-		parser:           parser,
-		reconciler:       rec,
-		logger:           slog.Default(),
-		debounceInterval: 5 * time.Second, // Default debounce
+		cfg:               cfg,
+		docker:            dockerClient,
+		dockerMode:        dockerMode,
+		host:              config.DefaultDockerHostName,
+		parser:            parser,
+		reconciler:        rec,
+		logger:            slog.Default(),
+		debounceInterval:  5 * time.Second, // Default debounce
+		reconnectDelay:    time.Second,
+		maxReconnectDelay: time.Minute,
 	}
 
 	for _, opt := range opts {
@@ -79,8 +118,49 @@ func New(
 }
 
 // Watch starts watching for Docker events and triggers reconciliation.
-// This method blocks until the context is cancelled.
+// A failed event stream is re-subscribed with exponential backoff, followed by a
+// full reconciliation to pick up events missed in between, so one unhealthy host
+// never stops the others. This method blocks until the context is cancelled.
 func (w *Watcher) Watch(ctx context.Context) error {
+	delay := w.reconnectDelay
+	for {
+		received, err := w.watchStream(ctx)
+		if ctx.Err() != nil {
+			w.logger.Info("event watcher stopped")
+			return ctx.Err()
+		}
+		if received {
+			delay = w.reconnectDelay
+		}
+
+		w.logger.Warn("event stream error, reconnecting",
+			slog.String("error", err.Error()),
+			slog.Duration("retry_in", delay),
+		)
+		select {
+		case <-ctx.Done():
+			w.logger.Info("event watcher stopped")
+			return ctx.Err()
+		case <-time.After(delay):
+		}
+		delay = min(delay*2, w.maxReconnectDelay)
+
+		// Events may have been missed while disconnected
+		w.scheduleReconcile(ctx)
+	}
+}
+
+// watchStream subscribes to Docker events and handles them until the stream fails
+// or the context is cancelled. It reports whether any event was received.
+func (w *Watcher) watchStream(ctx context.Context) (bool, error) {
+	if w.connect != nil {
+		mode, err := w.connect(ctx)
+		if err != nil {
+			return false, err
+		}
+		w.dockerMode = mode
+	}
+
 	w.logger.Info("starting event watcher",
 		slog.String("mode", string(w.dockerMode)),
 		slog.Duration("debounce", w.debounceInterval),
@@ -94,53 +174,62 @@ func (w *Watcher) Watch(ctx context.Context) error {
 		Filters: filterArgs,
 	})
 
-	// Debounce channel for batching events
-	var debounceTimer *time.Timer
-	pendingReconcile := false
-
+	received := false
 	for {
 		select {
 		case <-ctx.Done():
-			if debounceTimer != nil {
-				debounceTimer.Stop()
-			}
-			w.logger.Info("event watcher stopped")
-			return ctx.Err()
+			return received, ctx.Err()
 
 		case err := <-errCh:
 			if err != nil {
-				w.logger.Error("event stream error",
-					slog.String("error", err.Error()),
-				)
-				return fmt.Errorf("event stream error: %w", err)
+				return received, fmt.Errorf("event stream error: %w", err)
 			}
 
 		case event := <-eventsCh:
+			received = true
 			w.handleEvent(ctx, event)
 
 			// Debounce: schedule a full reconciliation
-			if !pendingReconcile {
-				pendingReconcile = true
-				debounceTimer = time.AfterFunc(w.debounceInterval, func() {
-					w.logger.Debug("debounce timer fired, triggering full reconciliation")
-					result, err := w.reconciler.Reconcile(ctx)
-					if err != nil {
-						w.logger.Error("reconciliation failed",
-							slog.String("error", err.Error()),
-						)
-					} else {
-						w.logger.Info("reconciliation triggered by events",
-							slog.Int("records_created", result.RecordsCreated),
-							slog.Int("records_existed", result.RecordsExisted),
-						)
-					}
-					pendingReconcile = false
-				})
-			}
+			w.scheduleReconcile(ctx)
 		}
 	}
 }
 
+// scheduleReconcile triggers a full reconciliation after the debounce interval,
+// unless one is already pending.
+func (w *Watcher) scheduleReconcile(ctx context.Context) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.pendingReconcile || w.reconciler == nil {
+		return
+	}
+	w.pendingReconcile = true
+
+	time.AfterFunc(w.debounceInterval, func() {
+		w.mu.Lock()
+		w.pendingReconcile = false
+		w.mu.Unlock()
+		if ctx.Err() != nil {
+			return
+		}
+
+		w.logger.Debug("debounce timer fired, triggering full reconciliation")
+		result, err := w.reconciler.Reconcile(ctx)
+		if err != nil {
+			w.logger.Error("reconciliation failed",
+				slog.String("error", err.Error()),
+			)
+			return
+		}
+		w.logger.Info("reconciliation triggered by events",
+			slog.Int("records_created", result.RecordsCreated),
+			slog.Int("records_updated", result.RecordsUpdated),
+			slog.Int("records_existed", result.RecordsExisted),
+			slog.Int("records_deleted", result.RecordsDeleted),
+		)
+	})
+}
+
 // buildEventFilters creates Docker event filters based on the operating mode.
 func (w *Watcher) buildEventFilters() filters.Args {
 	f := filters.NewArgs()
@@ -165,7 +254,7 @@ func (w *Watcher) buildEventFilters() filters.Args {
 // handleEvent processes a single Docker event.
 func (w *Watcher) handleEvent(ctx context.Context, event events.Message) {
 	// Record the event metric
-	metrics.RecordDockerEvent(string(event.Type), string(event.Action))
+	metrics.RecordDockerEvent(w.host, string(event.Type), string(event.Action))
 
 	w.logger.Debug("received event",
 		slog.String("type", string(event.Type)),
@@ -201,12 +290,9 @@ func (w *Watcher) handleServiceEvent(ctx context.Context, event events.Message)
 		w.logger.Info("service removed",
 			slog.String("service", serviceName),
 		)
-		// Note: We don't auto-delete DNS records for removed services
-		// because orphan cleanup is disabled by design.
-		// DNS records are intentionally left until manually cleaned up.
-		w.logger.Debug("orphan cleanup disabled - DNS records not removed",
-			slog.String("service", serviceName),
-		)
+		// Records are only removed by the debounced full reconciliation, and only
+		// when orphan cleanup is enabled. Otherwise they are left for manual cleanup.
+		w.logOrphanCleanup("service", serviceName)
 	}
 }
 
@@ -228,12 +314,21 @@ func (w *Watcher) handleContainerEvent(ctx context.Context, event events.Message
 		w.logger.Info("container stopped/destroyed",
 			slog.String("container", containerName),
 		)
-		// Note: We don't auto-delete DNS records for stopped containers
-		// because orphan cleanup is disabled by design.
-		w.logger.Debug("orphan cleanup disabled - DNS records not removed",
-			slog.String("container", containerName),
+		w.logOrphanCleanup("container", containerName)
+	}
+}
+
+// logOrphanCleanup reports what will happen to the DNS records of a removed workload.
+func (w *Watcher) logOrphanCleanup(kind, name string) {
+	if w.cfg != nil && w.cfg.OrphanCleanup {
+		w.logger.Debug("orphan cleanup enabled - owned DNS records will be removed on next reconciliation",
+			slog.String(kind, name),
 		)
+		return
 	}
+	w.logger.Debug("orphan cleanup disabled - DNS records not removed",
+		slog.String(kind, name),
+	)
 }
 
 // WatchWithHandler starts watching for Docker events and calls a custom handler.
@@ -3,6 +3,7 @@ package watcher
 
 import (
 	"context"
+	"errors"
 	"log/slog"
 	"os"
 	"testing"
@@ -57,6 +58,9 @@ func TestNew(t *testing.T) {
 	if w.debounceInterval != 5*time.Second {
 		t.Errorf("expected default debounce 5s, got %v", w.debounceInterval)
 	}
+	if w.host != config.DefaultDockerHostName {
+		t.Errorf("expected default host %s, got %s", config.DefaultDockerHostName, w.host)
+	}
 }
 
 // TestNew_WithOptions verifies options are applied correctly.
@@ -69,6 +73,7 @@ func TestNew_WithOptions(t *testing.T) {
 		cfg, nil, docker.ModeStandalone, nil, nil,
 		WithLogger(logger),
 		WithDebounceInterval(debounce),
+		WithHost("edge1"),
 	)
 
 	if w.logger != logger {
@@ -77,6 +82,9 @@ func TestNew_WithOptions(t *testing.T) {
 	if w.debounceInterval != debounce {
 		t.Errorf("debounce option not applied: expected %v, got %v", debounce, w.debounceInterval)
 	}
+	if w.host != "edge1" {
+		t.Errorf("host option not applied: got %s", w.host)
+	}
 }
 
 // TestBuildEventFilters_Swarm verifies correct filters for Swarm mode.
@@ -302,3 +310,39 @@ func TestEventHandlerType(t *testing.T) {
 		t.Error("EventHandler should not be nil")
 	}
 }
+
+// TestWatch_RetriesUnreachableHost verifies a host that cannot be reached is retried
+// with backoff instead of stopping the watcher.
+func TestWatch_RetriesUnreachableHost(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	attempts := 0
+	connect := func(ctx context.Context) (docker.Mode, error) {
+		attempts++
+		if attempts == 3 {
+			cancel()
+		}
+		return "", errors.New("connection refused")
+	}
+
+	w := New(&config.Config{}, nil, "", nil, nil,
+		WithConnect(connect),
+		WithReconnectDelay(time.Millisecond, 2*time.Millisecond),
+	)
+
+	done := make(chan error, 1)
+	go func() { done <- w.Watch(ctx) }()
+
+	select {
+	case err := <-done:
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("expected context.Canceled, got %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("watcher did not stop after cancellation")
+	}
+	if attempts != 3 {
+		t.Errorf("expected 3 connection attempts, got %d", attempts)
+	}
+}